@@ -6,39 +6,126 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
 	crdclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
 	apiserviceclient "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset"
 	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/initializer"
-	"sigs.k8s.io/kube-storage-version-migrator/pkg/version"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/options"
 )
 
 const (
 	initializerUserAgent = "storage-version-migration-initializer"
 )
 
+// InitializerOptions are the command line options of the initializer.
+type InitializerOptions struct {
+	configFile                 string
+	includeGroups              []string
+	excludeGroups              []string
+	includeResources           []string
+	excludeResources           []string
+	includeCustomResources     bool
+	includeAggregatedResources bool
+	assumeMigrated             bool
+	dryRun                     bool
+	// fs tells which flags are set explicitly.
+	fs *flag.FlagSet
+}
+
+// NewInitializerOptions returns the default options of the initializer.
+func NewInitializerOptions() *InitializerOptions {
+	return &InitializerOptions{
+		excludeResources: initializer.DefaultOptions().ExcludeResources,
+	}
+}
+
+// AddFlags adds the flags of the options to fs.
+func (o *InitializerOptions) AddFlags(fs *flag.FlagSet) {
+	o.fs = fs
+	fs.StringVar(&o.configFile, "config", o.configFile, "path to a YAML file with the options of the initializer. The flags set explicitly override the file.")
+	fs.StringSliceVar(&o.includeGroups, "include-groups", o.includeGroups, "if not empty, only the resources of these groups are migrated. The core group is written as \"core\".")
+	fs.StringSliceVar(&o.excludeGroups, "exclude-groups", o.excludeGroups, "the groups whose resources are not migrated.")
+	fs.StringSliceVar(&o.includeResources, "include-resources", o.includeResources, "if not empty, only these resources are migrated. Resources are written as <resource>.<group>, or <resource> for the core group.")
+	fs.StringSliceVar(&o.excludeResources, "exclude-resources", o.excludeResources, "the resources that are not migrated. Exclusions take precedence over inclusions.")
+	fs.BoolVar(&o.includeCustomResources, "include-custom-resources", o.includeCustomResources, "if true, the resources of the groups defined by CRDs are migrated too.")
+	fs.BoolVar(&o.includeAggregatedResources, "include-aggregated-resources", o.includeAggregatedResources, "if true, the resources of the groups served by aggregated apiservers are migrated too.")
+	fs.BoolVar(&o.assumeMigrated, "assume-migrated", o.assumeMigrated, "if true, the storageStates created by the initializer record the resources as migrated, and no migration is created for them. Only use it on clusters created at the current version.")
+	fs.BoolVar(&o.dryRun, "dry-run", o.dryRun, "if true, print which storageStates would be created and which resources would be migrated, without installing the CRDs, or creating storageStates or migrations.")
+}
+
+// InitializerOptions builds the options of the initializer from the config
+// file and the flags.
+func (o *InitializerOptions) InitializerOptions() (initializer.Options, error) {
+	options := initializer.DefaultOptions()
+	if o.configFile != "" {
+		var err error
+		options, err = initializer.LoadOptionsFile(o.configFile)
+		if err != nil {
+			return options, err
+		}
+	}
+	changed := func(name string) bool {
+		return o.configFile == "" || o.fs.Changed(name)
+	}
+	if changed("include-groups") {
+		options.IncludeGroups = o.includeGroups
+	}
+	if changed("exclude-groups") {
+		options.ExcludeGroups = o.excludeGroups
+	}
+	if changed("include-resources") {
+		options.IncludeResources = o.includeResources
+	}
+	if changed("exclude-resources") {
+		options.ExcludeResources = o.excludeResources
+	}
+	if changed("include-custom-resources") {
+		options.IncludeCustomResources = o.includeCustomResources
+	}
+	if changed("include-aggregated-resources") {
+		options.IncludeAggregatedResources = o.includeAggregatedResources
+	}
+	if changed("assume-migrated") {
+		options.AssumeMigrated = o.assumeMigrated
+	}
+	if changed("dry-run") {
+		options.DryRun = o.dryRun
+	}
+	options.Out = os.Stdout
+	return options, nil
+}
+
 func NewInitializerCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:  "kube-storage-migrator-initializer",
-		Long: `The Kubernetes storage migrator initializer is a job that discovers resources that need migration and creates storageVersionMigration objects for such resources.`,
+	clientOptions := options.DefaultClientOptions()
+	initializerOptions := NewInitializerOptions()
+	cmd := &cobra.Command{
+		Use:   "kube-storage-migrator-initializer",
+		Short: "Installs the CRDs and creates the storageStates and the migrations of the resources",
+		Long:  `The Kubernetes storage migrator initializer is a job that discovers resources that need migration and creates storageVersionMigration objects for such resources.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := Run(context.TODO()); err != nil {
+			options.PrintFlags(cmd.Flags())
+			if err := Run(context.TODO(), clientOptions, initializerOptions); err != nil {
 				fmt.Fprintf(os.Stderr, "%v\n", err)
 				os.Exit(1)
 			}
 		},
 	}
+	clientOptions.AddFlags(cmd.Flags())
+	initializerOptions.AddFlags(cmd.Flags())
+	return cmd
 }
 
-func Run(ctx context.Context) error {
-	// creates the in-cluster config
-	config, err := rest.InClusterConfig()
+func Run(ctx context.Context, clientOptions *options.ClientOptions, initializerOptions *InitializerOptions) error {
+	options, err := initializerOptions.InitializerOptions()
+	if err != nil {
+		return err
+	}
+	config, err := clientOptions.Config(initializerUserAgent)
 	if err != nil {
 		return err
 	}
-	config.UserAgent = initializerUserAgent + "/" + version.VERSION
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return err
@@ -61,6 +148,7 @@ func Run(ctx context.Context) error {
 		apiservice.ApiregistrationV1().APIServices(),
 		clientset.CoreV1().Namespaces(),
 		migration.MigrationV1alpha1(),
+		options,
 	)
 	return init.Initialize(ctx)
 }
@@ -13,10 +13,6 @@ import (
 func main() {
 	klog.InitFlags(nil)
 	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
-	pflag.Parse()
-	pflag.VisitAll(func(flag *pflag.Flag) {
-		klog.V(4).Infof("FLAG: --%s=%q", flag.Name, flag.Value)
-	})
 	command := app.NewInitializerCommand()
 	if err := command.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
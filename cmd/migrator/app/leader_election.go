@@ -7,8 +7,9 @@ import (
 	"time"
 
 	flag "github.com/spf13/pflag"
-	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	configv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/config/v1alpha1"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection"
@@ -16,18 +17,77 @@ import (
 	"k8s.io/klog/v2"
 )
 
-var (
-	leaderElectionEnabled = flag.Bool("leader-election", false, "enable leader election.")
-	leaseHolderId         = flag.String("lease-holder-id", "", "lease lock holder identity name")
-	leaseLockName         = flag.String("lease-lock-name", "storage-version-migration-migrator-lock", "the lease lock resource name")
-	leaseLockNamespace    = flag.String("lease-lock-namespace", "", "the lease lock resource namespace")
-	leaseDuration         = flag.Duration("lease-duration", 137*time.Second, "how long to wait before forcefully attempting to acquire lock")
-	leaseRenewDeadline    = flag.Duration("lease-renew-deadline", 107*time.Second, "how long to wait before giving up trying to refresh a lease")
-	leaseRetryPeriod      = flag.Duration("lease-retry-period", 26*time.Second, "how long to wait between any lease actions")
-)
+// LeaderElectionOptions are the command line options of the leader
+// election.
+type LeaderElectionOptions struct {
+	enabled            bool
+	leaseHolderId      string
+	leaseLockName      string
+	leaseLockNamespace string
+	leaseDuration      time.Duration
+	leaseRenewDeadline time.Duration
+	leaseRetryPeriod   time.Duration
+	// fs tells which flags are set explicitly.
+	fs *flag.FlagSet
+}
+
+// NewLeaderElectionOptions returns the default leader election options,
+// using the lease named leaseLockName.
+func NewLeaderElectionOptions(leaseLockName string) *LeaderElectionOptions {
+	return &LeaderElectionOptions{
+		leaseLockName:      leaseLockName,
+		leaseDuration:      137 * time.Second,
+		leaseRenewDeadline: 107 * time.Second,
+		leaseRetryPeriod:   26 * time.Second,
+	}
+}
 
-func newResourceLock(config *rest.Config) (resourcelock.Interface, error) {
-	if len(*leaseHolderId) == 0 {
+// AddFlags adds the flags of the options to fs.
+func (o *LeaderElectionOptions) AddFlags(fs *flag.FlagSet) {
+	o.fs = fs
+	fs.BoolVar(&o.enabled, "leader-election", o.enabled, "enable leader election.")
+	fs.StringVar(&o.leaseHolderId, "lease-holder-id", o.leaseHolderId, "lease lock holder identity name")
+	fs.StringVar(&o.leaseLockName, "lease-lock-name", o.leaseLockName, "the lease lock resource name")
+	fs.StringVar(&o.leaseLockNamespace, "lease-lock-namespace", o.leaseLockNamespace, "the lease lock resource namespace")
+	fs.DurationVar(&o.leaseDuration, "lease-duration", o.leaseDuration, "how long to wait before forcefully attempting to acquire lock")
+	fs.DurationVar(&o.leaseRenewDeadline, "lease-renew-deadline", o.leaseRenewDeadline, "how long to wait before giving up trying to refresh a lease")
+	fs.DurationVar(&o.leaseRetryPeriod, "lease-retry-period", o.leaseRetryPeriod, "how long to wait between any lease actions")
+}
+
+// Configuration builds the leader election settings from the configuration
+// file c and the flags. The flags set explicitly override the file, and the
+// lease of the command is used if neither names one.
+func (o *LeaderElectionOptions) Configuration(c *configv1alpha1.MigratorConfiguration) configv1alpha1.LeaderElectionConfiguration {
+	changed := func(name string) bool {
+		return c == nil || o.fs.Changed(name)
+	}
+	le := configv1alpha1.LeaderElectionConfiguration{}
+	if c != nil {
+		le = c.LeaderElection
+	}
+	if changed("leader-election") {
+		le.LeaderElect = o.enabled
+	}
+	if changed("lease-lock-name") || le.ResourceName == "" {
+		le.ResourceName = o.leaseLockName
+	}
+	if changed("lease-lock-namespace") {
+		le.ResourceNamespace = o.leaseLockNamespace
+	}
+	if changed("lease-duration") {
+		le.LeaseDuration = metav1.Duration{Duration: o.leaseDuration}
+	}
+	if changed("lease-renew-deadline") {
+		le.RenewDeadline = metav1.Duration{Duration: o.leaseRenewDeadline}
+	}
+	if changed("lease-retry-period") {
+		le.RetryPeriod = metav1.Duration{Duration: o.leaseRetryPeriod}
+	}
+	return le
+}
+
+func (o *LeaderElectionOptions) newResourceLock(config *rest.Config, le configv1alpha1.LeaderElectionConfiguration) (resourcelock.Interface, error) {
+	if len(o.leaseHolderId) == 0 {
 		var i string
 		if i = os.Getenv("POD_NAME"); i == "" {
 			if hostname, err := os.Hostname(); err != nil {
@@ -36,33 +96,30 @@ func newResourceLock(config *rest.Config) (resourcelock.Interface, error) {
 				i = hostname + "_" + string(uuid.NewUUID())
 			}
 		}
-		leaseHolderId = &i
+		o.leaseHolderId = i
 	}
-	if len(*leaseLockNamespace) == 0 {
-		n, err := determineLeaseLockNamespace()
-		if err != nil {
-			return nil, fmt.Errorf("error determining lease lock namespace: %v", err)
-		}
-		leaseLockNamespace = &n
+	namespace, err := determineLeaseLockNamespace(le)
+	if err != nil {
+		return nil, fmt.Errorf("error determining lease lock namespace: %v", err)
 	}
 	lock, err := resourcelock.NewFromKubeconfig(
 		resourcelock.LeasesResourceLock,
-		*leaseLockNamespace,
-		*leaseLockName,
+		namespace,
+		le.ResourceName,
 		resourcelock.ResourceLockConfig{
-			Identity:      *leaseHolderId,
+			Identity:      o.leaseHolderId,
 			EventRecorder: nil,
 		},
 		config,
-		*leaseRenewDeadline,
+		le.RenewDeadline.Duration,
 	)
 	return lock, err
 }
 
-func determineLeaseLockNamespace() (string, error) {
+func determineLeaseLockNamespace(le configv1alpha1.LeaderElectionConfiguration) (string, error) {
 	// cli flag overrides all
-	if len(*leaseLockNamespace) > 0 {
-		return *leaseLockNamespace, nil
+	if len(le.ResourceNamespace) > 0 {
+		return le.ResourceNamespace, nil
 	}
 	// use the pod namespace from the env if available
 	if ns := os.Getenv("POD_NAMESPACE"); len(ns) > 0 {
@@ -71,12 +128,12 @@ func determineLeaseLockNamespace() (string, error) {
 	return "", fmt.Errorf("lease lock namespace must be provided explicitly (--lease-lock-namespace) or via POD_NAMESPACE env var")
 }
 
-func newLeaderElectionConfig(lock resourcelock.Interface) leaderelection.LeaderElectionConfig {
+func newLeaderElectionConfig(lock resourcelock.Interface, le configv1alpha1.LeaderElectionConfiguration) leaderelection.LeaderElectionConfig {
 	leaderElectionConfig := leaderelection.LeaderElectionConfig{
 		Lock:            lock,
-		LeaseDuration:   *leaseDuration,
-		RenewDeadline:   *leaseRenewDeadline,
-		RetryPeriod:     *leaseRetryPeriod,
+		LeaseDuration:   le.LeaseDuration.Duration,
+		RenewDeadline:   le.RenewDeadline.Duration,
+		RetryPeriod:     le.RetryPeriod.Duration,
 		ReleaseOnCancel: true,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStoppedLeading: func() {
@@ -84,18 +141,19 @@ func newLeaderElectionConfig(lock resourcelock.Interface) leaderelection.LeaderE
 				os.Exit(0)
 			},
 		},
-		Name: *leaseLockName,
+		Name: le.ResourceName,
 	}
 	return leaderElectionConfig
 }
 
-func runWithLeaderElection(ctx context.Context, config *rest.Config, c *controller.KubeMigrator) error {
-	lock, err := newResourceLock(config)
+// RunWithLeaderElection calls run once the process is elected leader.
+func (o *LeaderElectionOptions) RunWithLeaderElection(ctx context.Context, config *rest.Config, le configv1alpha1.LeaderElectionConfiguration, run func(ctx context.Context)) error {
+	lock, err := o.newResourceLock(config, le)
 	if err != nil {
 		return err
 	}
-	leaderElectionConfig := newLeaderElectionConfig(lock)
-	leaderElectionConfig.Callbacks.OnStartedLeading = func(ctx context.Context) { c.Run(ctx) }
+	leaderElectionConfig := newLeaderElectionConfig(lock, le)
+	leaderElectionConfig.Callbacks.OnStartedLeading = run
 	leaderelection.RunOrDie(ctx, leaderElectionConfig)
 	return nil
 }
@@ -1,90 +1,280 @@
 package app
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"log"
-	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
 	flag "github.com/spf13/pflag"
 
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	configv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/config/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/audit"
 	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/config"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/notification"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/options"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/schedule"
 )
 
 const (
 	migratorUserAgent = "storage-version-migration-migrator"
+	// DefaultLeaseLockName is the default name of the lease of the
+	// migrator leader election.
+	DefaultLeaseLockName = "storage-version-migration-migrator-lock"
 )
 
-var (
-	kubeconfigPath = flag.String("kubeconfig", "", "absolute path to the kubeconfig file specifying the apiserver instance. If unspecified, fallback to in-cluster configuration")
-	kubeAPIQPS     = flag.Float32("kube-api-qps", 40.0, "QPS to use while talking with kubernetes apiserver.")
-	kubeAPIBurst   = flag.Int("kube-api-burst", 1000, "Burst to use while talking with kubernetes apiserver.")
-)
+// MigratorOptions are the command line options of the migrator.
+type MigratorOptions struct {
+	configFile string
+}
+
+// NewMigratorOptions returns the default options of the migrator.
+func NewMigratorOptions() *MigratorOptions {
+	return &MigratorOptions{}
+}
+
+// AddFlags adds the flags of the options to fs. The path of the
+// configuration file is set by the configFlag flag.
+func (o *MigratorOptions) AddFlags(fs *flag.FlagSet, configFlag string) {
+	fs.StringVar(&o.configFile, configFlag, o.configFile, "path to a MigratorConfiguration file. The flags set explicitly override the file. The chunk size, the concurrency, the maintenance windows, the priority aging period, the notifications and the audit sinks are reloaded when the file changes.")
+}
+
+// Configuration loads the configuration file, or returns nil if there is
+// none.
+func (o *MigratorOptions) Configuration() (*configv1alpha1.MigratorConfiguration, error) {
+	if o.configFile == "" {
+		return nil, nil
+	}
+	return config.LoadMigratorConfiguration(o.configFile)
+}
+
+// migratorOptions converts the configuration file to the options of the
+// migrators.
+func migratorOptions(c *configv1alpha1.MigratorConfiguration) migrator.Options {
+	if c == nil {
+		return migrator.DefaultOptions()
+	}
+	return migrator.Options{
+		ChunkLimit:  c.ChunkSize,
+		Concurrency: int(c.Concurrency),
+	}
+}
+
+// maintenanceWindows converts the windows of the configuration file. The
+// configuration has been validated.
+func maintenanceWindows(c *configv1alpha1.MigratorConfiguration) schedule.Windows {
+	if c == nil {
+		return nil
+	}
+	var windows schedule.Windows
+	for _, w := range c.MaintenanceWindows {
+		window, err := schedule.NewWindow(w.Schedule, w.Duration.Duration, w.TimeZone)
+		if err != nil {
+			klog.Errorf("ignored the maintenance window %q: %v", w.Schedule, err)
+			continue
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// priorityAgingPeriod returns the aging period of the configuration file.
+func priorityAgingPeriod(c *configv1alpha1.MigratorConfiguration) time.Duration {
+	if c == nil || c.PriorityAgingPeriod == nil {
+		return controller.DefaultPriorityAgingPeriod
+	}
+	return c.PriorityAgingPeriod.Duration
+}
+
+// notifier converts the webhooks of the configuration file, reading their
+// secrets. It returns nil if there are no webhooks.
+func notifier(c *configv1alpha1.MigratorConfiguration) (*notification.Notifier, error) {
+	if c == nil || len(c.Notifications) == 0 {
+		return nil, nil
+	}
+	var webhooks []*notification.Webhook
+	for _, w := range c.Notifications {
+		var secret []byte
+		if w.SecretFile != "" {
+			var err error
+			secret, err = os.ReadFile(w.SecretFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read the secret of the webhook %s: %v", w.URL, err)
+			}
+			secret = bytes.TrimSpace(secret)
+		}
+		var outcomes []notification.Outcome
+		for _, e := range w.Events {
+			outcomes = append(outcomes, notification.Outcome(e))
+		}
+		webhook := notification.NewWebhook(w.URL, secret, outcomes...)
+		if w.Timeout != nil {
+			webhook.Timeout = w.Timeout.Duration
+		}
+		if w.MaxAttempts != nil {
+			webhook.MaxAttempts = int(*w.MaxAttempts)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	return notification.NewNotifier(webhooks...), nil
+}
+
+// auditSink converts the audit sinks of the configuration file. It returns
+// nil if there are no sinks.
+func auditSink(c *configv1alpha1.MigratorConfiguration, kube kubernetes.Interface) (audit.Sink, error) {
+	if c == nil || len(c.AuditSinks) == 0 {
+		return nil, nil
+	}
+	var sinks audit.Sinks
+	for _, s := range c.AuditSinks {
+		switch s.Type {
+		case "ConfigMap":
+			namespace := s.Namespace
+			if namespace == "" {
+				namespace = os.Getenv("POD_NAMESPACE")
+			}
+			if namespace == "" {
+				return nil, fmt.Errorf("the namespace of the ConfigMap audit sink must be set explicitly or via the POD_NAMESPACE env var")
+			}
+			sinks = append(sinks, audit.NewConfigMapSink(kube, namespace))
+		case "File":
+			sinks = append(sinks, audit.NewFileSink(s.Path))
+		case "Stdout":
+			sinks = append(sinks, audit.NewWriterSink(os.Stdout))
+		default:
+			return nil, fmt.Errorf("unsupported audit sink type %q", s.Type)
+		}
+	}
+	return sinks, nil
+}
+
+// NewKubeMigrator creates the migrator configured by c, and reloads its
+// options, maintenance windows, priority aging period, notifications and
+// audit sinks when the configuration file changes until ctx is done. If
+// migrationInformer is not nil, the migrator uses it instead of creating its
+// own informer.
+func (o *MigratorOptions) NewKubeMigrator(ctx context.Context, restConfig *rest.Config, c *configv1alpha1.MigratorConfiguration, migrationInformer cache.SharedIndexInformer) (*controller.KubeMigrator, error) {
+	dynamic, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	migration, err := migrationclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	kube, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	n, err := notifier(c)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := auditSink(c, kube)
+	if err != nil {
+		return nil, err
+	}
+	var km *controller.KubeMigrator
+	if migrationInformer != nil {
+		km = controller.NewKubeMigratorWithInformer(dynamic, migration, migrationInformer, migratorOptions(c))
+	} else {
+		km = controller.NewKubeMigrator(dynamic, migration, migratorOptions(c))
+	}
+	km.SetMaintenanceWindows(maintenanceWindows(c))
+	km.SetPriorityAgingPeriod(priorityAgingPeriod(c))
+	km.SetNotifier(n)
+	km.SetAuditSink(sink)
+	km.SetPreflight(kube.Discovery(), kube.AuthorizationV1().SelfSubjectAccessReviews())
+	if o.configFile != "" {
+		err := config.Watch(ctx, o.configFile, func() {
+			updated, err := config.LoadMigratorConfiguration(o.configFile)
+			if err != nil {
+				klog.Errorf("ignored the change of %s: %v", o.configFile, err)
+				return
+			}
+			n, err := notifier(updated)
+			if err != nil {
+				klog.Errorf("ignored the change of %s: %v", o.configFile, err)
+				return
+			}
+			sink, err := auditSink(updated, kube)
+			if err != nil {
+				klog.Errorf("ignored the change of %s: %v", o.configFile, err)
+				return
+			}
+			km.UpdateOptions(migratorOptions(updated))
+			km.SetMaintenanceWindows(maintenanceWindows(updated))
+			km.SetPriorityAgingPeriod(priorityAgingPeriod(updated))
+			km.SetNotifier(n)
+			km.SetAuditSink(sink)
+			klog.Infof("reloaded %s", o.configFile)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return km, nil
+}
 
 func NewMigratorCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:  "kube-storage-migrator",
-		Long: `The Kubernetes storage migrator migrates resources based on the StorageVersionMigrations APIs.`,
+	clientOptions := &options.ClientOptions{QPS: 40, Burst: 1000}
+	servingOptions := options.DefaultServingOptions(":2112")
+	migratorOptions := NewMigratorOptions()
+	leaderElectionOptions := NewLeaderElectionOptions(DefaultLeaseLockName)
+	cmd := &cobra.Command{
+		Use:   "kube-storage-migrator",
+		Short: "Migrates the resources of the storageVersionMigrations",
+		Long:  `The Kubernetes storage migrator migrates resources based on the StorageVersionMigrations APIs.`,
 		Run: func(cmd *cobra.Command, args []string) {
+			options.PrintFlags(cmd.Flags())
 			ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 			defer done() // give leader election a chance to release the lock
 
-			if err := Run(ctx); err != nil {
+			if err := Run(ctx, clientOptions, servingOptions, migratorOptions, leaderElectionOptions); err != nil {
 				fmt.Fprintf(os.Stderr, "%v\n", err)
 				done() // os.Exit does not call deferred functions
 				os.Exit(1)
 			}
 		},
 	}
+	clientOptions.AddFlags(cmd.Flags())
+	servingOptions.AddFlags(cmd.Flags())
+	migratorOptions.AddFlags(cmd.Flags(), "config")
+	leaderElectionOptions.AddFlags(cmd.Flags())
+	return cmd
 }
 
-func Run(ctx context.Context) error {
-	http.Handle("/metrics", promhttp.Handler())
-	livenessHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "ok")
-	})
-	http.HandleFunc("/healthz", livenessHandler)
-	go func() { http.ListenAndServe(":2112", nil) }()
-
-	var err error
-	var config *rest.Config
-	if *kubeconfigPath != "" {
-		config, err = clientcmd.BuildConfigFromFlags("", *kubeconfigPath)
-		if err != nil {
-			log.Fatalf("Error initializing client config: %v for kubeconfig: %v", err.Error(), *kubeconfigPath)
-		}
-	} else {
-		config, err = rest.InClusterConfig()
-		if err != nil {
-			return err
-		}
+func Run(ctx context.Context, clientOptions *options.ClientOptions, servingOptions *options.ServingOptions, migratorOptions *MigratorOptions, leaderElectionOptions *LeaderElectionOptions) error {
+	c, err := migratorOptions.Configuration()
+	if err != nil {
+		return err
+	}
+	if err := servingOptions.Serve(ctx); err != nil {
+		return err
 	}
-	config.QPS = *kubeAPIQPS
-	config.Burst = *kubeAPIBurst
-	dynamic, err := dynamic.NewForConfig(rest.AddUserAgent(config, migratorUserAgent))
+	restConfig, err := clientOptions.Config(migratorUserAgent)
 	if err != nil {
 		return err
 	}
-	migration, err := migrationclient.NewForConfig(config)
+	km, err := migratorOptions.NewKubeMigrator(ctx, restConfig, c, nil)
 	if err != nil {
 		return err
 	}
-	c := controller.NewKubeMigrator(
-		dynamic,
-		migration,
-	)
-	if *leaderElectionEnabled {
-		return runWithLeaderElection(ctx, config, c)
+	if le := leaderElectionOptions.Configuration(c); le.LeaderElect {
+		return leaderElectionOptions.RunWithLeaderElection(ctx, restConfig, le, km.Run)
 	}
-	c.Run(ctx)
+	km.Run(ctx)
 	return nil // reachable if signal cancels ctx
 }
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"k8s.io/client-go/discovery"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/initializer"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/options"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/rbac"
+)
+
+const generateRBACUserAgent = "storage-version-migration-generate-rbac"
+
+// GenerateRBACOptions are the command line options of the generate-rbac
+// subcommand.
+type GenerateRBACOptions struct {
+	name string
+	controller.ResourceRules
+}
+
+// NewGenerateRBACOptions returns the default options of the generate-rbac
+// subcommand. Like the initializer, it skips the events.
+func NewGenerateRBACOptions() *GenerateRBACOptions {
+	return &GenerateRBACOptions{
+		name:          rbac.DefaultRoleName,
+		ResourceRules: initializer.DefaultOptions().ResourceRules,
+	}
+}
+
+// AddFlags adds the flags of the options to fs.
+func (o *GenerateRBACOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.name, "name", o.name, "the name of the generated ClusterRole.")
+	fs.StringSliceVar(&o.IncludeGroups, "include-groups", o.IncludeGroups, "if not empty, only the resources of these groups are covered. The core group is written as \"core\".")
+	fs.StringSliceVar(&o.ExcludeGroups, "exclude-groups", o.ExcludeGroups, "the groups whose resources are not covered.")
+	fs.StringSliceVar(&o.IncludeResources, "include-resources", o.IncludeResources, "if not empty, only these resources are covered. Resources are written as <resource>.<group>, or <resource> for the core group.")
+	fs.StringSliceVar(&o.ExcludeResources, "exclude-resources", o.ExcludeResources, "the resources that are not covered. Exclusions take precedence over inclusions.")
+}
+
+// NewGenerateRBACCommand returns the command printing the least-privilege
+// ClusterRole of the migrator.
+func NewGenerateRBACCommand() *cobra.Command {
+	clientOptions := options.DefaultClientOptions()
+	generateOptions := NewGenerateRBACOptions()
+	cmd := &cobra.Command{
+		Use:   "generate-rbac",
+		Short: "Prints a minimal ClusterRole for the migrator",
+		Long: `Prints a ClusterRole granting the migrator get, list and update on
+		every resource currently migratable according to the discovery
+		document, and the permissions it needs to process the migrations.
+		It replaces the binding of the migrator to cluster-admin on the
+		clusters forbidding wildcard roles. It must be generated again when
+		resources are added to the cluster.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := RunGenerateRBAC(clientOptions, generateOptions, os.Stdout); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	clientOptions.AddFlags(cmd.Flags())
+	generateOptions.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// RunGenerateRBAC discovers the migratable resources and writes the
+// ClusterRole covering them to out.
+func RunGenerateRBAC(clientOptions *options.ClientOptions, generateOptions *GenerateRBACOptions, out io.Writer) error {
+	config, err := clientOptions.Config(generateRBACUserAgent)
+	if err != nil {
+		return err
+	}
+	client, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return err
+	}
+	resources, err := rbac.MigratableResources(client, controller.NewResourceFilter(generateOptions.ResourceRules))
+	if err != nil {
+		return err
+	}
+	data, err := rbac.MarshalClusterRole(rbac.MigratorClusterRole(generateOptions.name, resources))
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	initializerapp "sigs.k8s.io/kube-storage-version-migrator/cmd/initializer/app"
+	migratorapp "sigs.k8s.io/kube-storage-version-migrator/cmd/migrator/app"
+	triggerapp "sigs.k8s.io/kube-storage-version-migrator/cmd/trigger/app"
+	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/options"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/plan"
+)
+
+const (
+	allInOneUserAgent = "storage-version-migration"
+	// DefaultLeaseLockName is the default name of the lease of the
+	// all-in-one leader election.
+	DefaultLeaseLockName = "storage-version-migration-lock"
+)
+
+// NewStorageVersionMigratorCommand returns the command running the
+// components of the storage version migrator, either all in one process, or
+// one per subcommand.
+func NewStorageVersionMigratorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:  "storage-version-migrator",
+		Long: `The Kubernetes storage version migrator. The all-in-one subcommand runs the trigger and the migrator in one process, generate-rbac prints a minimal ClusterRole for the migrator, the other subcommands run a single component.`,
+	}
+	cmd.AddCommand(NewAllInOneCommand())
+	cmd.AddCommand(NewGenerateRBACCommand())
+	for use, sub := range map[string]*cobra.Command{
+		"trigger":     triggerapp.NewTriggerCommand(),
+		"migrator":    migratorapp.NewMigratorCommand(),
+		"initializer": initializerapp.NewInitializerCommand(),
+	} {
+		sub.Use = use
+		cmd.AddCommand(sub)
+	}
+	return cmd
+}
+
+// NewAllInOneCommand returns the command running the trigger and the
+// migrator in one process.
+func NewAllInOneCommand() *cobra.Command {
+	clientOptions := &options.ClientOptions{QPS: 40, Burst: 1000}
+	servingOptions := options.DefaultServingOptions(":2112")
+	triggerOptions := triggerapp.NewTriggerOptions()
+	migratorOptions := migratorapp.NewMigratorOptions()
+	leaderElectionOptions := migratorapp.NewLeaderElectionOptions(DefaultLeaseLockName)
+	cmd := &cobra.Command{
+		Use:   "all-in-one",
+		Short: "Runs the trigger and the migrator in one process",
+		Long: `Runs the trigger and the migrator in one process, sharing one
+		informer of the storageVersionMigrations, and behind a single leader
+		election.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.PrintFlags(cmd.Flags())
+			ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer done() // give leader election a chance to release the lock
+
+			if err := RunAllInOne(ctx, clientOptions, servingOptions, triggerOptions, migratorOptions, leaderElectionOptions); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				done() // os.Exit does not call deferred functions
+				os.Exit(1)
+			}
+		},
+	}
+	clientOptions.AddFlags(cmd.Flags())
+	servingOptions.AddFlags(cmd.Flags())
+	triggerOptions.AddFlags(cmd.Flags(), "trigger-config")
+	migratorOptions.AddFlags(cmd.Flags(), "migrator-config")
+	leaderElectionOptions.AddFlags(cmd.Flags())
+	return cmd
+}
+
+// RunAllInOne runs the trigger and the migrator until ctx is done. The
+// leader election settings of the migrator configuration file apply to both.
+func RunAllInOne(ctx context.Context, clientOptions *options.ClientOptions, servingOptions *options.ServingOptions, triggerOptions *triggerapp.TriggerOptions, migratorOptions *migratorapp.MigratorOptions, leaderElectionOptions *migratorapp.LeaderElectionOptions) error {
+	c, err := migratorOptions.Configuration()
+	if err != nil {
+		return err
+	}
+	if err := servingOptions.Serve(ctx); err != nil {
+		return err
+	}
+	restConfig, err := clientOptions.Config(allInOneUserAgent)
+	if err != nil {
+		return err
+	}
+	migration, err := migrationclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	factory := informer.NewSharedInformerFactory(migration, 0)
+	migrationInformer := factory.Migration().V1alpha1().StorageVersionMigrations().Informer()
+	if err := controller.AddMigrationIndexers(migrationInformer); err != nil {
+		return err
+	}
+	mt, err := triggerOptions.NewTrigger(ctx, restConfig, migrationInformer)
+	if err != nil {
+		return err
+	}
+	km, err := migratorOptions.NewKubeMigrator(ctx, restConfig, c, migrationInformer)
+	if err != nil {
+		return err
+	}
+	pc, err := plan.NewController(migration, migrationInformer)
+	if err != nil {
+		return err
+	}
+	run := func(ctx context.Context) {
+		factory.Start(ctx.Done())
+		go mt.Run(ctx)
+		go pc.Run(ctx)
+		km.Run(ctx)
+	}
+	if le := leaderElectionOptions.Configuration(c); le.LeaderElect {
+		return leaderElectionOptions.RunWithLeaderElection(ctx, restConfig, le, run)
+	}
+	run(ctx)
+	return nil // reachable if signal cancels ctx
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	goflag "flag"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/kube-storage-version-migrator/cmd/storage-version-migrator/app"
+)
+
+func main() {
+	klog.InitFlags(nil)
+	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
+	command := app.NewStorageVersionMigratorCommand()
+	if err := command.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -2,17 +2,28 @@ package app
 
 import (
 	"context"
-	"flag"
 	"fmt"
-	"log"
-	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	flag "github.com/spf13/pflag"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
 
+	configv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/config/v1alpha1"
 	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clusteroperator"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/config"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/options"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/plan"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/trigger"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/version"
 )
@@ -21,52 +32,252 @@ const (
 	triggerUserAgent = "storage-version-migration-trigger"
 )
 
-var (
-	kubeconfigPath = flag.String("kubeconfig", "", "absolute path to the kubeconfig file specifying the apiserver instance. If unspecified, fallback to in-cluster configuration")
-)
+// TriggerOptions are the command line options of the trigger.
+type TriggerOptions struct {
+	configFile               string
+	storageStateGracePeriod  time.Duration
+	migrationTTL             int
+	retryMaxAttempts         int
+	retryInitialBackoff      time.Duration
+	retryMaxBackoff          time.Duration
+	observeOnly              bool
+	recordEvents             bool
+	clusterOperator          string
+	clusterOperatorNamespace string
+	resourceRetryAttempts    resourceMaxAttempts
+	// fs tells which flags are set explicitly.
+	fs *flag.FlagSet
+}
+
+// NewTriggerOptions returns the default options of the trigger.
+func NewTriggerOptions() *TriggerOptions {
+	defaults := trigger.DefaultOptions()
+	return &TriggerOptions{
+		storageStateGracePeriod: defaults.StorageStateGracePeriod,
+		migrationTTL:            -1,
+		retryMaxAttempts:        int(defaults.RetryPolicy.MaxAttempts),
+		retryInitialBackoff:     defaults.RetryPolicy.InitialBackoff,
+		retryMaxBackoff:         defaults.RetryPolicy.MaxBackoff,
+		recordEvents:            true,
+		resourceRetryAttempts:   resourceMaxAttempts{},
+	}
+}
+
+// AddFlags adds the flags of the options to fs. The path of the
+// configuration file is set by the configFlag flag.
+func (o *TriggerOptions) AddFlags(fs *flag.FlagSet, configFlag string) {
+	o.fs = fs
+	fs.StringVar(&o.configFile, configFlag, o.configFile, "path to a TriggerConfiguration file. The flags set explicitly override the file. The file is reloaded when it changes.")
+	fs.DurationVar(&o.storageStateGracePeriod, "storage-state-grace-period", o.storageStateGracePeriod, "how long the resource of a storageState can be absent from the discovery document before the storageState and the migrations of the resource are deleted. 0 disables the garbage collection.")
+	fs.IntVar(&o.migrationTTL, "migration-ttl-seconds-after-finished", o.migrationTTL, "if not negative, the ttlSecondsAfterFinished of the migrations created by the trigger. Finished migrations are deleted once it passes.")
+	fs.IntVar(&o.retryMaxAttempts, "retry-max-attempts", o.retryMaxAttempts, "the number of consecutive failed migrations of a resource after which the trigger gives up migrating the resource. 0 means the trigger never gives up.")
+	fs.DurationVar(&o.retryInitialBackoff, "retry-initial-backoff", o.retryInitialBackoff, "the delay before retrying the migration of a resource after the first failure. The delay doubles after every following failure.")
+	fs.DurationVar(&o.retryMaxBackoff, "retry-max-backoff", o.retryMaxBackoff, "the maximum delay between retries of the migration of a resource.")
+	fs.BoolVar(&o.observeOnly, "observe-only", o.observeOnly, "if true, the trigger only keeps the storageStates up to date and reports the resources that need to be migrated, without creating, superseding or deleting migrations.")
+	fs.BoolVar(&o.recordEvents, "record-events", o.recordEvents, "if true, the trigger records events about the storageStates.")
+	fs.StringVar(&o.clusterOperator, "cluster-operator", o.clusterOperator, "if not empty, the name of the OpenShift ClusterOperator whose status the trigger maintains from the migration summary.")
+	fs.StringVar(&o.clusterOperatorNamespace, "cluster-operator-namespace", o.clusterOperatorNamespace, "the namespace of the migrator, listed in the related objects of the ClusterOperator.")
+	fs.Var(o.resourceRetryAttempts, "resource-retry-max-attempts", "comma separated <resource>.<group>=<attempts> pairs overriding --retry-max-attempts for specific resources, e.g. pods=10,deployments.apps=0. Can be repeated.")
+}
+
+// resourceMaxAttempts is a flag.Value holding the maximum retry attempts of
+// resources.
+type resourceMaxAttempts map[string]int32
+
+func (r resourceMaxAttempts) String() string {
+	var pairs []string
+	for resource, attempts := range r {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", resource, attempts))
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (r resourceMaxAttempts) Type() string {
+	return "resourceMaxAttempts"
+}
+
+func (r resourceMaxAttempts) Set(value string) error {
+	for _, pair := range strings.Split(value, ",") {
+		if len(pair) == 0 {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || len(kv[0]) == 0 {
+			return fmt.Errorf("expected <resource>.<group>=<attempts>, got %q", pair)
+		}
+		attempts, err := strconv.ParseInt(kv[1], 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid attempts in %q: %v", pair, err)
+		}
+		r[kv[0]] = int32(attempts)
+	}
+	return nil
+}
 
 func NewTriggerCommand() *cobra.Command {
-	return &cobra.Command{
-		Use: "kube-storage-migrator-trigger",
+	clientOptions := options.DefaultClientOptions()
+	servingOptions := options.DefaultServingOptions(":2113")
+	triggerOptions := NewTriggerOptions()
+	cmd := &cobra.Command{
+		Use:   "kube-storage-migrator-trigger",
+		Short: "Creates storageVersionMigrations when storage versions change",
 		Long: `The Kubernetes storage migrator triggering controller
 		detects storage version changes and creates migration requests.
 		It also records the status of the storage via the storageState
 		API.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			if err := Run(context.TODO()); err != nil {
+			options.PrintFlags(cmd.Flags())
+			if err := Run(context.TODO(), clientOptions, servingOptions, triggerOptions); err != nil {
 				fmt.Fprintf(os.Stderr, "%v\n", err)
 				os.Exit(1)
 			}
 		},
 	}
+	clientOptions.AddFlags(cmd.Flags())
+	servingOptions.AddFlags(cmd.Flags())
+	triggerOptions.AddFlags(cmd.Flags(), "config")
+	return cmd
+}
+
+// optionsFromConfiguration converts the configuration file to the options of
+// the trigger.
+func optionsFromConfiguration(c *configv1alpha1.TriggerConfiguration) (trigger.Options, map[string]int32) {
+	options := trigger.DefaultOptions()
+	options.DiscoveryPeriod = c.DiscoveryPeriod.Duration
+	options.StaleHeartbeatMultiplier = c.StaleHeartbeatMultiplier
+	options.StorageStateGracePeriod = c.StorageStateGracePeriod.Duration
+	options.MigrationTTLSecondsAfterFinished = c.MigrationTTLSecondsAfterFinished
+	options.RetryPolicy = trigger.RetryPolicy{
+		MaxAttempts:    *c.RetryPolicy.MaxAttempts,
+		InitialBackoff: c.RetryPolicy.InitialBackoff.Duration,
+		MaxBackoff:     c.RetryPolicy.MaxBackoff.Duration,
+	}
+	options.ObserveOnly = c.ObserveOnly
+	options.ResourceRules = controller.ResourceRules{
+		IncludeGroups:    c.Resources.IncludeGroups,
+		ExcludeGroups:    c.Resources.ExcludeGroups,
+		IncludeResources: c.Resources.IncludeResources,
+		ExcludeResources: c.Resources.ExcludeResources,
+	}
+	return options, c.ResourceRetryMaxAttempts
+}
+
+// TriggerOptions builds the options of the trigger from the configuration
+// file and the flags. The flags set explicitly override the file.
+func (o *TriggerOptions) TriggerOptions() (trigger.Options, error) {
+	options := trigger.DefaultOptions()
+	resourceAttempts := map[string]int32{}
+	if o.configFile != "" {
+		c, err := config.LoadTriggerConfiguration(o.configFile)
+		if err != nil {
+			return options, err
+		}
+		options, resourceAttempts = optionsFromConfiguration(c)
+	}
+	changed := func(name string) bool {
+		return o.configFile == "" || o.fs.Changed(name)
+	}
+	if changed("storage-state-grace-period") {
+		options.StorageStateGracePeriod = o.storageStateGracePeriod
+	}
+	if changed("migration-ttl-seconds-after-finished") && o.migrationTTL >= 0 {
+		ttl := int32(o.migrationTTL)
+		options.MigrationTTLSecondsAfterFinished = &ttl
+	}
+	if changed("retry-max-attempts") {
+		options.RetryPolicy.MaxAttempts = int32(o.retryMaxAttempts)
+	}
+	if changed("retry-initial-backoff") {
+		options.RetryPolicy.InitialBackoff = o.retryInitialBackoff
+	}
+	if changed("retry-max-backoff") {
+		options.RetryPolicy.MaxBackoff = o.retryMaxBackoff
+	}
+	if changed("observe-only") {
+		options.ObserveOnly = o.observeOnly
+	}
+	for resource, attempts := range o.resourceRetryAttempts {
+		resourceAttempts[resource] = attempts
+	}
+	options.ResourceRetryPolicies = map[string]trigger.RetryPolicy{}
+	for resource, attempts := range resourceAttempts {
+		policy := options.RetryPolicy
+		policy.MaxAttempts = attempts
+		options.ResourceRetryPolicies[resource] = policy
+	}
+	return options, nil
 }
 
-func Run(ctx context.Context) error {
-	livenessHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "ok")
-	})
-	http.HandleFunc("/healthz", livenessHandler)
-	go func() { http.ListenAndServe(":2113", nil) }()
-
-	var err error
-	var config *rest.Config
-	if *kubeconfigPath != "" {
-		config, err = clientcmd.BuildConfigFromFlags("", *kubeconfigPath)
+// NewTrigger creates the trigger, and reloads its options when the
+// configuration file changes until ctx is done. If migrationInformer is not
+// nil, the trigger uses it instead of creating its own informer.
+func (o *TriggerOptions) NewTrigger(ctx context.Context, restConfig *rest.Config, migrationInformer cache.SharedIndexInformer) (*trigger.MigrationTrigger, error) {
+	options, err := o.TriggerOptions()
+	if err != nil {
+		return nil, err
+	}
+	migration, err := migrationclient.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	if o.recordEvents {
+		kube, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			return nil, err
+		}
+		options.EventRecorder = trigger.NewEventRecorder(kube.CoreV1(), triggerUserAgent)
+	}
+	if o.clusterOperator != "" {
+		dynamic, err := dynamic.NewForConfig(restConfig)
 		if err != nil {
-			log.Fatalf("Error initializing client config: %v for kubeconfig: %v", err.Error(), *kubeconfigPath)
+			return nil, err
 		}
-	} else {
-		config, err = rest.InClusterConfig()
+		options.StatusReporter = clusteroperator.NewReporter(dynamic, o.clusterOperator, o.clusterOperatorNamespace, version.VERSION)
+	}
+	options.MigrationInformer = migrationInformer
+	c := trigger.NewMigrationTrigger(migration, options)
+	if o.configFile != "" {
+		err := config.Watch(ctx, o.configFile, func() {
+			updated, err := o.TriggerOptions()
+			if err != nil {
+				klog.Errorf("ignored the change of %s: %v", o.configFile, err)
+				return
+			}
+			updated.EventRecorder = options.EventRecorder
+			updated.StatusReporter = options.StatusReporter
+			updated.MigrationInformer = options.MigrationInformer
+			c.UpdateOptions(updated)
+			klog.Infof("reloaded %s", o.configFile)
+		})
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	config.UserAgent = triggerUserAgent + "/" + version.VERSION
-	migration, err := migrationclient.NewForConfig(config)
+	return c, nil
+}
+
+func Run(ctx context.Context, clientOptions *options.ClientOptions, servingOptions *options.ServingOptions, triggerOptions *TriggerOptions) error {
+	if err := servingOptions.Serve(ctx); err != nil {
+		return err
+	}
+	restConfig, err := clientOptions.Config(triggerUserAgent)
+	if err != nil {
+		return err
+	}
+	c, err := triggerOptions.NewTrigger(ctx, restConfig, nil)
+	if err != nil {
+		return err
+	}
+	migration, err := migrationclient.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	pc, err := plan.NewController(migration, nil)
 	if err != nil {
 		return err
 	}
-	c := trigger.NewMigrationTrigger(migration)
+	go pc.Run(ctx)
 	c.Run(ctx)
 	panic("unreachable")
 }
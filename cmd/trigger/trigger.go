@@ -13,11 +13,6 @@ import (
 func main() {
 	klog.InitFlags(nil)
 	pflag.CommandLine.AddGoFlagSet(goflag.CommandLine)
-	pflag.Parse()
-	pflag.VisitAll(func(flag *pflag.Flag) {
-		klog.V(4).Infof("FLAG: --%s=%q", flag.Name, flag.Value)
-		fmt.Printf("FLAG: --%s=%q\n", flag.Name, flag.Value)
-	})
 	command := app.NewTriggerCommand()
 	if err := command.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
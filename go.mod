@@ -3,6 +3,7 @@ module sigs.k8s.io/kube-storage-version-migrator
 go 1.24.0
 
 require (
+	github.com/fsnotify/fsnotify v1.6.0
 	github.com/onsi/ginkgo v1.16.5
 	github.com/openshift/build-machinery-go v0.0.0-20250602125535-1b6d00b8c37c
 	github.com/prometheus/client_golang v1.14.0
@@ -16,7 +17,9 @@ require (
 	k8s.io/code-generator v0.27.4
 	k8s.io/klog/v2 v2.90.1
 	k8s.io/kube-aggregator v0.27.4
+	k8s.io/utils v0.0.0-20230209194617-a36077c30491
 	sigs.k8s.io/controller-tools v0.12.0
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -26,7 +29,6 @@ require (
 	github.com/emicklei/go-restful/v3 v3.9.0 // indirect
 	github.com/evanphx/json-patch v4.12.0+incompatible // indirect
 	github.com/fatih/color v1.15.0 // indirect
-	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/go-logr/logr v1.2.3 // indirect
 	github.com/go-openapi/jsonpointer v0.19.6 // indirect
 	github.com/go-openapi/jsonreference v0.20.1 // indirect
@@ -69,8 +71,6 @@ require (
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 	k8s.io/gengo v0.0.0-20220902162205-c0856e24416d // indirect
 	k8s.io/kube-openapi v0.0.0-20230501164219-8b0f38b5fd1f // indirect
-	k8s.io/utils v0.0.0-20230209194617-a36077c30491 // indirect
 	sigs.k8s.io/json v0.0.0-20221116044647-bc3834ca7abd // indirect
 	sigs.k8s.io/structured-merge-diff/v4 v4.2.3 // indirect
-	sigs.k8s.io/yaml v1.3.0 // indirect
 )
@@ -0,0 +1,28 @@
+#!/usr/bin/env bash
+
+# Copyright 2026 The Kubernetes Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Writes the manifests of the CRDs from the CRDs installed by the
+# initializer, defined in pkg/initializer. TestCRDManifestsInSync fails
+# until this script is run after the CRDs change.
+
+set -o errexit
+set -o nounset
+set -o pipefail
+
+THIS_REPO_ABSOLUTE="$(cd "$(dirname "${BASH_SOURCE}")/.." && pwd -P)"
+
+cd "${THIS_REPO_ABSOLUTE}"
+go run ./hack/crd-manifests "${THIS_REPO_ABSOLUTE}/manifests"
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&TriggerConfiguration{}, func(obj interface{}) {
+		SetDefaults_TriggerConfiguration(obj.(*TriggerConfiguration))
+	})
+	scheme.AddTypeDefaultingFunc(&MigratorConfiguration{}, func(obj interface{}) {
+		SetDefaults_MigratorConfiguration(obj.(*MigratorConfiguration))
+	})
+	return nil
+}
+
+// SetDefaults_TriggerConfiguration sets the unset fields of obj to their
+// defaults.
+func SetDefaults_TriggerConfiguration(obj *TriggerConfiguration) {
+	if obj.DiscoveryPeriod.Duration == 0 {
+		obj.DiscoveryPeriod = metav1.Duration{Duration: 10 * time.Minute}
+	}
+	if obj.StaleHeartbeatMultiplier == 0 {
+		obj.StaleHeartbeatMultiplier = 2
+	}
+	if obj.StorageStateGracePeriod == nil {
+		obj.StorageStateGracePeriod = &metav1.Duration{Duration: 6 * obj.DiscoveryPeriod.Duration}
+	}
+	if obj.RetryPolicy.MaxAttempts == nil {
+		maxAttempts := int32(5)
+		obj.RetryPolicy.MaxAttempts = &maxAttempts
+	}
+	if obj.RetryPolicy.InitialBackoff.Duration == 0 {
+		obj.RetryPolicy.InitialBackoff = metav1.Duration{Duration: time.Minute}
+	}
+	if obj.RetryPolicy.MaxBackoff.Duration == 0 {
+		obj.RetryPolicy.MaxBackoff = metav1.Duration{Duration: time.Hour}
+	}
+}
+
+// SetDefaults_MigratorConfiguration sets the unset fields of obj to their
+// defaults.
+func SetDefaults_MigratorConfiguration(obj *MigratorConfiguration) {
+	if obj.ChunkSize == 0 {
+		obj.ChunkSize = 100
+	}
+	if obj.Concurrency == 0 {
+		obj.Concurrency = 1
+	}
+	if obj.PriorityAgingPeriod == nil {
+		obj.PriorityAgingPeriod = &metav1.Duration{Duration: time.Hour}
+	}
+	for i := range obj.Notifications {
+		w := &obj.Notifications[i]
+		if len(w.Events) == 0 {
+			w.Events = []string{"Succeeded", "Failed"}
+		}
+		if w.Timeout == nil {
+			w.Timeout = &metav1.Duration{Duration: 10 * time.Second}
+		}
+		if w.MaxAttempts == nil {
+			maxAttempts := int32(3)
+			w.MaxAttempts = &maxAttempts
+		}
+	}
+	le := &obj.LeaderElection
+	if le.LeaseDuration.Duration == 0 {
+		le.LeaseDuration = metav1.Duration{Duration: 137 * time.Second}
+	}
+	if le.RenewDeadline.Duration == 0 {
+		le.RenewDeadline = metav1.Duration{Duration: 107 * time.Second}
+	}
+	if le.RetryPeriod.Duration == 0 {
+		le.RetryPeriod = metav1.Duration{Duration: 26 * time.Second}
+	}
+	// The ResourceName is left empty, the commands default it to their
+	// own lease.
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TriggerConfiguration configures the storage migration triggering
+// controller. All its fields are reloaded when the configuration file
+// changes.
+type TriggerConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// How often the trigger checks the storage version hashes in the
+	// discovery document. Defaults to 10m.
+	// +optional
+	DiscoveryPeriod metav1.Duration `json:"discoveryPeriod,omitempty"`
+	// The number of discovery periods after which the heartbeat of a
+	// storageState is stale, and its resource is migrated again. Defaults
+	// to 2.
+	// +optional
+	StaleHeartbeatMultiplier int32 `json:"staleHeartbeatMultiplier,omitempty"`
+	// How long the resource of a storageState can be absent from the
+	// discovery document before the storageState and the migrations of
+	// the resource are deleted. 0 disables the garbage collection.
+	// Defaults to 1h.
+	// +optional
+	StorageStateGracePeriod *metav1.Duration `json:"storageStateGracePeriod,omitempty"`
+	// The ttlSecondsAfterFinished of the migrations created by the
+	// trigger. If unset, the finished migrations are kept.
+	// +optional
+	MigrationTTLSecondsAfterFinished *int32 `json:"migrationTTLSecondsAfterFinished,omitempty"`
+	// How failed migrations are retried.
+	// +optional
+	RetryPolicy RetryPolicy `json:"retryPolicy,omitempty"`
+	// Overrides the maxAttempts of the retryPolicy for specific resources,
+	// written as "<resource>.<group>", or "<resource>" for the core group.
+	// +optional
+	ResourceRetryMaxAttempts map[string]int32 `json:"resourceRetryMaxAttempts,omitempty"`
+	// If true, the trigger only keeps the storageStates up to date and
+	// reports the resources that need to be migrated, without creating,
+	// superseding or deleting migrations.
+	// +optional
+	ObserveOnly bool `json:"observeOnly,omitempty"`
+	// The resources the trigger launches migrations for.
+	// +optional
+	Resources ResourceRules `json:"resources,omitempty"`
+}
+
+// RetryPolicy controls how the trigger retries migrating a resource after
+// its migration failed.
+type RetryPolicy struct {
+	// The number of consecutive failed migrations after which the trigger
+	// gives up migrating the resource. 0 means the trigger never gives up.
+	// Defaults to 5.
+	// +optional
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+	// The delay before retrying after the first failed migration. The
+	// delay doubles after every following failure. Defaults to 1m.
+	// +optional
+	InitialBackoff metav1.Duration `json:"initialBackoff,omitempty"`
+	// The maximum delay between retries. Defaults to 1h.
+	// +optional
+	MaxBackoff metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// ResourceRules select resources by group and by name.
+type ResourceRules struct {
+	// If not empty, only the resources of these groups are selected. The
+	// core group is written as "core".
+	// +optional
+	IncludeGroups []string `json:"includeGroups,omitempty"`
+	// The groups whose resources are not selected.
+	// +optional
+	ExcludeGroups []string `json:"excludeGroups,omitempty"`
+	// If not empty, only these resources are selected, written as
+	// "<resource>.<group>", or "<resource>" for the core group.
+	// +optional
+	IncludeResources []string `json:"includeResources,omitempty"`
+	// The resources that are not selected. Exclusions take precedence over
+	// inclusions.
+	// +optional
+	ExcludeResources []string `json:"excludeResources,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MigratorConfiguration configures the migrator. The chunkSize, the
+// concurrency, the maintenanceWindows, the priorityAgingPeriod, the
+// notifications and the auditSinks are reloaded when the configuration file
+// changes, the leader election settings require a restart.
+type MigratorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// The number of objects listed per request. Defaults to 100.
+	// +optional
+	ChunkSize int64 `json:"chunkSize,omitempty"`
+	// The number of objects migrated in parallel. Defaults to 1.
+	// +optional
+	Concurrency int32 `json:"concurrency,omitempty"`
+	// The time windows in which the migrations run. A migration running
+	// when the windows close is suspended and resumed when one opens. If
+	// empty, the migrations run at any time. The maintenanceWindows of the
+	// MigrationPolicies override them for the matched resources.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// How long a migration waits pending before its priority grows by one,
+	// so that the migrations of low priority are not starved. 0 disables
+	// the aging. Defaults to 1h.
+	// +optional
+	PriorityAgingPeriod *metav1.Duration `json:"priorityAgingPeriod,omitempty"`
+	// The webhooks notified when the migrations change state.
+	// +optional
+	Notifications []WebhookConfiguration `json:"notifications,omitempty"`
+	// The sinks the report of every run of a migration is written to.
+	// +optional
+	AuditSinks []AuditSinkConfiguration `json:"auditSinks,omitempty"`
+	// The leader election of the migrator replicas.
+	// +optional
+	LeaderElection LeaderElectionConfiguration `json:"leaderElection,omitempty"`
+}
+
+// MaintenanceWindow is a time window, opened on a cron schedule for a
+// duration.
+type MaintenanceWindow struct {
+	// The cron schedule opening the window, made of five fields: minute,
+	// hour, day of month, month and day of week.
+	Schedule string `json:"schedule"`
+	// How long the window stays open.
+	Duration metav1.Duration `json:"duration"`
+	// The time zone of the schedule, as a name of the IANA time zone
+	// database. Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// WebhookConfiguration configures an HTTP endpoint the state transitions of
+// the migrations are posted to, as JSON.
+type WebhookConfiguration struct {
+	// The http or https URL of the endpoint.
+	URL string `json:"url"`
+	// The path of a file holding the secret signing the requests with
+	// HMAC-SHA256, in the X-Migration-Signature header. If empty, the
+	// requests are not signed.
+	// +optional
+	SecretFile string `json:"secretFile,omitempty"`
+	// The states of the migrations posted to the endpoint: Running,
+	// Succeeded or Failed. Defaults to Succeeded and Failed.
+	// +optional
+	Events []string `json:"events,omitempty"`
+	// The timeout of a delivery attempt. Defaults to 10s.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// The number of delivery attempts. The delivery is retried on network
+	// errors, on 429 and on 5xx responses. Defaults to 3.
+	// +optional
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+}
+
+// AuditSinkConfiguration configures a sink of the reports of the runs of
+// the migrations.
+type AuditSinkConfiguration struct {
+	// The type of the sink: ConfigMap stores the reports of every
+	// migration in a ConfigMap, File appends them as JSON lines to a file,
+	// and Stdout prints them as JSON lines.
+	Type string `json:"type"`
+	// The namespace of the ConfigMaps. Defaults to the namespace of the
+	// pod.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// The path of the file. Required by the File sinks.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// LeaderElectionConfiguration configures the leader election.
+type LeaderElectionConfiguration struct {
+	// If true, the replicas elect a leader, which is the only one running.
+	// +optional
+	LeaderElect bool `json:"leaderElect,omitempty"`
+	// How long the other replicas wait before forcefully acquiring the
+	// lease. Defaults to 137s.
+	// +optional
+	LeaseDuration metav1.Duration `json:"leaseDuration,omitempty"`
+	// How long the leader tries to renew the lease before giving up.
+	// Defaults to 107s.
+	// +optional
+	RenewDeadline metav1.Duration `json:"renewDeadline,omitempty"`
+	// How long to wait between the attempts to acquire or renew the
+	// lease. Defaults to 26s.
+	// +optional
+	RetryPeriod metav1.Duration `json:"retryPeriod,omitempty"`
+	// The name of the lease. Defaults to the lease of the command:
+	// storage-version-migration-migrator-lock for the migrator, and
+	// storage-version-migration-lock for the all-in-one process.
+	// +optional
+	ResourceName string `json:"resourceName,omitempty"`
+	// The namespace of the lease. Defaults to the namespace of the pod.
+	// +optional
+	ResourceNamespace string `json:"resourceNamespace,omitempty"`
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package validation validates the configuration of the trigger and the
+// migrator.
+package validation
+
+import (
+	"net/url"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/config/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/schedule"
+)
+
+// ValidateTriggerConfiguration validates a defaulted TriggerConfiguration.
+func ValidateTriggerConfiguration(c *v1alpha1.TriggerConfiguration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.DiscoveryPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("discoveryPeriod"), c.DiscoveryPeriod.Duration.String(), "must be greater than 0"))
+	}
+	if c.StaleHeartbeatMultiplier < 1 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("staleHeartbeatMultiplier"), c.StaleHeartbeatMultiplier, "must be at least 1"))
+	}
+	if c.StorageStateGracePeriod != nil && c.StorageStateGracePeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("storageStateGracePeriod"), c.StorageStateGracePeriod.Duration.String(), "must not be negative"))
+	}
+	if c.MigrationTTLSecondsAfterFinished != nil && *c.MigrationTTLSecondsAfterFinished < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("migrationTTLSecondsAfterFinished"), *c.MigrationTTLSecondsAfterFinished, "must not be negative"))
+	}
+	allErrs = append(allErrs, validateRetryPolicy(c.RetryPolicy, field.NewPath("retryPolicy"))...)
+	for resource, attempts := range c.ResourceRetryMaxAttempts {
+		fldPath := field.NewPath("resourceRetryMaxAttempts").Key(resource)
+		if resource == "" {
+			allErrs = append(allErrs, field.Invalid(fldPath, resource, "must not be empty"))
+		}
+		if attempts < 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath, attempts, "must not be negative"))
+		}
+	}
+	allErrs = append(allErrs, validateResourceRules(c.Resources, field.NewPath("resources"))...)
+	return allErrs
+}
+
+func validateRetryPolicy(p v1alpha1.RetryPolicy, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if p.MaxAttempts != nil && *p.MaxAttempts < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxAttempts"), *p.MaxAttempts, "must not be negative"))
+	}
+	if p.InitialBackoff.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("initialBackoff"), p.InitialBackoff.Duration.String(), "must be greater than 0"))
+	}
+	if p.MaxBackoff.Duration < p.InitialBackoff.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxBackoff"), p.MaxBackoff.Duration.String(), "must not be less than initialBackoff"))
+	}
+	return allErrs
+}
+
+func validateResourceRules(r v1alpha1.ResourceRules, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	for _, list := range []struct {
+		name   string
+		values []string
+	}{
+		{"includeGroups", r.IncludeGroups},
+		{"excludeGroups", r.ExcludeGroups},
+		{"includeResources", r.IncludeResources},
+		{"excludeResources", r.ExcludeResources},
+	} {
+		for i, v := range list.values {
+			if v == "" {
+				allErrs = append(allErrs, field.Required(fldPath.Child(list.name).Index(i), "must not be empty"))
+			}
+		}
+	}
+	return allErrs
+}
+
+// ValidateMigratorConfiguration validates a defaulted MigratorConfiguration.
+func ValidateMigratorConfiguration(c *v1alpha1.MigratorConfiguration) field.ErrorList {
+	var allErrs field.ErrorList
+	if c.ChunkSize <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("chunkSize"), c.ChunkSize, "must be greater than 0"))
+	}
+	if c.Concurrency <= 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("concurrency"), c.Concurrency, "must be greater than 0"))
+	}
+	if c.PriorityAgingPeriod != nil && c.PriorityAgingPeriod.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("priorityAgingPeriod"), c.PriorityAgingPeriod.Duration.String(), "must not be negative"))
+	}
+	for i, w := range c.MaintenanceWindows {
+		if _, err := schedule.NewWindow(w.Schedule, w.Duration.Duration, w.TimeZone); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("maintenanceWindows").Index(i), w, err.Error()))
+		}
+	}
+	for i, w := range c.Notifications {
+		allErrs = append(allErrs, validateWebhook(w, field.NewPath("notifications").Index(i))...)
+	}
+	for i, s := range c.AuditSinks {
+		allErrs = append(allErrs, validateAuditSink(s, field.NewPath("auditSinks").Index(i))...)
+	}
+	allErrs = append(allErrs, validateLeaderElection(c.LeaderElection, field.NewPath("leaderElection"))...)
+	return allErrs
+}
+
+var auditSinkTypes = sets.NewString("ConfigMap", "File", "Stdout")
+
+func validateAuditSink(s v1alpha1.AuditSinkConfiguration, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !auditSinkTypes.Has(s.Type) {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), s.Type, auditSinkTypes.List()))
+	}
+	if s.Type == "File" && s.Path == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("path"), "must be set for the File sinks"))
+	}
+	return allErrs
+}
+
+var webhookEvents = sets.NewString("Running", "Succeeded", "Failed")
+
+func validateWebhook(w v1alpha1.WebhookConfiguration, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if u, err := url.Parse(w.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), w.URL, "must be an http or https URL"))
+	}
+	for i, e := range w.Events {
+		if !webhookEvents.Has(e) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("events").Index(i), e, webhookEvents.List()))
+		}
+	}
+	if w.Timeout != nil && w.Timeout.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("timeout"), w.Timeout.Duration.String(), "must be greater than 0"))
+	}
+	if w.MaxAttempts != nil && *w.MaxAttempts < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxAttempts"), *w.MaxAttempts, "must be at least 1"))
+	}
+	return allErrs
+}
+
+func validateLeaderElection(le v1alpha1.LeaderElectionConfiguration, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if !le.LeaderElect {
+		return allErrs
+	}
+	if le.RetryPeriod.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("retryPeriod"), le.RetryPeriod.Duration.String(), "must be greater than 0"))
+	}
+	if le.RenewDeadline.Duration <= le.RetryPeriod.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("renewDeadline"), le.RenewDeadline.Duration.String(), "must be greater than retryPeriod"))
+	}
+	if le.LeaseDuration.Duration <= le.RenewDeadline.Duration {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("leaseDuration"), le.LeaseDuration.Duration.String(), "must be greater than renewDeadline"))
+	}
+	return allErrs
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/config/v1alpha1"
+)
+
+func TestValidateTriggerConfiguration(t *testing.T) {
+	negative := int32(-1)
+	for _, tc := range []struct {
+		name     string
+		mutate   func(c *v1alpha1.TriggerConfiguration)
+		expected []string
+	}{
+		{
+			name:   "valid",
+			mutate: func(c *v1alpha1.TriggerConfiguration) {},
+		},
+		{
+			name: "invalid periods",
+			mutate: func(c *v1alpha1.TriggerConfiguration) {
+				c.DiscoveryPeriod = metav1.Duration{Duration: -time.Minute}
+				c.StorageStateGracePeriod = &metav1.Duration{Duration: -time.Minute}
+			},
+			expected: []string{"discoveryPeriod", "storageStateGracePeriod"},
+		},
+		{
+			name: "invalid retries",
+			mutate: func(c *v1alpha1.TriggerConfiguration) {
+				c.RetryPolicy.MaxAttempts = &negative
+				c.ResourceRetryMaxAttempts = map[string]int32{"pods": -1}
+			},
+			expected: []string{"retryPolicy.maxAttempts", "resourceRetryMaxAttempts[pods]"},
+		},
+		{
+			name: "empty resource",
+			mutate: func(c *v1alpha1.TriggerConfiguration) {
+				c.Resources.ExcludeResources = []string{"pods", ""}
+			},
+			expected: []string{"resources.excludeResources[1]"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &v1alpha1.TriggerConfiguration{}
+			v1alpha1.SetDefaults_TriggerConfiguration(c)
+			tc.mutate(c)
+			errs := ValidateTriggerConfiguration(c)
+			if len(errs) != len(tc.expected) {
+				t.Fatalf("expected errors for %v, got %v", tc.expected, errs)
+			}
+			for i, field := range tc.expected {
+				if errs[i].Field != field {
+					t.Errorf("expected an error for %s, got %v", field, errs[i])
+				}
+			}
+		})
+	}
+}
+
+func TestValidateMigratorConfiguration(t *testing.T) {
+	c := &v1alpha1.MigratorConfiguration{}
+	v1alpha1.SetDefaults_MigratorConfiguration(c)
+	if errs := ValidateMigratorConfiguration(c); len(errs) != 0 {
+		t.Errorf("unexpected errors %v", errs)
+	}
+	c.LeaderElection.LeaderElect = true
+	c.LeaderElection.RenewDeadline = c.LeaderElection.LeaseDuration
+	c.Concurrency = 0
+	errs := ValidateMigratorConfiguration(c)
+	if len(errs) != 2 || errs[0].Field != "concurrency" || errs[1].Field != "leaderElection.leaseDuration" {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
+
+func TestValidateMaintenanceWindows(t *testing.T) {
+	c := &v1alpha1.MigratorConfiguration{
+		MaintenanceWindows: []v1alpha1.MaintenanceWindow{
+			{Schedule: "0 1 * * 1-5", Duration: metav1.Duration{Duration: 2 * time.Hour}},
+			{Schedule: "0 25 * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			{Schedule: "0 1 * * *"},
+		},
+	}
+	v1alpha1.SetDefaults_MigratorConfiguration(c)
+	errs := ValidateMigratorConfiguration(c)
+	if len(errs) != 2 || errs[0].Field != "maintenanceWindows[1]" || errs[1].Field != "maintenanceWindows[2]" {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
+
+func TestValidatePriorityAgingPeriod(t *testing.T) {
+	c := &v1alpha1.MigratorConfiguration{}
+	v1alpha1.SetDefaults_MigratorConfiguration(c)
+	if c.PriorityAgingPeriod == nil || c.PriorityAgingPeriod.Duration != time.Hour {
+		t.Errorf("expected a default aging period of 1h, got %v", c.PriorityAgingPeriod)
+	}
+	c.PriorityAgingPeriod = &metav1.Duration{Duration: -time.Minute}
+	errs := ValidateMigratorConfiguration(c)
+	if len(errs) != 1 || errs[0].Field != "priorityAgingPeriod" {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
+
+func TestValidateNotifications(t *testing.T) {
+	zero := int32(0)
+	c := &v1alpha1.MigratorConfiguration{
+		Notifications: []v1alpha1.WebhookConfiguration{
+			{URL: "https://example.com/hook", Events: []string{"Running"}},
+			{URL: "ftp://example.com"},
+			{URL: "http://example.com", Events: []string{"Done"}, Timeout: &metav1.Duration{}, MaxAttempts: &zero},
+		},
+	}
+	v1alpha1.SetDefaults_MigratorConfiguration(c)
+	if w := c.Notifications[1]; len(w.Events) != 2 || w.Timeout.Duration != 10*time.Second || *w.MaxAttempts != 3 {
+		t.Errorf("unexpected defaults %+v", w)
+	}
+	errs := ValidateMigratorConfiguration(c)
+	var fields []string
+	for _, err := range errs {
+		fields = append(fields, err.Field)
+	}
+	expected := []string{"notifications[1].url", "notifications[2].events[0]", "notifications[2].timeout", "notifications[2].maxAttempts"}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected errors on %v, got %v", expected, errs)
+	}
+}
+
+func TestValidateAuditSinks(t *testing.T) {
+	c := &v1alpha1.MigratorConfiguration{
+		AuditSinks: []v1alpha1.AuditSinkConfiguration{
+			{Type: "ConfigMap"},
+			{Type: "File", Path: "/var/log/migrations.jsonl"},
+			{Type: "Stdout"},
+			{Type: "File"},
+			{Type: "Syslog"},
+		},
+	}
+	v1alpha1.SetDefaults_MigratorConfiguration(c)
+	errs := ValidateMigratorConfiguration(c)
+	if len(errs) != 2 || errs[0].Field != "auditSinks[3].path" || errs[1].Field != "auditSinks[4].type" {
+		t.Errorf("unexpected errors %v", errs)
+	}
+}
@@ -48,6 +48,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&StorageVersionMigrationList{},
 		&StorageState{},
 		&StorageStateList{},
+		&MigrationPolicy{},
+		&MigrationPolicyList{},
+		&MigrationPlan{},
+		&MigrationPlanList{},
+		&MigrationSummary{},
+		&MigrationSummaryList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -56,11 +56,27 @@ type StorageVersionMigrationSpec struct {
 	// Immutable.
 	Resource GroupVersionResource `json:"resource"`
 	// The token used in the list options to get the next chunk of objects
-	// to migrate. When the .status.conditions indicates the migration is
-	// "Running", users can use this token to check the progress of the
-	// migration.
+	// to migrate. Deprecated: the migrator saves its progress in
+	// .status.continueToken, and only reads this field to resume the
+	// migrations started by older migrators.
 	// +optional
 	ContinueToken string `json:"continueToken,omitempty"`
+	// ttlSecondsAfterFinished limits the lifetime of a migration that has
+	// finished execution (either Succeeded or Failed). If this field is set,
+	// ttlSecondsAfterFinished after the migration finishes, it is eligible
+	// to be automatically deleted by the trigger controller. If this field
+	// is unset, the migration won't be automatically deleted. If this field
+	// is set to zero, the migration becomes eligible to be deleted
+	// immediately after it finishes.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// The priority of the migration over the other pending migrations.
+	// The migrator runs the pending migrations by decreasing priority, then
+	// by creation time. The priority of a pending migration grows as it
+	// waits, so that migrations of low priority are eventually run.
+	// Defaults to 0.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
 	// TODO: consider recording the storage version hash when the migration
 	// is created. It can avoid races.
 }
@@ -74,6 +90,78 @@ const (
 	MigrationSucceeded MigrationConditionType = "Succeeded"
 	// Indicates that the migration has failed.
 	MigrationFailed MigrationConditionType = "Failed"
+	// Indicates that a newer migration of the same resource has been
+	// created. A superseded migration is not run, and is stopped if it is
+	// running. It is kept as a record of the past migrations of the
+	// resource.
+	MigrationSuperseded MigrationConditionType = "Superseded"
+	// Indicates that the migration is not run, or has been suspended,
+	// because the maintenance windows of its resource are closed.
+	MigrationWaiting MigrationConditionType = "Waiting"
+)
+
+const (
+	// The maintenance windows of the resource are closed.
+	MigrationWaitingReasonOutsideMaintenanceWindow = "OutsideMaintenanceWindow"
+	// The maintenance windows of the resource are invalid.
+	MigrationWaitingReasonInvalidMaintenanceWindow = "InvalidMaintenanceWindow"
+)
+
+const (
+	// The resource of the migration is not served by the apiserver.
+	MigrationFailedReasonResourceNotFound = "ResourceNotFound"
+	// The resource of the migration does not support the list and update
+	// verbs the migrator rewrites its objects with.
+	MigrationFailedReasonResourceNotMigratable = "ResourceNotMigratable"
+	// The migrator is not authorized to rewrite the objects of the
+	// resource.
+	MigrationFailedReasonForbidden = "Forbidden"
+	// Another migration of the resource, created before the migration, is
+	// pending or running.
+	MigrationFailedReasonDuplicateMigration = "DuplicateMigration"
+	// A migration policy with the Skip action matches the resource of the
+	// migration.
+	MigrationFailedReasonSkippedByPolicy = "SkippedByPolicy"
+)
+
+const (
+	// MigrationReasonAnnotation is set on a migration to the reason it was
+	// created, one of the MigrationReason* values.
+	MigrationReasonAnnotation = "migration.k8s.io/reason"
+	// MigrationMessageAnnotation is set on a migration to a human readable
+	// message explaining why it was created.
+	MigrationMessageAnnotation = "migration.k8s.io/message"
+	// MigrationCreatedByAnnotation is set on a migration to the name of the
+	// component that created it.
+	MigrationCreatedByAnnotation = "migration.k8s.io/created-by"
+	// MigrationStorageVersionHashAnnotation is set on a migration to the
+	// storage version hash of the resource, as shown in the discovery
+	// document, when the migration was created.
+	MigrationStorageVersionHashAnnotation = "migration.k8s.io/storage-version-hash"
+	// MigrationPlanLabel is set on the migrations created for a migration
+	// plan to the name of the plan.
+	MigrationPlanLabel = "migration.k8s.io/plan"
+	// MigrationPlanStepLabel is set on the migrations created for a
+	// migration plan to the name of their step.
+	MigrationPlanStepLabel = "migration.k8s.io/plan-step"
+)
+
+const (
+	// The resource had no storageState.
+	MigrationReasonNewResource = "NewResource"
+	// The storage version hash of the resource changed.
+	MigrationReasonStorageVersionHashChanged = "StorageVersionHashChanged"
+	// The heartbeat of the storageState of the resource was stale, so
+	// storage versions might have changed unobserved.
+	MigrationReasonStaleHeartbeat = "StaleHeartbeat"
+	// The previous migration of the resource failed.
+	MigrationReasonPreviousMigrationFailed = "PreviousMigrationFailed"
+	// The resource was not migrated, and had no migration in progress.
+	MigrationReasonMigrationMissing = "MigrationMissing"
+	// The migration was created when the migrator was installed.
+	MigrationReasonInitialization = "Initialization"
+	// The migration was created for a step of a migration plan.
+	MigrationReasonMigrationPlan = "MigrationPlan"
 )
 
 // Describes the state of a migration at a certain point.
@@ -100,6 +188,23 @@ type StorageVersionMigrationStatus struct {
 	// +patchMergeKey=type
 	// +patchStrategy=merge
 	Conditions []MigrationCondition `json:"conditions,omitempty"`
+	// The token used in the list options to get the next chunk of objects
+	// to migrate, saved by the migrator after every migrated chunk. When
+	// the .status.conditions indicates the migration is "Running", users
+	// can use this token to check the progress of the migration.
+	// +optional
+	ContinueToken string `json:"continueToken,omitempty"`
+	// The number of objects rewritten by the migration, saved by the
+	// migrator with the continue token.
+	// +optional
+	ObjectsMigrated int64 `json:"objectsMigrated,omitempty"`
+	// The time the migrator started running the migration.
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// How long the migration ran, from its start time to its completion.
+	// Set when the migration succeeds or fails.
+	// +optional
+	Duration *metav1.Duration `json:"duration,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -172,6 +277,89 @@ type StorageStateStatus struct {
 	// discovery document and updates this field.
 	// +optional
 	LastHeartbeatTime metav1.Time `json:"lastHeartbeatTime,omitempty"`
+	// The history of the values of persistedStorageVersionHashes, recording
+	// when each hash was first observed and when it was removed. The oldest
+	// removed hashes are dropped from the history when it grows too long.
+	// +optional
+	StorageVersionHashHistory []StorageVersionHashRecord `json:"storageVersionHashHistory,omitempty"`
+	// The name of the last migration of spec.resource that succeeded.
+	// +optional
+	LastSucceededMigration string `json:"lastSucceededMigration,omitempty"`
+	// The message of the Failed condition of the last migration of
+	// spec.resource that failed.
+	// +optional
+	LastMigrationFailureMessage string `json:"lastMigrationFailureMessage,omitempty"`
+	// The number of consecutive failed migrations of spec.resource since
+	// the storage version hash last changed, or since the last successful
+	// migration.
+	// +optional
+	FailedMigrationAttempts int32 `json:"failedMigrationAttempts,omitempty"`
+	// The name of the last failed migration counted in
+	// failedMigrationAttempts.
+	// +optional
+	LastFailedMigration string `json:"lastFailedMigration,omitempty"`
+	// The earliest time the storage migration triggering controller
+	// launches a new migration of spec.resource after a failed migration.
+	// +optional
+	NextMigrationRetryTime *metav1.Time `json:"nextMigrationRetryTime,omitempty"`
+	// The latest available observations of the storage state.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []StorageStateCondition `json:"conditions,omitempty"`
+}
+
+type StorageStateConditionType string
+
+const (
+	// Indicates that the storage migration triggering controller stopped
+	// retrying failed migrations of the resource, because the maximum
+	// number of attempts was reached. It is cleared when the storage
+	// version hash changes, or when the storageState is deleted.
+	StorageStateGaveUp StorageStateConditionType = "GaveUp"
+	// Indicates that all persisted instances of spec.resource are encoded
+	// in the current storage version.
+	StorageStateMigrated StorageStateConditionType = "Migrated"
+	// Indicates that a migration of spec.resource is pending or running.
+	StorageStateMigrationInProgress StorageStateConditionType = "MigrationInProgress"
+	// Indicates that spec.resource is not migrated, and no migration of it
+	// is pending or running. The reason tells why the migration is
+	// required, using the same values as the reason annotation of
+	// migrations.
+	StorageStateMigrationRequired StorageStateConditionType = "MigrationRequired"
+	// Indicates that spec.resource is absent from the discovery document.
+	// The storageState is garbage collected if the resource does not
+	// reappear within the grace period.
+	StorageStateStale StorageStateConditionType = "Stale"
+)
+
+// Records when a storage version hash was persisted.
+type StorageVersionHashRecord struct {
+	// The storage version hash, or "Unknown".
+	Hash string `json:"hash"`
+	// The time the hash was added to persistedStorageVersionHashes.
+	FirstObservedTime metav1.Time `json:"firstObservedTime"`
+	// The time the hash was removed from persistedStorageVersionHashes,
+	// unset while the hash is persisted.
+	// +optional
+	RemovedTime *metav1.Time `json:"removedTime,omitempty"`
+}
+
+// Describes the state of the storage at a certain point.
+type StorageStateCondition struct {
+	// Type of the condition.
+	Type StorageStateConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// The reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating details about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -184,3 +372,430 @@ type StorageStateList struct {
 	// Items is the list of StorageState
 	Items []StorageState `json:"items"`
 }
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+
+// MigrationPolicy sets how the resources it matches are migrated. When
+// several policies match a resource, every setting is taken from the policy
+// with the highest priority that sets it, ties being broken by the
+// alphabetical order of the names of the policies.
+type MigrationPolicy struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the policy.
+	// +optional
+	Spec MigrationPolicySpec `json:"spec,omitempty"`
+	// Status of the policy.
+	// +optional
+	Status MigrationPolicyStatus `json:"status,omitempty"`
+}
+
+// Matches resources by group and resource name.
+type ResourcePattern struct {
+	// The name of the group, empty for the core group. "*" matches every
+	// group, and "*.<suffix>" matches the groups ending in ".<suffix>".
+	// +optional
+	Group string `json:"group,omitempty"`
+	// The name of the resource. "*" matches every resource.
+	Resource string `json:"resource"`
+}
+
+type MigrationPolicyAction string
+
+const (
+	// The matched resources are migrated, even if the trigger is
+	// configured not to migrate them.
+	MigrationPolicyActionMigrate MigrationPolicyAction = "Migrate"
+	// The trigger never launches migrations of the matched resources.
+	MigrationPolicyActionSkip MigrationPolicyAction = "Skip"
+)
+
+type MigrationStrategy string
+
+const (
+	// The migrator rewrites the objects.
+	MigrationStrategyDirect MigrationStrategy = "Direct"
+	// The migrator rewrites all the objects in dry-run mode first, and
+	// only rewrites them if no dry-run rewrite failed.
+	MigrationStrategyDryRunFirst MigrationStrategy = "DryRunFirst"
+)
+
+// Specification of the migration policy.
+type MigrationPolicySpec struct {
+	// The resources the policy applies to. A resource is matched if it
+	// matches any of the patterns.
+	// +kubebuilder:validation:MinItems=1
+	Resources []ResourcePattern `json:"resources"`
+	// The priority of the policy over the other policies matching the same
+	// resources. Higher priorities win.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// Whether the matched resources are migrated, one of Migrate or Skip.
+	// If unset, the configuration of the trigger decides.
+	// +optional
+	// +kubebuilder:validation:Enum=Migrate;Skip
+	Action MigrationPolicyAction `json:"action,omitempty"`
+	// How the migrator rewrites the objects, one of Direct or DryRunFirst.
+	// If unset, Direct.
+	// +optional
+	// +kubebuilder:validation:Enum=Direct;DryRunFirst
+	Strategy MigrationStrategy `json:"strategy,omitempty"`
+	// The number of objects the migrator lists per request. If unset, the
+	// configuration of the migrator decides.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ChunkSize *int64 `json:"chunkSize,omitempty"`
+	// The number of objects the migrator rewrites in parallel. If unset,
+	// the configuration of the migrator decides.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Concurrency *int32 `json:"concurrency,omitempty"`
+	// How the trigger retries failed migrations. The unset fields are
+	// taken from the configuration of the trigger.
+	// +optional
+	Retry *MigrationPolicyRetry `json:"retry,omitempty"`
+	// The time windows in which the migrator rewrites the matched
+	// resources. A migration running when the windows close is suspended
+	// and resumed when one opens. If unset, the configuration of the
+	// migrator decides.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+	// The priority of the migrations the trigger creates for the matched
+	// resources. If unset, 0.
+	// +optional
+	MigrationPriority *int32 `json:"migrationPriority,omitempty"`
+}
+
+// A time window, opened on a cron schedule for a duration.
+type MaintenanceWindow struct {
+	// The cron schedule opening the window, made of five fields: minute,
+	// hour, day of month, month and day of week, e.g. "0 1 * * 1-5" for
+	// 01:00 on weekdays.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+	// How long the window stays open.
+	Duration metav1.Duration `json:"duration"`
+	// The time zone of the schedule, as a name of the IANA time zone
+	// database, e.g. "Europe/Paris". Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// How the trigger retries failed migrations.
+type MigrationPolicyRetry struct {
+	// The number of consecutive failed migrations after which the trigger
+	// gives up migrating a resource. Zero means the trigger never gives up.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+	// The delay before retrying after the first failed migration. The
+	// delay doubles after every following failure.
+	// +optional
+	InitialBackoff *metav1.Duration `json:"initialBackoff,omitempty"`
+	// The maximum delay between retries.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+}
+
+// Status of the migration policy.
+type MigrationPolicyStatus struct {
+	// The generation of the policy observed by the trigger.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The number of resources served by the apiserver that the policy
+	// matches.
+	// +optional
+	MatchedResources int32 `json:"matchedResources,omitempty"`
+	// The latest available observations of the policy.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []MigrationPolicyCondition `json:"conditions,omitempty"`
+}
+
+type MigrationPolicyConditionType string
+
+const (
+	// Indicates that some settings of the policy do not apply to some of
+	// the resources it matches, because policies of higher precedence set
+	// them to other values. The message tells which.
+	MigrationPolicyConflicted MigrationPolicyConditionType = "Conflicted"
+)
+
+// Describes the state of a migration policy at a certain point.
+type MigrationPolicyCondition struct {
+	// Type of the condition.
+	Type MigrationPolicyConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// The reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating details about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MigrationPolicyList is a collection of migration policies.
+type MigrationPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// Items is the list of MigrationPolicy
+	Items []MigrationPolicy `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+
+// MigrationPlan migrates several resources in an order. Every step of the
+// plan migrates a resource, once the steps it depends on have succeeded.
+type MigrationPlan struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Specification of the plan.
+	// +optional
+	Spec MigrationPlanSpec `json:"spec,omitempty"`
+	// Status of the plan.
+	// +optional
+	Status MigrationPlanStatus `json:"status,omitempty"`
+}
+
+// Specification of the migration plan.
+type MigrationPlanSpec struct {
+	// The steps of the plan.
+	// +kubebuilder:validation:MinItems=1
+	Steps []MigrationPlanStep `json:"steps"`
+	// The priority of the migrations created for the steps.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+}
+
+// A step of a migration plan, migrating a resource.
+type MigrationPlanStep struct {
+	// The name of the step, unique in the plan.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// The resource migrated by the step.
+	Resource GroupVersionResource `json:"resource"`
+	// The names of the steps that must succeed before the step starts.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+type MigrationPlanStepPhase string
+
+const (
+	// The steps the step depends on have not succeeded yet.
+	MigrationPlanStepBlocked MigrationPlanStepPhase = "Blocked"
+	// The migration of the step is pending.
+	MigrationPlanStepPending MigrationPlanStepPhase = "Pending"
+	// The migration of the step is running.
+	MigrationPlanStepRunning MigrationPlanStepPhase = "Running"
+	// The migration of the step has succeeded.
+	MigrationPlanStepSucceeded MigrationPlanStepPhase = "Succeeded"
+	// The migration of the step has failed.
+	MigrationPlanStepFailed MigrationPlanStepPhase = "Failed"
+)
+
+// Status of a step of a migration plan.
+type MigrationPlanStepStatus struct {
+	// The name of the step.
+	Name string `json:"name"`
+	// The phase of the step, one of Blocked, Pending, Running, Succeeded or
+	// Failed.
+	Phase MigrationPlanStepPhase `json:"phase"`
+	// The name of the storageVersionMigration of the step.
+	// +optional
+	Migration string `json:"migration,omitempty"`
+	// A human readable message explaining the phase.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// Status of the migration plan.
+type MigrationPlanStatus struct {
+	// The generation of the plan observed by the plan controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// The status of the steps, in the order of the spec.
+	// +optional
+	Steps []MigrationPlanStepStatus `json:"steps,omitempty"`
+	// The latest available observations of the plan.
+	// +optional
+	Conditions []MigrationPlanCondition `json:"conditions,omitempty"`
+}
+
+type MigrationPlanConditionType string
+
+const (
+	// Indicates that all the steps of the plan have succeeded.
+	MigrationPlanSucceeded MigrationPlanConditionType = "Succeeded"
+	// Indicates that a step of the plan has failed. The steps depending on
+	// it are blocked until its migration is deleted, which retries it.
+	MigrationPlanFailed MigrationPlanConditionType = "Failed"
+	// Indicates that the steps of the plan are invalid, because of
+	// duplicated names, unknown dependencies or dependency cycles.
+	MigrationPlanInvalid MigrationPlanConditionType = "Invalid"
+)
+
+// Describes the state of a migration plan at a certain point.
+type MigrationPlanCondition struct {
+	// Type of the condition.
+	Type MigrationPlanConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// The reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating details about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MigrationPlanList is a collection of migration plans.
+type MigrationPlanList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// Items is the list of MigrationPlan
+	Items []MigrationPlan `json:"items"`
+}
+
+// The name of the MigrationSummary maintained by the storage migration
+// triggering controller.
+const MigrationSummaryName = "cluster"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +genclient:nonNamespaced
+
+// MigrationSummary summarizes the storage states of all the resources of the
+// cluster, e.g. to tell whether the apiserver can be upgraded. The storage
+// migration triggering controller maintains a single summary, named
+// "cluster".
+type MigrationSummary struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	// Status of the summary.
+	// +optional
+	Status MigrationSummaryStatus `json:"status,omitempty"`
+}
+
+// Status of the migration summary.
+type MigrationSummaryStatus struct {
+	// The last time the summary was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// The number of resources with a storageState that the trigger
+	// migrates. The excluded resources are not counted.
+	// +optional
+	Resources int32 `json:"resources,omitempty"`
+	// The number of resources with a storageState that the trigger does
+	// not migrate: the resources skipped by a migration policy or
+	// excluded by the resource rules of the trigger, and the resources
+	// migrated by an active migration plan.
+	// +optional
+	ExcludedResources int32 `json:"excludedResources,omitempty"`
+	// The number of resources whose objects are all encoded in the current
+	// storage version, and that have no pending or running migration.
+	// +optional
+	MigratedResources int32 `json:"migratedResources,omitempty"`
+	// The number of resources with a pending or running migration.
+	// +optional
+	InProgressResources int32 `json:"inProgressResources,omitempty"`
+	// The number of resources that are not migrated, and whose last
+	// migration failed.
+	// +optional
+	FailingResources int32 `json:"failingResources,omitempty"`
+	// The number of resources whose storageState has not been refreshed by
+	// the recent discoveries.
+	// +optional
+	StaleResources int32 `json:"staleResources,omitempty"`
+	// The resources that are not migrated, sorted by group and resource.
+	// The list is truncated if it is too long.
+	// +optional
+	BlockingResources []BlockingResource `json:"blockingResources,omitempty"`
+	// The latest available observations of the summary.
+	// +optional
+	Conditions []MigrationSummaryCondition `json:"conditions,omitempty"`
+}
+
+// A resource that is not migrated.
+type BlockingResource struct {
+	// The resource.
+	Resource GroupResource `json:"resource"`
+	// Why the resource is not migrated, one of MigrationInProgress,
+	// MigrationFailing or MigrationRequired.
+	Reason string `json:"reason"`
+	// A human readable message with the details.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+const (
+	// A migration of the resource is pending or running.
+	BlockingReasonMigrationInProgress = "MigrationInProgress"
+	// The last migration of the resource failed.
+	BlockingReasonMigrationFailing = "MigrationFailing"
+	// The resource is not migrated, and no migration of it is pending or
+	// running.
+	BlockingReasonMigrationRequired = "MigrationRequired"
+)
+
+type MigrationSummaryConditionType string
+
+const (
+	// Indicates that all the resources are migrated.
+	MigrationSummaryAllResourcesMigrated MigrationSummaryConditionType = "AllResourcesMigrated"
+	// Indicates that the last migrations of some resources failed.
+	MigrationSummaryMigrationsFailing MigrationSummaryConditionType = "MigrationsFailing"
+	// Indicates that the last discovery failed, or that the storageStates
+	// of some resources have not been refreshed by the recent discoveries,
+	// so the summary might miss storage version changes.
+	MigrationSummaryDiscoveryStale MigrationSummaryConditionType = "DiscoveryStale"
+)
+
+// Describes the state of the migration summary at a certain point.
+type MigrationSummaryCondition struct {
+	// Type of the condition.
+	Type MigrationSummaryConditionType `json:"type"`
+	// Status of the condition, one of True, False, Unknown.
+	Status corev1.ConditionStatus `json:"status"`
+	// The last time this condition was updated.
+	// +optional
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
+	// The reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// A human readable message indicating details about the transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MigrationSummaryList is a collection of migration summaries.
+type MigrationSummaryList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// Items is the list of MigrationSummary
+	Items []MigrationSummary `json:"items"`
+}
@@ -22,9 +22,27 @@ limitations under the License.
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlockingResource) DeepCopyInto(out *BlockingResource) {
+	*out = *in
+	out.Resource = in.Resource
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockingResource.
+func (in *BlockingResource) DeepCopy() *BlockingResource {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockingResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupResource) DeepCopyInto(out *GroupResource) {
 	*out = *in
@@ -57,6 +75,23 @@ func (in *GroupVersionResource) DeepCopy() *GroupVersionResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationCondition) DeepCopyInto(out *MigrationCondition) {
 	*out = *in
@@ -74,6 +109,473 @@ func (in *MigrationCondition) DeepCopy() *MigrationCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlan) DeepCopyInto(out *MigrationPlan) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlan.
+func (in *MigrationPlan) DeepCopy() *MigrationPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationPlan) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlanCondition) DeepCopyInto(out *MigrationPlanCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlanCondition.
+func (in *MigrationPlanCondition) DeepCopy() *MigrationPlanCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlanCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlanList) DeepCopyInto(out *MigrationPlanList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MigrationPlan, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlanList.
+func (in *MigrationPlanList) DeepCopy() *MigrationPlanList {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlanList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationPlanList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlanSpec) DeepCopyInto(out *MigrationPlanSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]MigrationPlanStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlanSpec.
+func (in *MigrationPlanSpec) DeepCopy() *MigrationPlanSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlanSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlanStatus) DeepCopyInto(out *MigrationPlanStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]MigrationPlanStepStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]MigrationPlanCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlanStatus.
+func (in *MigrationPlanStatus) DeepCopy() *MigrationPlanStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlanStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlanStep) DeepCopyInto(out *MigrationPlanStep) {
+	*out = *in
+	out.Resource = in.Resource
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlanStep.
+func (in *MigrationPlanStep) DeepCopy() *MigrationPlanStep {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlanStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPlanStepStatus) DeepCopyInto(out *MigrationPlanStepStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPlanStepStatus.
+func (in *MigrationPlanStepStatus) DeepCopy() *MigrationPlanStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPlanStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPolicy) DeepCopyInto(out *MigrationPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPolicy.
+func (in *MigrationPolicy) DeepCopy() *MigrationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPolicyCondition) DeepCopyInto(out *MigrationPolicyCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPolicyCondition.
+func (in *MigrationPolicyCondition) DeepCopy() *MigrationPolicyCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPolicyCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPolicyList) DeepCopyInto(out *MigrationPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MigrationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPolicyList.
+func (in *MigrationPolicyList) DeepCopy() *MigrationPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPolicyRetry) DeepCopyInto(out *MigrationPolicyRetry) {
+	*out = *in
+	if in.MaxAttempts != nil {
+		in, out := &in.MaxAttempts, &out.MaxAttempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InitialBackoff != nil {
+		in, out := &in.InitialBackoff, &out.InitialBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPolicyRetry.
+func (in *MigrationPolicyRetry) DeepCopy() *MigrationPolicyRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPolicyRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPolicySpec) DeepCopyInto(out *MigrationPolicySpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ResourcePattern, len(*in))
+		copy(*out, *in)
+	}
+	if in.ChunkSize != nil {
+		in, out := &in.ChunkSize, &out.ChunkSize
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Concurrency != nil {
+		in, out := &in.Concurrency, &out.Concurrency
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(MigrationPolicyRetry)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.MigrationPriority != nil {
+		in, out := &in.MigrationPriority, &out.MigrationPriority
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPolicySpec.
+func (in *MigrationPolicySpec) DeepCopy() *MigrationPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationPolicyStatus) DeepCopyInto(out *MigrationPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]MigrationPolicyCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationPolicyStatus.
+func (in *MigrationPolicyStatus) DeepCopy() *MigrationPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationSummary) DeepCopyInto(out *MigrationSummary) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationSummary.
+func (in *MigrationSummary) DeepCopy() *MigrationSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationSummary) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationSummaryCondition) DeepCopyInto(out *MigrationSummaryCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationSummaryCondition.
+func (in *MigrationSummaryCondition) DeepCopy() *MigrationSummaryCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationSummaryCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationSummaryList) DeepCopyInto(out *MigrationSummaryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]MigrationSummary, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationSummaryList.
+func (in *MigrationSummaryList) DeepCopy() *MigrationSummaryList {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationSummaryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MigrationSummaryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationSummaryStatus) DeepCopyInto(out *MigrationSummaryStatus) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.BlockingResources != nil {
+		in, out := &in.BlockingResources, &out.BlockingResources
+		*out = make([]BlockingResource, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]MigrationSummaryCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationSummaryStatus.
+func (in *MigrationSummaryStatus) DeepCopy() *MigrationSummaryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationSummaryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourcePattern) DeepCopyInto(out *ResourcePattern) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourcePattern.
+func (in *ResourcePattern) DeepCopy() *ResourcePattern {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourcePattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageState) DeepCopyInto(out *StorageState) {
 	*out = *in
@@ -102,6 +604,23 @@ func (in *StorageState) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageStateCondition) DeepCopyInto(out *StorageStateCondition) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageStateCondition.
+func (in *StorageStateCondition) DeepCopy() *StorageStateCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageStateCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageStateList) DeepCopyInto(out *StorageStateList) {
 	*out = *in
@@ -161,6 +680,24 @@ func (in *StorageStateStatus) DeepCopyInto(out *StorageStateStatus) {
 		copy(*out, *in)
 	}
 	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
+	if in.StorageVersionHashHistory != nil {
+		in, out := &in.StorageVersionHashHistory, &out.StorageVersionHashHistory
+		*out = make([]StorageVersionHashRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NextMigrationRetryTime != nil {
+		in, out := &in.NextMigrationRetryTime, &out.NextMigrationRetryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]StorageStateCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -174,12 +711,33 @@ func (in *StorageStateStatus) DeepCopy() *StorageStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageVersionHashRecord) DeepCopyInto(out *StorageVersionHashRecord) {
+	*out = *in
+	in.FirstObservedTime.DeepCopyInto(&out.FirstObservedTime)
+	if in.RemovedTime != nil {
+		in, out := &in.RemovedTime, &out.RemovedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageVersionHashRecord.
+func (in *StorageVersionHashRecord) DeepCopy() *StorageVersionHashRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageVersionHashRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageVersionMigration) DeepCopyInto(out *StorageVersionMigration) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -239,6 +797,11 @@ func (in *StorageVersionMigrationList) DeepCopyObject() runtime.Object {
 func (in *StorageVersionMigrationSpec) DeepCopyInto(out *StorageVersionMigrationSpec) {
 	*out = *in
 	out.Resource = in.Resource
+	if in.TTLSecondsAfterFinished != nil {
+		in, out := &in.TTLSecondsAfterFinished, &out.TTLSecondsAfterFinished
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -262,6 +825,15 @@ func (in *StorageVersionMigrationStatus) DeepCopyInto(out *StorageVersionMigrati
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
 	return
 }
 
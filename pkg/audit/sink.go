@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package audit writes the reports of the runs of the migrations to
+// durable sinks.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+)
+
+// Report is the record of a run of a migration.
+type Report struct {
+	// The name of the storageVersionMigration.
+	Migration string `json:"migration"`
+	// The UID of the storageVersionMigration, which owns its ConfigMap
+	// of reports.
+	MigrationUID types.UID `json:"-"`
+	// The resource migrated.
+	Resource migrationv1alpha1.GroupVersionResource `json:"resource"`
+	// The outcome of the run: Succeeded, Failed, or Interrupted if the run
+	// was stopped before the end, e.g. when its maintenance windows closed.
+	Outcome string `json:"outcome"`
+	// The error failing or interrupting the run.
+	Error string `json:"error,omitempty"`
+	// The counters collected by the migrator. Under the DryRunFirst
+	// strategy, only the last pass is reported: the dry run if it
+	// failed, the real pass otherwise.
+	migrator.Report
+}
+
+const (
+	OutcomeSucceeded   = "Succeeded"
+	OutcomeFailed      = "Failed"
+	OutcomeInterrupted = "Interrupted"
+)
+
+// Sink stores the reports.
+type Sink interface {
+	Write(ctx context.Context, r *Report) error
+}
+
+// Sinks writes the reports to all its sinks.
+type Sinks []Sink
+
+// Write writes r to all the sinks, and returns their errors.
+func (s Sinks) Write(ctx context.Context, r *Report) error {
+	var errs []error
+	for _, sink := range s {
+		if err := sink.Write(ctx, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// writerSink writes the reports as JSON lines to a writer.
+type writerSink struct {
+	lock sync.Mutex
+	w    io.Writer
+}
+
+// NewWriterSink returns a Sink writing every report as a JSON line to w,
+// e.g. os.Stdout.
+func NewWriterSink(w io.Writer) Sink {
+	return &writerSink{w: w}
+}
+
+func (s *writerSink) Write(_ context.Context, r *Report) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.w.Write(append(line, '\n'))
+	return err
+}
+
+// fileSink appends the reports as JSON lines to a file.
+type fileSink struct {
+	lock sync.Mutex
+	path string
+}
+
+// NewFileSink returns a Sink appending every report as a JSON line to the
+// file at path, which is created if it does not exist.
+func NewFileSink(path string) Sink {
+	return &fileSink{path: path}
+}
+
+func (s *fileSink) Write(_ context.Context, r *Report) error {
+	line, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	f, err := os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write the report of migration %s to %s: %v", r.Migration, s.path, err)
+	}
+	return f.Close()
+}
+
+// MigrationLabel is the label of the ConfigMaps of the reports, set to the
+// name of the migration.
+const MigrationLabel = "migration.k8s.io/migration"
+
+// configMapSink stores the reports of every migration in a ConfigMap.
+type configMapSink struct {
+	client    kubernetes.Interface
+	namespace string
+}
+
+// NewConfigMapSink returns a Sink storing the reports of every migration in
+// the ConfigMap migration-report-<migration> of the namespace. Every run
+// of the migration adds a key named after its start time, e.g.
+// 20260310T010000Z.json, so that the reports of a suspended and resumed
+// migration are all kept. The ConfigMap is owned by the migration, and is
+// garbage collected with it.
+func NewConfigMapSink(client kubernetes.Interface, namespace string) Sink {
+	return &configMapSink{client: client, namespace: namespace}
+}
+
+// ConfigMapName returns the name of the ConfigMap of the reports of a
+// migration.
+func ConfigMapName(migration string) string {
+	return "migration-report-" + migration
+}
+
+// ConfigMapKey returns the key of the report in its ConfigMap.
+func ConfigMapKey(r *Report) string {
+	return r.StartTime.UTC().Format("20060102T150405Z") + ".json"
+}
+
+func (s *configMapSink) Write(ctx context.Context, r *Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	configMaps := s.client.CoreV1().ConfigMaps(s.namespace)
+	name, key := ConfigMapName(r.Migration), ConfigMapKey(r)
+	cm, err := configMaps.Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.namespace,
+				Labels:    map[string]string{MigrationLabel: r.Migration},
+			},
+			Data: map[string]string{key: string(data)},
+		}
+		setOwner(cm, r)
+		_, err = configMaps.Create(ctx, cm, metav1.CreateOptions{})
+		return wrapConfigMapError(err, s.namespace, name)
+	}
+	if err != nil {
+		return wrapConfigMapError(err, s.namespace, name)
+	}
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+	cm.Data[key] = string(data)
+	setOwner(cm, r)
+	_, err = configMaps.Update(ctx, cm, metav1.UpdateOptions{})
+	return wrapConfigMapError(err, s.namespace, name)
+}
+
+// setOwner makes the migration of the report an owner of the ConfigMap, if it
+// is not yet. A migration deleted and created again with the same name is
+// another owner.
+func setOwner(cm *corev1.ConfigMap, r *Report) {
+	if r.MigrationUID == "" {
+		return
+	}
+	for _, ref := range cm.OwnerReferences {
+		if ref.UID == r.MigrationUID {
+			return
+		}
+	}
+	cm.OwnerReferences = append(cm.OwnerReferences, metav1.OwnerReference{
+		APIVersion: migrationv1alpha1.SchemeGroupVersion.String(),
+		Kind:       "StorageVersionMigration",
+		Name:       r.Migration,
+		UID:        r.MigrationUID,
+	})
+}
+
+func wrapConfigMapError(err error, namespace, name string) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("failed to write the report to the ConfigMap %s/%s: %v", namespace, name, err)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+)
+
+func newReport(migration string, start time.Time) *Report {
+	return &Report{
+		Migration:    migration,
+		MigrationUID: types.UID(migration + "-uid"),
+		Resource:     migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+		Outcome:      OutcomeFailed,
+		Error:        "update is not supported",
+		Report: migrator.Report{
+			StartTime:      start,
+			CompletionTime: start.Add(time.Minute),
+			Rewritten:      10,
+			Skipped:        1,
+			Failed:         1,
+			FailedObjects:  []migrator.ObjectFailure{{Namespace: "default", Name: "a", Reason: "update is not supported"}},
+			Retries:        2,
+		},
+	}
+}
+
+func decodeLines(t *testing.T, data []byte) []map[string]interface{} {
+	t.Helper()
+	var reports []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var r map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	start := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	if err := NewWriterSink(&buf).Write(context.TODO(), newReport("pods-1", start)); err != nil {
+		t.Fatal(err)
+	}
+	reports := decodeLines(t, buf.Bytes())
+	if len(reports) != 1 {
+		t.Fatalf("expected 1 report, got %d", len(reports))
+	}
+	r := reports[0]
+	// The counters of the migrator are inlined.
+	if r["migration"] != "pods-1" || r["outcome"] != "Failed" || r["rewritten"] != float64(10) || r["retries"] != float64(2) || r["startTime"] != "2026-03-10T01:00:00Z" {
+		t.Errorf("unexpected report %v", r)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports.jsonl")
+	sink := NewFileSink(path)
+	start := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	for _, name := range []string{"pods-1", "nodes-1"} {
+		if err := sink.Write(context.TODO(), newReport(name, start)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reports := decodeLines(t, data)
+	if len(reports) != 2 || reports[0]["migration"] != "pods-1" || reports[1]["migration"] != "nodes-1" {
+		t.Errorf("expected the reports to be appended, got %v", reports)
+	}
+}
+
+func TestConfigMapSink(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := NewConfigMapSink(client, "migrator")
+	first := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	second := time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC)
+	for _, start := range []time.Time{first, second} {
+		if err := sink.Write(context.TODO(), newReport("pods-1", start)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cm, err := client.CoreV1().ConfigMaps("migrator").Get(context.TODO(), "migration-report-pods-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cm.Labels[MigrationLabel] != "pods-1" {
+		t.Errorf("expected the migration label, got %v", cm.Labels)
+	}
+	owners := cm.OwnerReferences
+	if len(owners) != 1 || owners[0].Kind != "StorageVersionMigration" || owners[0].Name != "pods-1" || owners[0].UID != "pods-1-uid" {
+		t.Errorf("expected the ConfigMap to be owned by the migration, got %+v", owners)
+	}
+	if len(cm.Data) != 2 {
+		t.Fatalf("expected the reports of both runs, got %v", cm.Data)
+	}
+	var r Report
+	if err := json.Unmarshal([]byte(cm.Data["20260311T010000Z.json"]), &r); err != nil {
+		t.Fatal(err)
+	}
+	if !r.StartTime.Equal(second) || r.Failed != 1 || len(r.FailedObjects) != 1 {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
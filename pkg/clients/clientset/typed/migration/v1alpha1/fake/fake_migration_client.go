@@ -28,6 +28,18 @@ type FakeMigrationV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeMigrationV1alpha1) MigrationPlans() v1alpha1.MigrationPlanInterface {
+	return &FakeMigrationPlans{c}
+}
+
+func (c *FakeMigrationV1alpha1) MigrationPolicies() v1alpha1.MigrationPolicyInterface {
+	return &FakeMigrationPolicies{c}
+}
+
+func (c *FakeMigrationV1alpha1) MigrationSummaries() v1alpha1.MigrationSummaryInterface {
+	return &FakeMigrationSummaries{c}
+}
+
 func (c *FakeMigrationV1alpha1) StorageStates() v1alpha1.StorageStateInterface {
 	return &FakeStorageStates{c}
 }
@@ -18,6 +18,12 @@ limitations under the License.
 
 package v1alpha1
 
+type MigrationPlanExpansion interface{}
+
+type MigrationPolicyExpansion interface{}
+
+type MigrationSummaryExpansion interface{}
+
 type StorageStateExpansion interface{}
 
 type StorageVersionMigrationExpansion interface{}
@@ -26,6 +26,9 @@ import (
 
 type MigrationV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	MigrationPlansGetter
+	MigrationPoliciesGetter
+	MigrationSummariesGetter
 	StorageStatesGetter
 	StorageVersionMigrationsGetter
 }
@@ -35,6 +38,18 @@ type MigrationV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *MigrationV1alpha1Client) MigrationPlans() MigrationPlanInterface {
+	return newMigrationPlans(c)
+}
+
+func (c *MigrationV1alpha1Client) MigrationPolicies() MigrationPolicyInterface {
+	return newMigrationPolicies(c)
+}
+
+func (c *MigrationV1alpha1Client) MigrationSummaries() MigrationSummaryInterface {
+	return newMigrationSummaries(c)
+}
+
 func (c *MigrationV1alpha1Client) StorageStates() StorageStateInterface {
 	return newStorageStates(c)
 }
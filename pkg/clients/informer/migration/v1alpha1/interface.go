@@ -24,6 +24,12 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// MigrationPlans returns a MigrationPlanInformer.
+	MigrationPlans() MigrationPlanInformer
+	// MigrationPolicies returns a MigrationPolicyInformer.
+	MigrationPolicies() MigrationPolicyInformer
+	// MigrationSummaries returns a MigrationSummaryInformer.
+	MigrationSummaries() MigrationSummaryInformer
 	// StorageStates returns a StorageStateInformer.
 	StorageStates() StorageStateInformer
 	// StorageVersionMigrations returns a StorageVersionMigrationInformer.
@@ -41,6 +47,21 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// MigrationPlans returns a MigrationPlanInformer.
+func (v *version) MigrationPlans() MigrationPlanInformer {
+	return &migrationPlanInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// MigrationPolicies returns a MigrationPolicyInformer.
+func (v *version) MigrationPolicies() MigrationPolicyInformer {
+	return &migrationPolicyInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// MigrationSummaries returns a MigrationSummaryInformer.
+func (v *version) MigrationSummaries() MigrationSummaryInformer {
+	return &migrationSummaryInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // StorageStates returns a StorageStateInformer.
 func (v *version) StorageStates() StorageStateInformer {
 	return &storageStateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
@@ -18,6 +18,18 @@ limitations under the License.
 
 package v1alpha1
 
+// MigrationPlanListerExpansion allows custom methods to be added to
+// MigrationPlanLister.
+type MigrationPlanListerExpansion interface{}
+
+// MigrationPolicyListerExpansion allows custom methods to be added to
+// MigrationPolicyLister.
+type MigrationPolicyListerExpansion interface{}
+
+// MigrationSummaryListerExpansion allows custom methods to be added to
+// MigrationSummaryLister.
+type MigrationSummaryListerExpansion interface{}
+
 // StorageStateListerExpansion allows custom methods to be added to
 // StorageStateLister.
 type StorageStateListerExpansion interface{}
@@ -0,0 +1,309 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusteroperator reports the migration summary as the status of an
+// OpenShift ClusterOperator, through the dynamic client so that the
+// OpenShift APIs are not needed to build the migrator.
+package clusteroperator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// GroupVersionResource is the resource of the ClusterOperators.
+var GroupVersionResource = schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}
+
+// The types of the conditions of a ClusterOperator set by the Reporter.
+const (
+	Available   = "Available"
+	Progressing = "Progressing"
+	Degraded    = "Degraded"
+	Upgradeable = "Upgradeable"
+)
+
+// maxReportedResources limits the number of resources listed in the
+// messages of the conditions.
+const maxReportedResources = 5
+
+// condition is a condition of the status of a ClusterOperator.
+type condition struct {
+	Type               string      `json:"type"`
+	Status             string      `json:"status"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	Reason             string      `json:"reason,omitempty"`
+	Message            string      `json:"message,omitempty"`
+}
+
+// relatedObject is an object related to a ClusterOperator, collected when
+// debugging it.
+type relatedObject struct {
+	Group     string `json:"group"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+}
+
+// operandVersion is a version reported by a ClusterOperator.
+type operandVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Reporter maintains the status of a ClusterOperator from the migration
+// summary.
+type Reporter struct {
+	client    dynamic.Interface
+	name      string
+	namespace string
+	version   string
+}
+
+// NewReporter creates a Reporter of the ClusterOperator name. The namespace
+// of the migrator, if not empty, is listed in the related objects, and the
+// version, if not empty, is reported as the version of the operator.
+func NewReporter(client dynamic.Interface, name, namespace, version string) *Reporter {
+	return &Reporter{
+		client:    client,
+		name:      name,
+		namespace: namespace,
+		version:   version,
+	}
+}
+
+// Report updates the status of the ClusterOperator from the migration
+// summary, creating the ClusterOperator if it does not exist. The conditions
+// of the other types are kept.
+func (r *Reporter) Report(ctx context.Context, summary migrationv1alpha1.MigrationSummaryStatus) error {
+	client := r.client.Resource(GroupVersionResource)
+	co, err := client.Get(ctx, r.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		co = &unstructured.Unstructured{}
+		co.SetAPIVersion(GroupVersionResource.GroupVersion().String())
+		co.SetKind("ClusterOperator")
+		co.SetName(r.name)
+		co, err = client.Create(ctx, co, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	existing, err := statusConditions(co)
+	if err != nil {
+		return err
+	}
+	conditions := existing
+	for _, c := range operatorConditions(summary) {
+		conditions = setCondition(conditions, c)
+	}
+	status := map[string]interface{}{}
+	for key, value := range map[string]interface{}{
+		"conditions":     conditions,
+		"relatedObjects": r.relatedObjects(),
+		"versions":       r.versions(),
+	} {
+		u, err := toUnstructured(value)
+		if err != nil {
+			return err
+		}
+		status[key] = u
+	}
+	updated := co.DeepCopy()
+	for key, value := range status {
+		current, _, _ := unstructured.NestedFieldNoCopy(co.Object, "status", key)
+		if equality.Semantic.DeepEqual(current, value) {
+			continue
+		}
+		if err := unstructured.SetNestedField(updated.Object, value, "status", key); err != nil {
+			return err
+		}
+	}
+	if equality.Semantic.DeepEqual(co.Object, updated.Object) {
+		return nil
+	}
+	_, err = client.UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// operatorConditions maps the migration summary to the conditions of a
+// ClusterOperator. The LastTransitionTime of the conditions are not set.
+//   - Available is always True, the migrator runs.
+//   - Progressing is True while resources are being migrated.
+//   - Degraded is True if migrations are failing, or if the discovery is
+//     stale.
+//   - Upgradeable is True once all the resources are migrated.
+func operatorConditions(summary migrationv1alpha1.MigrationSummaryStatus) []condition {
+	available := condition{
+		Type:    Available,
+		Status:  "True",
+		Reason:  "AsExpected",
+		Message: "the storage version migrator is running",
+	}
+	progressing := condition{Type: Progressing, Status: "False", Reason: "AsExpected"}
+	if inProgress := blockingResources(summary, migrationv1alpha1.BlockingReasonMigrationInProgress); summary.InProgressResources > 0 {
+		progressing.Status = "True"
+		progressing.Reason = "MigrationsInProgress"
+		progressing.Message = fmt.Sprintf("migrating %d resources: %s", summary.InProgressResources, resourcesMessage(inProgress, int(summary.InProgressResources)))
+	}
+	degraded := condition{Type: Degraded, Status: "False", Reason: "AsExpected"}
+	var reasons, messages []string
+	if c := summaryCondition(summary, migrationv1alpha1.MigrationSummaryMigrationsFailing); c != nil && c.Status == "True" {
+		reasons = append(reasons, "MigrationsFailing")
+		messages = append(messages, c.Message)
+	}
+	if c := summaryCondition(summary, migrationv1alpha1.MigrationSummaryDiscoveryStale); c != nil && c.Status == "True" {
+		reasons = append(reasons, "DiscoveryStale")
+		messages = append(messages, c.Message)
+	}
+	if len(reasons) > 0 {
+		degraded.Status = "True"
+		degraded.Reason = strings.Join(reasons, "And")
+		degraded.Message = strings.Join(messages, "; ")
+	}
+	upgradeable := condition{Type: Upgradeable, Status: "True", Reason: "AsExpected", Message: "all the resources are migrated"}
+	if c := summaryCondition(summary, migrationv1alpha1.MigrationSummaryAllResourcesMigrated); c == nil || c.Status != "True" {
+		upgradeable.Status = "False"
+		upgradeable.Reason = "ResourcesNotMigrated"
+		var names []string
+		for _, b := range summary.BlockingResources {
+			names = append(names, controller.ResourceName(b.Resource.Group, b.Resource.Resource))
+		}
+		upgradeable.Message = fmt.Sprintf("%d of %d resources are not migrated: %s", summary.Resources-summary.MigratedResources, summary.Resources,
+			resourcesMessage(names, int(summary.Resources-summary.MigratedResources)))
+	}
+	return []condition{available, progressing, degraded, upgradeable}
+}
+
+func (r *Reporter) relatedObjects() []relatedObject {
+	var objects []relatedObject
+	if r.namespace != "" {
+		objects = append(objects, relatedObject{Resource: "namespaces", Name: r.namespace})
+	}
+	return append(objects,
+		relatedObject{Group: migrationv1alpha1.GroupName, Resource: "migrationsummaries", Name: migrationv1alpha1.MigrationSummaryName},
+		relatedObject{Group: migrationv1alpha1.GroupName, Resource: "storagestates"},
+		relatedObject{Group: migrationv1alpha1.GroupName, Resource: "storageversionmigrations"},
+		relatedObject{Group: migrationv1alpha1.GroupName, Resource: "migrationpolicies"},
+	)
+}
+
+func (r *Reporter) versions() []operandVersion {
+	if r.version == "" {
+		return nil
+	}
+	return []operandVersion{{Name: "operator", Version: r.version}}
+}
+
+// statusConditions returns the conditions of the ClusterOperator.
+func statusConditions(co *unstructured.Unstructured) ([]condition, error) {
+	l, _, err := unstructured.NestedSlice(co.Object, "status", "conditions")
+	if err != nil {
+		return nil, err
+	}
+	var conditions []condition
+	for _, obj := range l {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected the conditions of ClusterOperator %s to be objects, got %T", co.GetName(), obj)
+		}
+		var c condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &c); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// setCondition replaces the condition of the same type by c in conditions.
+// The LastTransitionTime is kept if the status does not change.
+func setCondition(conditions []condition, c condition) []condition {
+	c.LastTransitionTime = metav1.Now()
+	for i := range conditions {
+		if conditions[i].Type != c.Type {
+			continue
+		}
+		if conditions[i].Status == c.Status {
+			c.LastTransitionTime = conditions[i].LastTransitionTime
+		}
+		updated := append([]condition{}, conditions...)
+		updated[i] = c
+		return updated
+	}
+	return append(conditions, c)
+}
+
+// toUnstructured converts a list of the status to its unstructured form.
+func toUnstructured(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var u interface{}
+	if err := json.Unmarshal(b, &u); err != nil {
+		return nil, err
+	}
+	if u == nil {
+		// Unset lists are stored as empty lists, so that they compare
+		// equal to the lists read back.
+		u = []interface{}{}
+	}
+	return u, nil
+}
+
+func summaryCondition(summary migrationv1alpha1.MigrationSummaryStatus, conditionType migrationv1alpha1.MigrationSummaryConditionType) *migrationv1alpha1.MigrationSummaryCondition {
+	for i := range summary.Conditions {
+		if summary.Conditions[i].Type == conditionType {
+			return &summary.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// blockingResources returns the names of the blocking resources of the
+// summary with the reason.
+func blockingResources(summary migrationv1alpha1.MigrationSummaryStatus, reason string) []string {
+	var names []string
+	for _, b := range summary.BlockingResources {
+		if b.Reason == reason {
+			names = append(names, controller.ResourceName(b.Resource.Group, b.Resource.Resource))
+		}
+	}
+	return names
+}
+
+// resourcesMessage lists the first resources, out of total.
+func resourcesMessage(resources []string, total int) string {
+	if len(resources) > maxReportedResources {
+		resources = resources[:maxReportedResources]
+	}
+	message := strings.Join(resources, ", ")
+	if total > len(resources) {
+		message += fmt.Sprintf(" and %d more", total-len(resources))
+	}
+	return message
+}
@@ -0,0 +1,187 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusteroperator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+func newDynamicClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		GroupVersionResource: "ClusterOperatorList",
+	}, objects...)
+}
+
+func migratingSummary() migrationv1alpha1.MigrationSummaryStatus {
+	return migrationv1alpha1.MigrationSummaryStatus{
+		Resources:           3,
+		MigratedResources:   1,
+		InProgressResources: 1,
+		FailingResources:    1,
+		BlockingResources: []migrationv1alpha1.BlockingResource{
+			{Resource: migrationv1alpha1.GroupResource{Group: "example.com", Resource: "gadgets"}, Reason: migrationv1alpha1.BlockingReasonMigrationFailing},
+			{Resource: migrationv1alpha1.GroupResource{Resource: "pods"}, Reason: migrationv1alpha1.BlockingReasonMigrationInProgress},
+		},
+		Conditions: []migrationv1alpha1.MigrationSummaryCondition{
+			{Type: migrationv1alpha1.MigrationSummaryAllResourcesMigrated, Status: corev1.ConditionFalse},
+			{Type: migrationv1alpha1.MigrationSummaryMigrationsFailing, Status: corev1.ConditionTrue, Message: "the last migrations of gadgets.example.com failed"},
+			{Type: migrationv1alpha1.MigrationSummaryDiscoveryStale, Status: corev1.ConditionFalse},
+		},
+	}
+}
+
+func migratedSummary() migrationv1alpha1.MigrationSummaryStatus {
+	return migrationv1alpha1.MigrationSummaryStatus{
+		Resources:         3,
+		MigratedResources: 3,
+		Conditions: []migrationv1alpha1.MigrationSummaryCondition{
+			{Type: migrationv1alpha1.MigrationSummaryAllResourcesMigrated, Status: corev1.ConditionTrue},
+			{Type: migrationv1alpha1.MigrationSummaryMigrationsFailing, Status: corev1.ConditionFalse},
+			{Type: migrationv1alpha1.MigrationSummaryDiscoveryStale, Status: corev1.ConditionFalse},
+		},
+	}
+}
+
+func getConditions(t *testing.T, client *fake.FakeDynamicClient) map[string]condition {
+	t.Helper()
+	co, err := client.Resource(GroupVersionResource).Get(context.TODO(), "kube-storage-version-migrator", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := statusConditions(co)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conditions := map[string]condition{}
+	for _, c := range l {
+		conditions[c.Type] = c
+	}
+	return conditions
+}
+
+func expectCondition(t *testing.T, conditions map[string]condition, conditionType, status, reason, message string) {
+	t.Helper()
+	c, ok := conditions[conditionType]
+	if !ok {
+		t.Fatalf("expected the %s condition, got %v", conditionType, conditions)
+	}
+	if c.Status != status || c.Reason != reason || c.Message != message {
+		t.Errorf("expected %s condition %s %s %q, got %s %s %q", conditionType, status, reason, message, c.Status, c.Reason, c.Message)
+	}
+}
+
+func TestReport(t *testing.T) {
+	client := newDynamicClient()
+	r := NewReporter(client, "kube-storage-version-migrator", "openshift-kube-storage-version-migrator", "4.99.0")
+	if err := r.Report(context.TODO(), migratingSummary()); err != nil {
+		t.Fatal(err)
+	}
+	conditions := getConditions(t, client)
+	expectCondition(t, conditions, Available, "True", "AsExpected", "the storage version migrator is running")
+	expectCondition(t, conditions, Progressing, "True", "MigrationsInProgress", "migrating 1 resources: pods")
+	expectCondition(t, conditions, Degraded, "True", "MigrationsFailing", "the last migrations of gadgets.example.com failed")
+	expectCondition(t, conditions, Upgradeable, "False", "ResourcesNotMigrated", "2 of 3 resources are not migrated: gadgets.example.com, pods")
+
+	co, err := client.Resource(GroupVersionResource).Get(context.TODO(), "kube-storage-version-migrator", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	versions, _, _ := unstructured.NestedSlice(co.Object, "status", "versions")
+	if e := []interface{}{map[string]interface{}{"name": "operator", "version": "4.99.0"}}; !reflect.DeepEqual(versions, e) {
+		t.Errorf("expected versions %v, got %v", e, versions)
+	}
+	related, _, _ := unstructured.NestedSlice(co.Object, "status", "relatedObjects")
+	if len(related) != 5 || !reflect.DeepEqual(related[0], map[string]interface{}{"group": "", "resource": "namespaces", "name": "openshift-kube-storage-version-migrator"}) {
+		t.Errorf("unexpected related objects %v", related)
+	}
+
+	// Nothing is updated when the summary does not change.
+	client.ClearActions()
+	if err := r.Report(context.TODO(), migratingSummary()); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range client.Actions() {
+		if a.GetVerb() != "get" {
+			t.Errorf("expected no update, got %v", a)
+		}
+	}
+}
+
+func TestReportKeepsTransitionTimes(t *testing.T) {
+	long := metav1.NewTime(time.Now().Add(-24 * time.Hour).Truncate(time.Second))
+	co := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterOperator",
+		"metadata":   map[string]interface{}{"name": "kube-storage-version-migrator"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": Available, "status": "True", "lastTransitionTime": long.UTC().Format(time.RFC3339), "reason": "AsExpected"},
+				map[string]interface{}{"type": Upgradeable, "status": "False", "lastTransitionTime": long.UTC().Format(time.RFC3339), "reason": "ResourcesNotMigrated"},
+				map[string]interface{}{"type": "EvaluationConditionsDetected", "status": "False", "lastTransitionTime": long.UTC().Format(time.RFC3339)},
+			},
+		},
+	}}
+	client := newDynamicClient(co)
+	r := NewReporter(client, "kube-storage-version-migrator", "", "")
+	if err := r.Report(context.TODO(), migratedSummary()); err != nil {
+		t.Fatal(err)
+	}
+	conditions := getConditions(t, client)
+	if c := conditions[Available]; !c.LastTransitionTime.Equal(&long) {
+		t.Errorf("expected the transition time of Available to be kept, got %v", c.LastTransitionTime)
+	}
+	if c := conditions[Upgradeable]; c.Status != "True" || c.LastTransitionTime.Equal(&long) {
+		t.Errorf("expected Upgradeable to transition to True, got %+v", c)
+	}
+	if _, ok := conditions["EvaluationConditionsDetected"]; !ok {
+		t.Errorf("expected the conditions of other types to be kept, got %v", conditions)
+	}
+	expectCondition(t, conditions, Progressing, "False", "AsExpected", "")
+	expectCondition(t, conditions, Degraded, "False", "AsExpected", "")
+}
+
+func TestOperatorConditionsDiscoveryStale(t *testing.T) {
+	summary := migratingSummary()
+	summary.Conditions[2] = migrationv1alpha1.MigrationSummaryCondition{
+		Type:    migrationv1alpha1.MigrationSummaryDiscoveryStale,
+		Status:  corev1.ConditionTrue,
+		Message: "the last discovery failed: boom",
+	}
+	for _, c := range operatorConditions(summary) {
+		if c.Type != Degraded {
+			continue
+		}
+		if e, a := "MigrationsFailingAndDiscoveryStale", c.Reason; e != a {
+			t.Errorf("expected reason %s, got %s", e, a)
+		}
+		if e, a := "the last migrations of gadgets.example.com failed; the last discovery failed: boom", c.Message; e != a {
+			t.Errorf("expected message %q, got %q", e, a)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package config loads the configuration files of the trigger and the
+// migrator, and watches them for changes.
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/config/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/config/validation"
+)
+
+var (
+	scheme = runtime.NewScheme()
+	codecs = serializer.NewCodecFactory(scheme, serializer.EnableStrict)
+)
+
+func init() {
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		panic(err)
+	}
+}
+
+// decode decodes the file at path into obj, and sets the defaults. Unknown
+// and duplicate fields are errors.
+func decode(path string, obj runtime.Object) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	decoded, gvk, err := codecs.UniversalDecoder(v1alpha1.SchemeGroupVersion).Decode(data, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to decode %s: %v", path, err)
+	}
+	if reflect.TypeOf(decoded) != reflect.TypeOf(obj) {
+		return fmt.Errorf("%s contains a %s, expected a %T", path, gvk.Kind, obj)
+	}
+	return scheme.Convert(decoded, obj, nil)
+}
+
+// LoadTriggerConfiguration reads, defaults and validates the
+// TriggerConfiguration in the file at path.
+func LoadTriggerConfiguration(path string) (*v1alpha1.TriggerConfiguration, error) {
+	c := &v1alpha1.TriggerConfiguration{}
+	if err := decode(path, c); err != nil {
+		return nil, err
+	}
+	if errs := validation.ValidateTriggerConfiguration(c); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration in %s: %v", path, errs.ToAggregate())
+	}
+	return c, nil
+}
+
+// LoadMigratorConfiguration reads, defaults and validates the
+// MigratorConfiguration in the file at path.
+func LoadMigratorConfiguration(path string) (*v1alpha1.MigratorConfiguration, error) {
+	c := &v1alpha1.MigratorConfiguration{}
+	if err := decode(path, c); err != nil {
+		return nil, err
+	}
+	if errs := validation.ValidateMigratorConfiguration(c); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration in %s: %v", path, errs.ToAggregate())
+	}
+	return c, nil
+}
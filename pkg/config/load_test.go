@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, data string) {
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadTriggerConfiguration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `apiVersion: config.migration.k8s.io/v1alpha1
+kind: TriggerConfiguration
+discoveryPeriod: 5m
+retryPolicy:
+  maxAttempts: 0
+resources:
+  excludeGroups: [batch]
+`)
+	c, err := LoadTriggerConfiguration(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, a := 5*time.Minute, c.DiscoveryPeriod.Duration; e != a {
+		t.Errorf("expected discovery period %v, got %v", e, a)
+	}
+	if e, a := 30*time.Minute, c.StorageStateGracePeriod.Duration; e != a {
+		t.Errorf("expected the grace period to default to %v, got %v", e, a)
+	}
+	if e, a := int32(2), c.StaleHeartbeatMultiplier; e != a {
+		t.Errorf("expected stale heartbeat multiplier %v, got %v", e, a)
+	}
+	if e, a := int32(0), *c.RetryPolicy.MaxAttempts; e != a {
+		t.Errorf("expected the explicit max attempts %v to be kept, got %v", e, a)
+	}
+	if e, a := time.Minute, c.RetryPolicy.InitialBackoff.Duration; e != a {
+		t.Errorf("expected initial backoff %v, got %v", e, a)
+	}
+	if len(c.Resources.ExcludeGroups) != 1 || c.Resources.ExcludeGroups[0] != "batch" {
+		t.Errorf("unexpected resources %v", c.Resources)
+	}
+}
+
+func TestLoadConfigurationErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			name: "unknown field",
+			data: `apiVersion: config.migration.k8s.io/v1alpha1
+kind: TriggerConfiguration
+discoveryPeriods: 5m
+`,
+			expected: "discoveryPeriods",
+		},
+		{
+			name: "wrong kind",
+			data: `apiVersion: config.migration.k8s.io/v1alpha1
+kind: MigratorConfiguration
+`,
+			expected: "expected a *v1alpha1.TriggerConfiguration",
+		},
+		{
+			name: "invalid",
+			data: `apiVersion: config.migration.k8s.io/v1alpha1
+kind: TriggerConfiguration
+retryPolicy:
+  initialBackoff: 1h
+  maxBackoff: 1m
+`,
+			expected: "retryPolicy.maxBackoff",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			writeFile(t, path, tc.data)
+			_, err := LoadTriggerConfiguration(path)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("expected an error containing %q, got %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+func TestLoadMigratorConfiguration(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `apiVersion: config.migration.k8s.io/v1alpha1
+kind: MigratorConfiguration
+concurrency: 4
+leaderElection:
+  leaderElect: true
+`)
+	c, err := LoadMigratorConfiguration(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ChunkSize != 100 || c.Concurrency != 4 {
+		t.Errorf("unexpected chunk size %d and concurrency %d", c.ChunkSize, c.Concurrency)
+	}
+	// The lease name is defaulted by the commands.
+	if a := c.LeaderElection.ResourceName; a != "" {
+		t.Errorf("expected no lease name, got %q", a)
+	}
+}
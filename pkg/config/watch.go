@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"k8s.io/klog/v2"
+)
+
+// Watch calls onChange every time the content of the file at path changes,
+// until ctx is done. The directory of the file is watched rather than the
+// file, so that files replaced by renames, like the files of mounted
+// configMaps, are followed.
+func Watch(ctx context.Context, path string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		watcher.Close()
+		return err
+	}
+	last, _ := os.ReadFile(path)
+	go func() {
+		defer watcher.Close()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				klog.Errorf("failed to watch %s: %v", path, err)
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				data, err := os.ReadFile(path)
+				if err != nil || bytes.Equal(data, last) {
+					// The file is being replaced, or another file
+					// of the directory changed.
+					continue
+				}
+				last = data
+				onChange()
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "a")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan struct{}, 10)
+	if err := Watch(ctx, path, func() { changes <- struct{}{} }); err != nil {
+		t.Fatal(err)
+	}
+
+	// Another file of the directory changes.
+	writeFile(t, filepath.Join(dir, "other"), "b")
+	// The file is replaced by a rename, like a mounted configMap.
+	writeFile(t, filepath.Join(dir, "config.yaml.new"), "c")
+	if err := os.Rename(filepath.Join(dir, "config.yaml.new"), path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-changes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a change")
+	}
+	select {
+	case <-changes:
+		t.Fatal("expected a single change")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
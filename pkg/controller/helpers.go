@@ -26,6 +26,24 @@ func HasCondition(m *migrationv1alpha1.StorageVersionMigration, conditionType mi
 	return indexOfCondition(m, conditionType) != -1
 }
 
+// IsFinished returns true if the migration will not be run anymore, because
+// it has succeeded, failed, or been superseded by a newer migration.
+func IsFinished(m *migrationv1alpha1.StorageVersionMigration) bool {
+	return HasCondition(m, migrationv1alpha1.MigrationSucceeded) ||
+		HasCondition(m, migrationv1alpha1.MigrationFailed) ||
+		HasCondition(m, migrationv1alpha1.MigrationSuperseded)
+}
+
+// GetCondition returns the condition of the given type if it is true, or nil
+// otherwise.
+func GetCondition(m *migrationv1alpha1.StorageVersionMigration, conditionType migrationv1alpha1.MigrationConditionType) *migrationv1alpha1.MigrationCondition {
+	i := indexOfCondition(m, conditionType)
+	if i == -1 {
+		return nil
+	}
+	return &m.Status.Conditions[i]
+}
+
 func indexOfCondition(m *migrationv1alpha1.StorageVersionMigration, conditionType migrationv1alpha1.MigrationConditionType) int {
 	for i, c := range m.Status.Conditions {
 		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
@@ -42,3 +60,93 @@ func resource(m *migrationv1alpha1.StorageVersionMigration) schema.GroupVersionR
 		Resource: m.Spec.Resource.Resource,
 	}
 }
+
+// HasStorageStateCondition returns true if the storageState has a true
+// condition of the given type.
+func HasStorageStateCondition(ss *migrationv1alpha1.StorageState, conditionType migrationv1alpha1.StorageStateConditionType) bool {
+	for _, c := range ss.Status.Conditions {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStorageStateCondition returns the condition of the given type of the
+// storageState, or nil if there is none.
+func GetStorageStateCondition(ss *migrationv1alpha1.StorageState, conditionType migrationv1alpha1.StorageStateConditionType) *migrationv1alpha1.StorageStateCondition {
+	for i := range ss.Status.Conditions {
+		if ss.Status.Conditions[i].Type == conditionType {
+			return &ss.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// conditionKey is what tells apart two conditions of the same type, except
+// their update time.
+type conditionKey struct {
+	conditionType string
+	status        corev1.ConditionStatus
+	reason        string
+	message       string
+}
+
+// setCondition returns a copy of the conditions with the condition added,
+// replacing the existing condition of the same type. The existing condition,
+// and its LastUpdateTime, is kept if the status, reason and message do not
+// change. key returns the conditionKey of a condition.
+func setCondition[C any](conditions []C, condition C, key func(C) conditionKey) []C {
+	updated := make([]C, 0, len(conditions)+1)
+	k, found := key(condition), false
+	for _, c := range conditions {
+		if ck := key(c); ck.conditionType == k.conditionType {
+			found = true
+			if ck != k {
+				c = condition
+			}
+		}
+		updated = append(updated, c)
+	}
+	if !found {
+		updated = append(updated, condition)
+	}
+	return updated
+}
+
+// SetStorageStateCondition adds the condition to the storageState, replacing
+// the existing condition of the same type. The LastUpdateTime of the existing
+// condition is kept if the status, reason and message do not change.
+func SetStorageStateCondition(ss *migrationv1alpha1.StorageState, condition migrationv1alpha1.StorageStateCondition) {
+	ss.Status.Conditions = setCondition(ss.Status.Conditions, condition, func(c migrationv1alpha1.StorageStateCondition) conditionKey {
+		return conditionKey{string(c.Type), c.Status, c.Reason, c.Message}
+	})
+}
+
+// SetPolicyCondition returns the conditions of a migration policy with the
+// condition added, like SetStorageStateCondition.
+func SetPolicyCondition(conditions []migrationv1alpha1.MigrationPolicyCondition, condition migrationv1alpha1.MigrationPolicyCondition) []migrationv1alpha1.MigrationPolicyCondition {
+	return setCondition(conditions, condition, func(c migrationv1alpha1.MigrationPolicyCondition) conditionKey {
+		return conditionKey{string(c.Type), c.Status, c.Reason, c.Message}
+	})
+}
+
+// SetSummaryCondition returns the conditions of the migration summary with
+// the condition added, like SetStorageStateCondition.
+func SetSummaryCondition(conditions []migrationv1alpha1.MigrationSummaryCondition, condition migrationv1alpha1.MigrationSummaryCondition) []migrationv1alpha1.MigrationSummaryCondition {
+	return setCondition(conditions, condition, func(c migrationv1alpha1.MigrationSummaryCondition) conditionKey {
+		return conditionKey{string(c.Type), c.Status, c.Reason, c.Message}
+	})
+}
+
+// RemoveStorageStateCondition removes the condition of the given type from
+// the storageState.
+func RemoveStorageStateCondition(ss *migrationv1alpha1.StorageState, conditionType migrationv1alpha1.StorageStateConditionType) {
+	var conditions []migrationv1alpha1.StorageStateCondition
+	for _, c := range ss.Status.Conditions {
+		if c.Type != conditionType {
+			conditions = append(conditions, c)
+		}
+	}
+	ss.Status.Conditions = conditions
+}
@@ -41,7 +41,7 @@ func migrationStatusIndexFunc(obj interface{}) ([]string, error) {
 	if !ok {
 		return []string{}, fmt.Errorf("expected StroageVersionMigration, got %#v", reflect.TypeOf(obj))
 	}
-	if HasCondition(m, migration_v1alpha1.MigrationSucceeded) || HasCondition(m, migration_v1alpha1.MigrationFailed) {
+	if IsFinished(m) {
 		return []string{StatusCompleted}, nil
 	}
 	if HasCondition(m, migration_v1alpha1.MigrationRunning) {
@@ -70,3 +70,21 @@ func migrationResourceIndexFunc(obj interface{}) ([]string, error) {
 func NewStatusAndResourceIndexedInformer(c migrationclient.Interface) cache.SharedIndexInformer {
 	return migrationinformer.NewStorageVersionMigrationInformer(c, 0, cache.Indexers{StatusIndex: migrationStatusIndexFunc, ResourceIndex: migrationResourceIndexFunc})
 }
+
+// AddMigrationIndexers adds the status and the resource indexers to the
+// informer of the storageVersionMigrations, unless it already has them. It
+// must be called before the informer starts.
+func AddMigrationIndexers(informer cache.SharedIndexInformer) error {
+	indexers := cache.Indexers{}
+	existing := informer.GetIndexer().GetIndexers()
+	if _, ok := existing[StatusIndex]; !ok {
+		indexers[StatusIndex] = migrationStatusIndexFunc
+	}
+	if _, ok := existing[ResourceIndex]; !ok {
+		indexers[ResourceIndex] = migrationResourceIndexFunc
+	}
+	if len(indexers) == 0 {
+		return nil
+	}
+	return informer.AddIndexers(indexers)
+}
@@ -22,9 +22,11 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/cache"
 	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer"
 )
 
 func newMigration(name string, conditionType migrationv1alpha1.MigrationConditionType) *migrationv1alpha1.StorageVersionMigration {
@@ -64,7 +66,12 @@ func TestStatusIndexedInformer(t *testing.T) {
 			Name: "Pending",
 		},
 	}
-	client := fake.NewSimpleClientset(running, succeeded, failed, pending)
+	superseded := newMigration("Superseded", migrationv1alpha1.MigrationRunning)
+	superseded.Status.Conditions = append(superseded.Status.Conditions, migrationv1alpha1.MigrationCondition{
+		Type:   migrationv1alpha1.MigrationSuperseded,
+		Status: corev1.ConditionTrue,
+	})
+	client := fake.NewSimpleClientset(running, succeeded, failed, superseded, pending)
 	informer := NewStatusIndexedInformer(client)
 
 	stopCh := make(chan struct{})
@@ -90,11 +97,12 @@ func TestStatusIndexedInformer(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	switch {
-	case reflect.DeepEqual(ret[0], failed) && reflect.DeepEqual(ret[1], succeeded):
-	case reflect.DeepEqual(ret[1], failed) && reflect.DeepEqual(ret[0], succeeded):
-	default:
-		t.Errorf("expected one successful, one failed, got %v", ret)
+	completed := sets.NewString()
+	for _, m := range ret {
+		completed.Insert(m.(*migrationv1alpha1.StorageVersionMigration).Name)
+	}
+	if e, a := sets.NewString("Succeeded", "Failed", "Superseded"), completed; !e.Equal(a) {
+		t.Errorf("expected completed migrations %v, got %v", e.List(), a.List())
 	}
 }
 
@@ -150,3 +158,40 @@ func TestResourceIndexedInformer(t *testing.T) {
 		t.Errorf("expected %v, got %v", e, a)
 	}
 }
+
+func TestAddMigrationIndexers(t *testing.T) {
+	podsR := migrationv1alpha1.GroupVersionResource{Group: "core", Version: "v1", Resource: "pods"}
+	pods := newMigrationForResource("pods", podsR)
+	pods.Status = newMigration("pods", migrationv1alpha1.MigrationRunning).Status
+
+	client := fake.NewSimpleClientset(pods)
+	factory := informer.NewSharedInformerFactory(client, 0)
+	migrationInformer := factory.Migration().V1alpha1().StorageVersionMigrations().Informer()
+	if err := AddMigrationIndexers(migrationInformer); err != nil {
+		t.Fatal(err)
+	}
+	// Adding the indexers again is a no-op.
+	if err := AddMigrationIndexers(migrationInformer); err != nil {
+		t.Fatal(err)
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+
+	cache.WaitForCacheSync(stopCh, migrationInformer.HasSynced)
+	ret, err := migrationInformer.GetIndexer().ByIndex(StatusIndex, StatusRunning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 || !reflect.DeepEqual(pods, ret[0]) {
+		t.Errorf("expected [%v], got %v", pods, ret)
+	}
+	ret, err = migrationInformer.GetIndexer().ByIndex(ResourceIndex, ToIndex(podsR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ret) != 1 || !reflect.DeepEqual(pods, ret[0]) {
+		t.Errorf("expected [%v], got %v", pods, ret)
+	}
+}
@@ -19,20 +19,29 @@ package controller
 import (
 	"context"
 	"fmt"
-	"reflect"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/audit"
 	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
+	migrationinformer "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/engine"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator/metrics"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/notification"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/schedule"
 )
 
 // KubeMigrator monitors storageVersionMigraiton objects, fulfills the
@@ -41,98 +50,483 @@ type KubeMigrator struct {
 	dynamic           dynamic.Interface
 	migrationClient   migrationclient.Interface
 	migrationInformer cache.SharedIndexInformer
+	// runInformer is true if the KubeMigrator owns migrationInformer.
+	runInformer bool
+	// policyInformer caches the migration policies. It is nil once Run
+	// finds the CRD of the policies is not installed.
+	policyInformer cache.SharedIndexInformer
+
+	// runningLock protects running and stopRunning.
+	runningLock sync.Mutex
+	// The name of the migration being run.
+	running string
+	// stopRunning cancels the context of the migration being run.
+	stopRunning context.CancelFunc
+
+	// optionsLock protects options, windows, agingPeriod, notifier and
+	// auditSink.
+	optionsLock sync.Mutex
+	// The options of the migrators of the next migrations.
+	options migrator.Options
+	// The maintenance windows of the resources without policy windows.
+	windows schedule.Windows
+	// How long a migration waits pending before its priority grows by one.
+	agingPeriod time.Duration
+	// notifier posts the state transitions of the migrations to webhooks.
+	notifier *notification.Notifier
+	// auditSink stores the reports of the runs of the migrations.
+	auditSink audit.Sink
+
+	// clock tells the time the maintenance windows are checked against.
+	clock clock.PassiveClock
+
+	// discovery and accessReviews check the resources of the migrations
+	// before they are run, if they are set.
+	discovery     discovery.ServerResourcesInterface
+	accessReviews authorizationclient.SelfSubjectAccessReviewInterface
 }
 
 // NewKubeMigrator creates KubeMigrator.
-func NewKubeMigrator(dynamic dynamic.Interface, migrationClient migrationclient.Interface) *KubeMigrator {
-	informer := NewStatusIndexedInformer(migrationClient)
-	return &KubeMigrator{
+func NewKubeMigrator(dynamic dynamic.Interface, migrationClient migrationclient.Interface, options migrator.Options) *KubeMigrator {
+	km := NewKubeMigratorWithInformer(dynamic, migrationClient, NewStatusIndexedInformer(migrationClient), options)
+	km.runInformer = true
+	return km
+}
+
+// NewKubeMigratorWithInformer creates KubeMigrator using an informer of the
+// storageVersionMigrations shared with other controllers, which has the
+// status indexer. The owner of the informer runs it.
+func NewKubeMigratorWithInformer(dynamic dynamic.Interface, migrationClient migrationclient.Interface, informer cache.SharedIndexInformer, options migrator.Options) *KubeMigrator {
+	km := &KubeMigrator{
 		dynamic:           dynamic,
 		migrationClient:   migrationClient,
 		migrationInformer: informer,
+		policyInformer:    migrationinformer.NewMigrationPolicyInformer(migrationClient, 0, cache.Indexers{}),
+		options:           options,
+		agingPeriod:       DefaultPriorityAgingPeriod,
+		clock:             clock.RealClock{},
 	}
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: km.updateMigration,
+	})
+	return km
+}
+
+// updateMigration stops the running migration once it is superseded.
+func (km *KubeMigrator) updateMigration(oldObj, obj interface{}) {
+	m, ok := obj.(*migrationv1alpha1.StorageVersionMigration)
+	if !ok || !HasCondition(m, migrationv1alpha1.MigrationSuperseded) {
+		return
+	}
+	km.runningLock.Lock()
+	defer km.runningLock.Unlock()
+	if km.running == m.Name && km.stopRunning != nil {
+		klog.V(2).Infof("%v: migration superseded, stopping", m.Name)
+		km.stopRunning()
+	}
+}
+
+// UpdateOptions replaces the options of the migrators. The running
+// migration keeps its options, the new ones apply from the next migration.
+func (km *KubeMigrator) UpdateOptions(options migrator.Options) {
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	km.options = options
+}
+
+// SetPriorityAgingPeriod replaces how long a migration waits pending before
+// its priority grows by one. 0 disables the aging.
+func (km *KubeMigrator) SetPriorityAgingPeriod(agingPeriod time.Duration) {
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	km.agingPeriod = agingPeriod
+}
+
+// SetNotifier replaces the notifier of the state transitions of the
+// migrations. nil disables the notifications.
+func (km *KubeMigrator) SetNotifier(notifier *notification.Notifier) {
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	km.notifier = notifier
+}
+
+// SetAuditSink replaces the sink of the reports of the runs of the
+// migrations. nil disables the reports.
+func (km *KubeMigrator) SetAuditSink(sink audit.Sink) {
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	km.auditSink = sink
+}
+
+func (km *KubeMigrator) priorityAgingPeriod() time.Duration {
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	return km.agingPeriod
+}
+
+func (km *KubeMigrator) migratorOptions() migrator.Options {
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	return km.options
+}
+
+func (km *KubeMigrator) setRunning(name string, stop context.CancelFunc) {
+	km.runningLock.Lock()
+	defer km.runningLock.Unlock()
+	km.running = name
+	km.stopRunning = stop
 }
 
 func (km *KubeMigrator) Run(ctx context.Context) {
 	defer utilruntime.HandleCrash()
-	go km.migrationInformer.Run(ctx.Done())
+	if km.runInformer {
+		go km.migrationInformer.Run(ctx.Done())
+	}
 	if !cache.WaitForCacheSync(ctx.Done(), km.migrationInformer.HasSynced) {
 		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
 		return
 	}
+	km.runPolicyInformer(ctx)
 	wait.UntilWithContext(ctx, km.process, time.Second)
 }
 
+// policySyncTimeout bounds how long Run waits for the cache of the migration
+// policies, before it runs the migrations without them.
+const policySyncTimeout = 30 * time.Second
+
+// runPolicyInformer runs the informer of the migration policies, unless
+// their CRD is not installed, and waits for its cache to sync.
+func (km *KubeMigrator) runPolicyInformer(ctx context.Context) {
+	_, err := km.migrationClient.MigrationV1alpha1().MigrationPolicies().List(ctx, metav1.ListOptions{Limit: 1})
+	if errors.IsNotFound(err) {
+		klog.Infof("the MigrationPolicy CRD is not installed, the migration policies are ignored")
+		km.policyInformer = nil
+		return
+	}
+	go km.policyInformer.Run(ctx.Done())
+	syncCtx, cancel := context.WithTimeout(ctx, policySyncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), km.policyInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("the migration policies are not synced yet, the migrations run without them until they are"))
+	}
+}
+
 func (km *KubeMigrator) process(ctx context.Context) {
 	// KubeMigrator has only one worker, so it doesn't need to use a
 	// workqueue to ensure only there is a single thread processing a
 	// storageVersionMigration.
 
 	// The already "Running" storageVersionMigrations are the priority.
-	runnings, err := km.migrationInformer.GetIndexer().ByIndex(StatusIndex, StatusRunning)
+	runnings, err := km.sortedMigrations(StatusRunning)
 	if err != nil {
 		utilruntime.HandleError(err)
 		return
 	}
-	if len(runnings) != 0 {
-		utilruntime.HandleError(km.processOne(ctx, runnings[0]))
-		return
-	}
-
-	// The next priority is the pending storageVersionMigrations.
-	pendings, err := km.migrationInformer.GetIndexer().ByIndex(StatusIndex, StatusPending)
+	// The next priority is the pending storageVersionMigrations, by
+	// decreasing effective priority, then by creation time.
+	pendings, err := km.sortedMigrations(StatusPending)
 	if err != nil {
 		utilruntime.HandleError(err)
 		return
 	}
-	if len(pendings) != 0 {
-		utilruntime.HandleError(km.processOne(ctx, pendings[0]))
+	km.observePendings(pendings)
+	candidates := append(runnings, pendings...)
+	if len(candidates) == 0 {
 		return
 	}
+	policies := km.policies()
+	// The migrations whose maintenance windows are closed are skipped.
+	for _, m := range candidates {
+		waiting, err := km.processOne(ctx, m, policies)
+		utilruntime.HandleError(err)
+		if !waiting {
+			return
+		}
+	}
 }
 
-func (km *KubeMigrator) processOne(ctx context.Context, obj interface{}) error {
-	m, ok := obj.(*migrationv1alpha1.StorageVersionMigration)
-	if !ok {
-		return fmt.Errorf("expected StorageVersionMigration, got %#v", reflect.TypeOf(obj))
+// sortedMigrations returns the migrations of the status in the order they
+// are run.
+func (km *KubeMigrator) sortedMigrations(status string) ([]*migrationv1alpha1.StorageVersionMigration, error) {
+	objs, err := km.migrationInformer.GetIndexer().ByIndex(StatusIndex, status)
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := toMigrations(objs)
+	if err != nil {
+		return nil, err
+	}
+	SortMigrations(migrations, km.clock.Now(), km.priorityAgingPeriod())
+	return migrations, nil
+}
+
+// observePendings records the order of the pending migrations in the
+// metrics. Only the first pending migration of a resource is recorded.
+func (km *KubeMigrator) observePendings(pendings []*migrationv1alpha1.StorageVersionMigration) {
+	now, agingPeriod := km.clock.Now(), km.priorityAgingPeriod()
+	metrics.Metrics.ResetPendingMigrations()
+	observed := map[string]bool{}
+	for i, m := range pendings {
+		r := resource(m).String()
+		if observed[r] {
+			continue
+		}
+		observed[r] = true
+		metrics.Metrics.ObservePendingMigration(r, i+1, EffectivePriority(m, now, agingPeriod))
+		klog.V(4).Infof("%v: pending migration at position %d", m.Name, i+1)
+	}
+}
+
+// processOne runs the migration m, unless the maintenance windows of its
+// resource are closed, in which case it returns true.
+func (km *KubeMigrator) processOne(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration, policies *MigrationPolicies) (bool, error) {
+	policy := policies.Resolve(m.Spec.Resource.Group, m.Spec.Resource.Resource)
+	windows, err := km.maintenanceWindows(policy)
+	if err != nil {
+		return true, km.wait(ctx, m, migrationv1alpha1.MigrationWaitingReasonInvalidMaintenanceWindow, err.Error())
+	}
+	now := km.clock.Now()
+	if !windows.Open(now) {
+		suspended := HasCondition(m, migrationv1alpha1.MigrationRunning)
+		return true, km.wait(ctx, m, migrationv1alpha1.MigrationWaitingReasonOutsideMaintenanceWindow, waitingMessage(windows, now, suspended))
 	}
 	// get the fresh object from the apiserver to make sure the object
 	// still exists, and the object is not completed.
-	m, err := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, m.Name, metav1.GetOptions{})
+	m, err = km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, m.Name, metav1.GetOptions{})
 	if err != nil {
-		return err
+		return false, err
 	}
-	if HasCondition(m, migrationv1alpha1.MigrationSucceeded) || HasCondition(m, migrationv1alpha1.MigrationFailed) {
+	if IsFinished(m) {
 		klog.V(2).Infof("%v: migration has already completed", m.Name)
-		return nil
+		return false, nil
+	}
+	err = checkPolicy(m, policy)
+	if err == nil {
+		err = km.preflight(ctx, m)
 	}
-	m, err = km.updateStatus(ctx, m, migrationv1alpha1.MigrationRunning, "")
-	klog.V(2).Infof("%v: migration running", m.Name)
 	if err != nil {
-		return err
+		failed, ok := err.(*preflightError)
+		if !ok {
+			return false, err
+		}
+		klog.Errorf("%v: migration failed the preflight checks: %v", m.Name, err)
+		if _, err := km.updateStatusWithReason(ctx, m, migrationv1alpha1.MigrationFailed, failed.reason, failed.message); err != nil {
+			utilruntime.HandleError(err)
+		}
+		metrics.Metrics.ObserveFailedMigration(resource(m).String())
+		km.notify(m, notification.OutcomeFailed, now, 0, failed.message)
+		return false, nil
+	}
+	options, strategy := km.policyOptions(policy)
+	if !HasCondition(m, migrationv1alpha1.MigrationRunning) && !m.CreationTimestamp.IsZero() {
+		metrics.Metrics.ObserveMigrationWait(resource(m).String(), now.Sub(m.CreationTimestamp.Time))
 	}
-	progressTracker := migrator.NewProgressTracker(km.migrationClient.MigrationV1alpha1().StorageVersionMigrations(), m.Name)
-	core := migrator.NewMigrator(resource(m), km.dynamic, progressTracker)
 	// If the storageVersionMigration object is deleted during Run(), Run()
 	// will return an error when it tries to write the continueToken into the
 	// migration object. Thus, it's not necessary to register a deletion
 	// event handler with the migrationInformer to interrupt the Run().
-	err = core.Run(ctx)
+	// The migration is interrupted by cancelling runCtx if it is
+	// superseded, or when the maintenance windows close. The progress
+	// tracker has saved the continue token of the last migrated chunk, so
+	// the migration resumes from it.
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if closing := windows.Close(now); !closing.IsZero() {
+		var cancelTimeout context.CancelFunc
+		runCtx, cancelTimeout = context.WithTimeoutCause(runCtx, closing.Sub(now), errMaintenanceWindowClosed)
+		defer cancelTimeout()
+	}
+	// The cancel is registered before the Running condition is set, so
+	// that a migration superseded meanwhile is stopped.
+	km.setRunning(m.Name, cancel)
+	// A resumed migration has started running before.
+	resumed := m.Status.StartTime != nil
+	m, err = km.updateStatus(ctx, m, migrationv1alpha1.MigrationRunning, "")
+	if err != nil {
+		km.setRunning("", nil)
+		return false, err
+	}
+	if HasCondition(m, migrationv1alpha1.MigrationSuperseded) {
+		km.setRunning("", nil)
+		klog.V(2).Infof("%v: migration superseded before it started running", m.Name)
+		return false, nil
+	}
+	klog.V(2).Infof("%v: migration running", m.Name)
+	if !resumed {
+		km.notify(m, notification.OutcomeRunning, now, 0, "")
+	}
+	report, err := km.run(runCtx, m, options, strategy)
+	km.setRunning("", nil)
+	km.writeReport(m, report, err, runCtx.Err() != nil)
+	if err != nil && runCtx.Err() != nil && ctx.Err() == nil {
+		if context.Cause(runCtx) == errMaintenanceWindowClosed {
+			klog.V(2).Infof("%v: migration suspended because the maintenance windows closed", m.Name)
+			return true, km.wait(ctx, m, migrationv1alpha1.MigrationWaitingReasonOutsideMaintenanceWindow, waitingMessage(windows, km.clock.Now(), true))
+		}
+		klog.V(2).Infof("%v: migration stopped because it is superseded", m.Name)
+		return false, nil
+	}
 	utilruntime.HandleError(err)
+	// get the fresh object, whose status has the progress saved by the run.
+	if fresh, getErr := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, m.Name, metav1.GetOptions{}); getErr == nil {
+		m = fresh
+	}
 	if err == nil {
 		if _, err := km.updateStatus(ctx, m, migrationv1alpha1.MigrationSucceeded, ""); err != nil {
 			utilruntime.HandleError(err)
 		}
 		metrics.Metrics.ObserveSucceededMigration(resource(m).String())
+		km.notify(m, notification.OutcomeSucceeded, now, report.Rewritten, "")
 		klog.V(2).Infof("%v: migration succeeded", m.Name)
-		return err
+		return false, err
 	}
 	klog.Errorf("%v: migration failed: %v", m.Name, err)
 	if _, err := km.updateStatus(ctx, m, migrationv1alpha1.MigrationFailed, err.Error()); err != nil {
 		utilruntime.HandleError(err)
 	}
 	metrics.Metrics.ObserveFailedMigration(resource(m).String())
-	return err
+	km.notify(m, notification.OutcomeFailed, now, report.Rewritten, err.Error())
+	return false, err
+}
+
+// wait sets the Waiting condition of the migration m if it does not have
+// it yet.
+func (km *KubeMigrator) wait(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration, reason, message string) error {
+	if isWaiting(m, reason, message) {
+		return nil
+	}
+	klog.V(2).Infof("%v: migration waiting: %s", m.Name, message)
+	// get the fresh object to keep the progress of the migration.
+	m, err := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, m.Name, metav1.GetOptions{})
+	if err != nil || IsFinished(m) {
+		return err
+	}
+	return km.setWaiting(ctx, m, reason, message)
+}
+
+// policies returns the migration policies in the cache, or nil if their CRD
+// is not installed or the cache has not synced yet.
+func (km *KubeMigrator) policies() *MigrationPolicies {
+	if km.policyInformer == nil {
+		return nil
+	}
+	if !km.policyInformer.HasSynced() {
+		klog.V(2).Infof("the migration policies are not synced yet, ignoring them")
+		return nil
+	}
+	var policies []migrationv1alpha1.MigrationPolicy
+	for _, obj := range km.policyInformer.GetStore().List() {
+		policy, ok := obj.(*migrationv1alpha1.MigrationPolicy)
+		if !ok {
+			utilruntime.HandleError(fmt.Errorf("expected MigrationPolicy, got %#v", obj))
+			continue
+		}
+		policies = append(policies, *policy)
+	}
+	return NewMigrationPolicies(policies)
+}
+
+// policyOptions returns the options of the migrator and the strategy of a
+// migration, as set by the migration policies matching its resource.
+func (km *KubeMigrator) policyOptions(policy ResolvedPolicy) (migrator.Options, migrationv1alpha1.MigrationStrategy) {
+	options := km.migratorOptions()
+	if policy.ChunkSize != nil {
+		options.ChunkLimit = *policy.ChunkSize
+	}
+	if policy.Concurrency != nil {
+		options.Concurrency = int(*policy.Concurrency)
+	}
+	strategy := policy.Strategy
+	if strategy == "" {
+		strategy = migrationv1alpha1.MigrationStrategyDirect
+	}
+	return options, strategy
+}
+
+// run migrates the resource of m. With the DryRunFirst strategy, the objects
+// are first rewritten in dry-run mode, unless the migration has already
+// started rewriting them. It returns the report of the last run of the
+// migrator.
+func (km *KubeMigrator) run(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration, options migrator.Options, strategy migrationv1alpha1.MigrationStrategy) (migrator.Report, error) {
+	e, err := engine.New(engine.Options{
+		Resource:    resource(m),
+		Client:      km.dynamic,
+		Progress:    migrator.NewMigrationProgressStore(km.migrationClient.MigrationV1alpha1().StorageVersionMigrations(), m.Name, migrator.DefaultCheckpointInterval),
+		Strategy:    engine.Strategy(strategy),
+		ChunkSize:   options.ChunkLimit,
+		Concurrency: options.Concurrency,
+	})
+	if err != nil {
+		return migrator.Report{}, err
+	}
+	return e.Run(ctx)
+}
+
+// reportTimeout limits the writing of a report, which outlives the context
+// of the migration.
+const reportTimeout = 30 * time.Second
+
+// writeReport writes the report of the run of the migration m to the audit
+// sink. interrupted is true if the run was stopped before the end.
+func (km *KubeMigrator) writeReport(m *migrationv1alpha1.StorageVersionMigration, report migrator.Report, err error, interrupted bool) {
+	km.optionsLock.Lock()
+	sink := km.auditSink
+	km.optionsLock.Unlock()
+	if sink == nil {
+		return
+	}
+	r := &audit.Report{
+		Migration:    m.Name,
+		MigrationUID: m.UID,
+		Resource:     m.Spec.Resource,
+		Outcome:      audit.OutcomeSucceeded,
+		Report:       report,
+	}
+	switch {
+	case err != nil && interrupted:
+		r.Outcome = audit.OutcomeInterrupted
+	case err != nil:
+		r.Outcome = audit.OutcomeFailed
+	}
+	if err != nil {
+		r.Error = err.Error()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), reportTimeout)
+	defer cancel()
+	if err := sink.Write(ctx, r); err != nil {
+		utilruntime.HandleError(fmt.Errorf("%v: failed to write the report: %v", m.Name, err))
+	}
+}
+
+// notify posts the transition of the migration m to the outcome to the
+// webhooks. The migration started at the start time of its status, or now if
+// it never started running.
+func (km *KubeMigrator) notify(m *migrationv1alpha1.StorageVersionMigration, outcome notification.Outcome, now time.Time, migrated int, failureReason string) {
+	km.optionsLock.Lock()
+	notifier := km.notifier
+	km.optionsLock.Unlock()
+	if notifier == nil {
+		return
+	}
+	start := now
+	if m.Status.StartTime != nil {
+		start = m.Status.StartTime.Time
+	}
+	e := notification.Event{
+		Migration:     m.Name,
+		Resource:      m.Spec.Resource,
+		Outcome:       outcome,
+		StartTime:     start,
+		FailureReason: failureReason,
+	}
+	if outcome != notification.OutcomeRunning {
+		completion := km.clock.Now()
+		duration := completion.Sub(start).Seconds()
+		e.ObjectsMigrated = &migrated
+		e.CompletionTime = &completion
+		e.DurationSeconds = &duration
+	}
+	notifier.Notify(e)
 }
 
 // updateStatus always retries no matter what kind of error is returned by the
@@ -140,42 +534,75 @@ func (km *KubeMigrator) processOne(ctx context.Context, obj interface{}) error {
 // because a status update failure.
 // updateStatus also removes other KNOWN conditions.
 func (km *KubeMigrator) updateStatus(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration, condition migrationv1alpha1.MigrationConditionType, message string) (*migrationv1alpha1.StorageVersionMigration, error) {
+	return km.updateStatusWithReason(ctx, m, condition, "", message)
+}
+
+// updateStatusWithReason is updateStatus, setting the reason of the
+// condition. It returns the updated migration. A superseded migration is
+// returned unchanged, its conditions are final.
+func (km *KubeMigrator) updateStatusWithReason(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration, condition migrationv1alpha1.MigrationConditionType, reason, message string) (*migrationv1alpha1.StorageVersionMigration, error) {
 	backoff := wait.Backoff{
 		Steps:    6,
 		Duration: 10 * time.Millisecond,
 		Factor:   5.0,
 		Jitter:   0.1,
 	}
-	return m, wait.ExponentialBackoff(backoff, func() (bool, error) {
+	// m may be in the cache of the informer.
+	m = m.DeepCopy()
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		if HasCondition(m, migrationv1alpha1.MigrationSuperseded) {
+			return true, nil
+		}
 		var newConditions []migrationv1alpha1.MigrationCondition
 		for _, c := range m.Status.Conditions {
 			switch c.Type {
 			case migrationv1alpha1.MigrationRunning:
 			case migrationv1alpha1.MigrationSucceeded:
 			case migrationv1alpha1.MigrationFailed:
+			case migrationv1alpha1.MigrationWaiting:
 			default:
 				// keeps unknown conditions
 				newConditions = append(newConditions, c)
 			}
 		}
+		now := metav1.Now()
 		newCondition := migrationv1alpha1.MigrationCondition{
 			Type:           condition,
 			Status:         corev1.ConditionTrue,
-			LastUpdateTime: metav1.Now(),
+			LastUpdateTime: now,
+			Reason:         reason,
 			Message:        message,
 		}
 		newConditions = append(newConditions, newCondition)
 		m.Status.Conditions = newConditions
+		setTimes(m, condition, now)
 
-		_, err := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().UpdateStatus(ctx, m, metav1.UpdateOptions{})
+		updated, err := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().UpdateStatus(ctx, m, metav1.UpdateOptions{})
 		if err == nil {
+			m = updated
 			return true, nil
 		}
 		// Always refresh and retry, no matter what kind of error is returned by the apiserver.
-		updated, err := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, m.Name, metav1.GetOptions{})
+		fresh, err := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, m.Name, metav1.GetOptions{})
 		if err == nil {
-			m = updated
+			m = fresh
 		}
 		return false, nil
 	})
+	return m, err
+}
+
+// setTimes records the start time of the migration m when it starts
+// running, and its duration when it finishes.
+func setTimes(m *migrationv1alpha1.StorageVersionMigration, condition migrationv1alpha1.MigrationConditionType, now metav1.Time) {
+	switch condition {
+	case migrationv1alpha1.MigrationRunning:
+		if m.Status.StartTime == nil {
+			m.Status.StartTime = &now
+		}
+	case migrationv1alpha1.MigrationSucceeded, migrationv1alpha1.MigrationFailed:
+		if m.Status.StartTime != nil {
+			m.Status.Duration = &metav1.Duration{Duration: now.Sub(m.Status.StartTime.Time).Round(time.Second)}
+		}
+	}
 }
@@ -0,0 +1,258 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clitesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/audit"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/notification"
+)
+
+func TestProcessOneNotifies(t *testing.T) {
+	var lock sync.Mutex
+	events := map[notification.Outcome]notification.Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e notification.Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		events[e.Outcome] = e
+	}))
+	defer server.Close()
+
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	client := fake.NewSimpleClientset(pods)
+	km := NewKubeMigrator(newDynamicClient(newPod("a"), newPod("b")), client, migrator.DefaultOptions())
+	km.SetNotifier(notification.NewNotifier(notification.NewWebhook(server.URL, nil, notification.OutcomeRunning, notification.OutcomeSucceeded)))
+
+	if _, err := km.processOne(context.TODO(), pods, nil); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lock.Lock()
+		n := len(events)
+		lock.Unlock()
+		if n == 2 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	running, ok := events[notification.OutcomeRunning]
+	if !ok || running.Migration != "pods" || running.ObjectsMigrated != nil {
+		t.Errorf("unexpected Running event %+v", running)
+	}
+	succeeded, ok := events[notification.OutcomeSucceeded]
+	if !ok || succeeded.Resource.Resource != "pods" || succeeded.ObjectsMigrated == nil || *succeeded.ObjectsMigrated != 2 || succeeded.CompletionTime == nil {
+		t.Errorf("unexpected Succeeded event %+v", succeeded)
+	}
+}
+
+func TestProcessOneNotifiesResumedMigration(t *testing.T) {
+	var lock sync.Mutex
+	events := map[notification.Outcome]notification.Event{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e notification.Event
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		events[e.Outcome] = e
+	}))
+	defer server.Close()
+
+	start := time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC)
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	pods.Status.StartTime = &metav1.Time{Time: start}
+	pods.Status.Conditions = []migrationv1alpha1.MigrationCondition{{Type: migrationv1alpha1.MigrationRunning, Status: corev1.ConditionTrue}}
+	client := fake.NewSimpleClientset(pods)
+	km := NewKubeMigrator(newDynamicClient(newPod("a")), client, migrator.DefaultOptions())
+	km.SetNotifier(notification.NewNotifier(notification.NewWebhook(server.URL, nil, notification.OutcomeRunning, notification.OutcomeSucceeded)))
+	km.clock = testingclock.NewFakePassiveClock(start.Add(24 * time.Hour))
+
+	if _, err := km.processOne(context.TODO(), pods, nil); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lock.Lock()
+		_, ok := events[notification.OutcomeSucceeded]
+		lock.Unlock()
+		if ok || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Give a Running event the time to arrive.
+	time.Sleep(100 * time.Millisecond)
+	lock.Lock()
+	defer lock.Unlock()
+	if running, ok := events[notification.OutcomeRunning]; ok {
+		t.Errorf("expected no Running event for a resumed migration, got %+v", running)
+	}
+	succeeded, ok := events[notification.OutcomeSucceeded]
+	if !ok || !succeeded.StartTime.Equal(start) || succeeded.DurationSeconds == nil || *succeeded.DurationSeconds != (24*time.Hour).Seconds() {
+		t.Errorf("expected the Succeeded event to start at %v and last a day, got %+v", start, succeeded)
+	}
+}
+
+type recordingSink struct {
+	reports []*audit.Report
+}
+
+func (s *recordingSink) Write(_ context.Context, r *audit.Report) error {
+	s.reports = append(s.reports, r)
+	return nil
+}
+
+func TestProcessOneWritesReport(t *testing.T) {
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	client := fake.NewSimpleClientset(pods)
+	km := NewKubeMigrator(newDynamicClient(newPod("a"), newPod("b")), client, migrator.DefaultOptions())
+	sink := &recordingSink{}
+	km.SetAuditSink(sink)
+
+	if _, err := km.processOne(context.TODO(), pods, nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.reports) != 1 {
+		t.Fatalf("expected 1 report, got %d", len(sink.reports))
+	}
+	r := sink.reports[0]
+	if r.Migration != "pods" || r.Outcome != audit.OutcomeSucceeded || r.Rewritten != 2 || r.Failed != 0 || r.CompletionTime.IsZero() {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
+
+func TestProcessOneRecordsTimes(t *testing.T) {
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	client := fake.NewSimpleClientset(pods)
+	km := NewKubeMigrator(newDynamicClient(newPod("a"), newPod("b")), client, migrator.DefaultOptions())
+
+	if _, err := km.processOne(context.TODO(), pods, nil); err != nil {
+		t.Fatal(err)
+	}
+	m := getMigration(t, client, "pods")
+	if !HasCondition(m, migrationv1alpha1.MigrationSucceeded) {
+		t.Fatalf("expected the migration to succeed, got %+v", m.Status.Conditions)
+	}
+	if m.Status.StartTime == nil || m.Status.Duration == nil {
+		t.Errorf("expected the start time and the duration to be set, got %+v", m.Status)
+	}
+	if m.Status.ObjectsMigrated != 2 {
+		t.Errorf("expected 2 objects migrated, got %d", m.Status.ObjectsMigrated)
+	}
+}
+
+func TestProcessOneSupersededBeforeRunning(t *testing.T) {
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	client := fake.NewSimpleClientset(pods)
+	dynamic := newDynamicClient(newPod("a"))
+	km := NewKubeMigrator(dynamic, client, migrator.DefaultOptions())
+	// The migration is superseded while the migrator sets it Running, which
+	// makes the update conflict.
+	superseded := false
+	client.PrependReactor("update", "storageversionmigrations", func(action clitesting.Action) (bool, runtime.Object, error) {
+		m := action.(clitesting.UpdateAction).GetObject().(*migrationv1alpha1.StorageVersionMigration)
+		if superseded || !HasCondition(m, migrationv1alpha1.MigrationRunning) {
+			return false, nil, nil
+		}
+		superseded = true
+		km.runningLock.Lock()
+		running := km.running
+		km.runningLock.Unlock()
+		if running != "pods" {
+			t.Errorf("expected the migration to be registered as running before its update, got %q", running)
+		}
+		// The reactors run under the lock of the client, the tracker is
+		// used directly.
+		gvr := migrationv1alpha1.SchemeGroupVersion.WithResource("storageversionmigrations")
+		obj, err := client.Tracker().Get(gvr, "", "pods")
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored := obj.(*migrationv1alpha1.StorageVersionMigration)
+		stored.Status.Conditions = append(stored.Status.Conditions, migrationv1alpha1.MigrationCondition{
+			Type:   migrationv1alpha1.MigrationSuperseded,
+			Status: corev1.ConditionTrue,
+		})
+		if err := client.Tracker().Update(gvr, stored, ""); err != nil {
+			t.Fatal(err)
+		}
+		return true, nil, errors.NewConflict(migrationv1alpha1.Resource("storageversionmigrations"), "pods", nil)
+	})
+
+	waiting, err := km.processOne(context.TODO(), pods, nil)
+	if err != nil || waiting {
+		t.Fatalf("expected the migration to be skipped, got %v, %v", waiting, err)
+	}
+	m := getMigration(t, client, "pods")
+	if !HasCondition(m, migrationv1alpha1.MigrationSuperseded) || HasCondition(m, migrationv1alpha1.MigrationRunning) || HasCondition(m, migrationv1alpha1.MigrationSucceeded) {
+		t.Errorf("expected the migration to stay superseded, got %+v", m.Status.Conditions)
+	}
+	for _, a := range dynamic.Actions() {
+		if a.GetVerb() == "list" || a.GetVerb() == "update" {
+			t.Errorf("expected the superseded migration not to run, got %v", a)
+		}
+	}
+	if km.running != "" {
+		t.Errorf("expected no running migration, got %q", km.running)
+	}
+}
+
+func TestProcessOneSkippedByPolicy(t *testing.T) {
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	client := fake.NewSimpleClientset(pods)
+	dynamic := newDynamicClient(newPod("a"))
+	km := NewKubeMigrator(dynamic, client, migrator.DefaultOptions())
+	skip := newPolicy("skip-pods", 0, migrationv1alpha1.ResourcePattern{Resource: "pods"})
+	skip.Spec.Action = migrationv1alpha1.MigrationPolicyActionSkip
+
+	waiting, err := km.processOne(context.TODO(), pods, NewMigrationPolicies([]migrationv1alpha1.MigrationPolicy{skip}))
+	if err != nil || waiting {
+		t.Fatalf("expected the migration to fail, got %v, %v", waiting, err)
+	}
+	m := getMigration(t, client, "pods")
+	c := GetCondition(m, migrationv1alpha1.MigrationFailed)
+	if c == nil || c.Reason != migrationv1alpha1.MigrationFailedReasonSkippedByPolicy || c.Message != "migration policy skip-pods skips pods" {
+		t.Errorf("expected the migration to be skipped by the policy, got %+v", m.Status.Conditions)
+	}
+	if len(dynamic.Actions()) != 0 {
+		t.Errorf("expected the skipped migration not to run, got %v", dynamic.Actions())
+	}
+}
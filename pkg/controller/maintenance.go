@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/schedule"
+)
+
+// errMaintenanceWindowClosed cancels a running migration when the
+// maintenance windows of its resource close.
+var errMaintenanceWindowClosed = fmt.Errorf("the maintenance windows closed")
+
+// NewMaintenanceWindows returns the windows of the API.
+func NewMaintenanceWindows(windows []migrationv1alpha1.MaintenanceWindow) (schedule.Windows, error) {
+	var ws schedule.Windows
+	for i, w := range windows {
+		window, err := schedule.NewWindow(w.Schedule, w.Duration.Duration, w.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance window %d: %v", i, err)
+		}
+		ws = append(ws, window)
+	}
+	return ws, nil
+}
+
+// SetMaintenanceWindows replaces the maintenance windows of the resources
+// that no migration policy sets windows for. The running migration is
+// checked against the new windows when it is resumed.
+func (km *KubeMigrator) SetMaintenanceWindows(windows schedule.Windows) {
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	km.windows = windows
+}
+
+// maintenanceWindows returns the windows of the policy if it sets some, or
+// the windows of the KubeMigrator.
+func (km *KubeMigrator) maintenanceWindows(policy ResolvedPolicy) (schedule.Windows, error) {
+	if len(policy.MaintenanceWindows) != 0 {
+		return NewMaintenanceWindows(policy.MaintenanceWindows)
+	}
+	km.optionsLock.Lock()
+	defer km.optionsLock.Unlock()
+	return km.windows, nil
+}
+
+// waitingMessage explains that the migration waits for the windows to open
+// after now.
+func waitingMessage(windows schedule.Windows, now time.Time, suspended bool) string {
+	verb := "run"
+	if suspended {
+		verb = "resume"
+	}
+	next := windows.NextOpen(now)
+	if next.IsZero() {
+		return "the maintenance windows are closed and do not open in the next five years"
+	}
+	return fmt.Sprintf("the maintenance windows are closed, the migration will %s at %s", verb, next.UTC().Format(time.RFC3339))
+}
+
+// isWaiting returns true if m has the Waiting condition with the reason and
+// the message.
+func isWaiting(m *migrationv1alpha1.StorageVersionMigration, reason, message string) bool {
+	c := GetCondition(m, migrationv1alpha1.MigrationWaiting)
+	return c != nil && c.Reason == reason && c.Message == message
+}
+
+// setWaiting sets the Waiting condition of m, keeping its other conditions,
+// so that a suspended migration is still Running.
+func (km *KubeMigrator) setWaiting(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration, reason, message string) error {
+	if isWaiting(m, reason, message) {
+		return nil
+	}
+	m = m.DeepCopy()
+	var conditions []migrationv1alpha1.MigrationCondition
+	for _, c := range m.Status.Conditions {
+		if c.Type != migrationv1alpha1.MigrationWaiting {
+			conditions = append(conditions, c)
+		}
+	}
+	m.Status.Conditions = append(conditions, migrationv1alpha1.MigrationCondition{
+		Type:           migrationv1alpha1.MigrationWaiting,
+		Status:         corev1.ConditionTrue,
+		LastUpdateTime: metav1.Now(),
+		Reason:         reason,
+		Message:        message,
+	})
+	_, err := km.migrationClient.MigrationV1alpha1().StorageVersionMigrations().UpdateStatus(ctx, m, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clitesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+)
+
+// nightly is open from 01:00 to 03:00 UTC.
+var nightly = []migrationv1alpha1.MaintenanceWindow{{Schedule: "0 1 * * *", Duration: metav1.Duration{Duration: 2 * time.Hour}}}
+
+func newDynamicClient(objects ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		{Version: "v1", Resource: "pods"}:  "PodList",
+		{Version: "v1", Resource: "nodes"}: "NodeList",
+	}, objects...)
+}
+
+func newPod(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": "default",
+		},
+	}}
+}
+
+func getMigration(t *testing.T, client *fake.Clientset, name string) *migrationv1alpha1.StorageVersionMigration {
+	t.Helper()
+	m, err := client.MigrationV1alpha1().StorageVersionMigrations().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestResolveMaintenanceWindows(t *testing.T) {
+	all := newPolicy("all", 0, migrationv1alpha1.ResourcePattern{Group: "*", Resource: "*"})
+	all.Spec.MaintenanceWindows = nightly
+	same := newPolicy("same", 0, migrationv1alpha1.ResourcePattern{Resource: "pods"})
+	same.Spec.MaintenanceWindows = []migrationv1alpha1.MaintenanceWindow{nightly[0]}
+	other := newPolicy("other", 0, migrationv1alpha1.ResourcePattern{Resource: "pods"})
+	other.Spec.MaintenanceWindows = []migrationv1alpha1.MaintenanceWindow{{Schedule: "0 2 * * *", Duration: metav1.Duration{Duration: time.Hour}}}
+
+	r := NewMigrationPolicies([]migrationv1alpha1.MigrationPolicy{all, same, other}).Resolve("", "pods")
+	if len(r.MaintenanceWindows) != 1 || r.MaintenanceWindows[0].Schedule != "0 1 * * *" {
+		t.Errorf("expected the windows of the policy all, got %v", r.MaintenanceWindows)
+	}
+	expectedConflicts := []PolicyConflict{{Policy: "other", Field: "maintenanceWindows", By: "all"}}
+	if len(r.Conflicts) != 1 || r.Conflicts[0] != expectedConflicts[0] {
+		t.Errorf("expected conflicts %v, got %v", expectedConflicts, r.Conflicts)
+	}
+}
+
+func TestProcessSkipsClosedMaintenanceWindows(t *testing.T) {
+	// The pods migration was suspended, and has priority over the pending
+	// nodes migration.
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	pods.Status.Conditions = []migrationv1alpha1.MigrationCondition{{Type: migrationv1alpha1.MigrationRunning, Status: corev1.ConditionTrue}}
+	nodes := newMigrationForResource("nodes", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "nodes"})
+	policy := newPolicy("pods", 0, migrationv1alpha1.ResourcePattern{Resource: "pods"})
+	policy.Spec.MaintenanceWindows = nightly
+	client := fake.NewSimpleClientset(pods, nodes, &policy)
+	km := NewKubeMigrator(newDynamicClient(), client, migrator.DefaultOptions())
+	km.clock = testingclock.NewFakePassiveClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go km.migrationInformer.Run(ctx.Done())
+	cache.WaitForCacheSync(ctx.Done(), km.migrationInformer.HasSynced)
+	km.runPolicyInformer(ctx)
+	km.process(ctx)
+
+	m := getMigration(t, client, "pods")
+	c := GetCondition(m, migrationv1alpha1.MigrationWaiting)
+	if c == nil || c.Reason != migrationv1alpha1.MigrationWaitingReasonOutsideMaintenanceWindow {
+		t.Fatalf("expected the pods migration to wait for its window, got %v", m.Status.Conditions)
+	}
+	if e := "will resume at 2026-03-11T01:00:00Z"; !strings.Contains(c.Message, e) {
+		t.Errorf("expected the message to contain %q, got %q", e, c.Message)
+	}
+	if !HasCondition(m, migrationv1alpha1.MigrationRunning) {
+		t.Errorf("expected the pods migration to stay running, got %v", m.Status.Conditions)
+	}
+	if m := getMigration(t, client, "nodes"); !HasCondition(m, migrationv1alpha1.MigrationSucceeded) {
+		t.Errorf("expected the nodes migration to succeed, got %v", m.Status.Conditions)
+	}
+}
+
+func TestGlobalMaintenanceWindows(t *testing.T) {
+	nodes := newMigrationForResource("nodes", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "nodes"})
+	client := fake.NewSimpleClientset(nodes)
+	km := NewKubeMigrator(newDynamicClient(), client, migrator.DefaultOptions())
+	windows, err := NewMaintenanceWindows(nightly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	km.SetMaintenanceWindows(windows)
+	clock := testingclock.NewFakePassiveClock(time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC))
+	km.clock = clock
+
+	waiting, err := km.processOne(context.TODO(), nodes, nil)
+	if err != nil || !waiting {
+		t.Fatalf("expected the migration to wait, got %v, %v", waiting, err)
+	}
+	m := getMigration(t, client, "nodes")
+	if c := GetCondition(m, migrationv1alpha1.MigrationWaiting); c == nil || !strings.Contains(c.Message, "will run at 2026-03-11T01:00:00Z") {
+		t.Fatalf("expected the migration to wait for its window, got %v", m.Status.Conditions)
+	}
+
+	clock.SetTime(time.Date(2026, 3, 11, 1, 30, 0, 0, time.UTC))
+	waiting, err = km.processOne(context.TODO(), m, nil)
+	if err != nil || waiting {
+		t.Fatalf("expected the migration to run, got %v, %v", waiting, err)
+	}
+	m = getMigration(t, client, "nodes")
+	if !HasCondition(m, migrationv1alpha1.MigrationSucceeded) || HasCondition(m, migrationv1alpha1.MigrationWaiting) {
+		t.Errorf("expected the migration to succeed and no longer wait, got %v", m.Status.Conditions)
+	}
+}
+
+func TestInvalidMaintenanceWindows(t *testing.T) {
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	policy := newPolicy("pods", 0, migrationv1alpha1.ResourcePattern{Resource: "pods"})
+	policy.Spec.MaintenanceWindows = []migrationv1alpha1.MaintenanceWindow{{Schedule: "0 1 * *", Duration: metav1.Duration{Duration: time.Hour}}}
+	client := fake.NewSimpleClientset(pods)
+	km := NewKubeMigrator(newDynamicClient(), client, migrator.DefaultOptions())
+
+	waiting, err := km.processOne(context.TODO(), pods, NewMigrationPolicies([]migrationv1alpha1.MigrationPolicy{policy}))
+	if err != nil || !waiting {
+		t.Fatalf("expected the migration to wait, got %v, %v", waiting, err)
+	}
+	m := getMigration(t, client, "pods")
+	if c := GetCondition(m, migrationv1alpha1.MigrationWaiting); c == nil || c.Reason != migrationv1alpha1.MigrationWaitingReasonInvalidMaintenanceWindow {
+		t.Errorf("expected the migration to wait because of the invalid window, got %v", m.Status.Conditions)
+	}
+}
+
+func TestMaintenanceWindowClosesDuringMigration(t *testing.T) {
+	pods := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	client := fake.NewSimpleClientset(pods)
+	dynamic := newDynamicClient(newPod("pod1"))
+	// The window closes while the pods are listed.
+	dynamic.PrependReactor("list", "pods", func(clitesting.Action) (bool, runtime.Object, error) {
+		time.Sleep(100 * time.Millisecond)
+		return false, nil, nil
+	})
+	km := NewKubeMigrator(dynamic, client, migrator.DefaultOptions())
+	windows, err := NewMaintenanceWindows(nightly)
+	if err != nil {
+		t.Fatal(err)
+	}
+	km.SetMaintenanceWindows(windows)
+	km.clock = testingclock.NewFakePassiveClock(time.Date(2026, 3, 10, 2, 59, 59, 990000000, time.UTC))
+
+	waiting, err := km.processOne(context.TODO(), pods, nil)
+	if err != nil || !waiting {
+		t.Fatalf("expected the migration to be suspended, got %v, %v", waiting, err)
+	}
+	m := getMigration(t, client, "pods")
+	if IsFinished(m) || !HasCondition(m, migrationv1alpha1.MigrationRunning) {
+		t.Errorf("expected the migration to stay running, got %v", m.Status.Conditions)
+	}
+	if c := GetCondition(m, migrationv1alpha1.MigrationWaiting); c == nil || !strings.Contains(c.Message, "will resume at 2026-03-11T01:00:00Z") {
+		t.Errorf("expected the migration to wait for the next window, got %v", m.Status.Conditions)
+	}
+}
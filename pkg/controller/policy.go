@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+// PolicyConflict records that a setting of a migration policy does not apply
+// to a resource, because a policy of higher precedence sets it to another
+// value.
+type PolicyConflict struct {
+	// Policy is the name of the overridden policy.
+	Policy string
+	// Field is the overridden setting, e.g. "concurrency".
+	Field string
+	// By is the name of the policy whose setting applies.
+	By string
+}
+
+// ResolvedPolicy is the outcome of the migration policies matching a
+// resource. The settings that no policy sets are empty.
+type ResolvedPolicy struct {
+	Action              migrationv1alpha1.MigrationPolicyAction
+	Strategy            migrationv1alpha1.MigrationStrategy
+	ChunkSize           *int64
+	Concurrency         *int32
+	RetryMaxAttempts    *int32
+	RetryInitialBackoff *time.Duration
+	RetryMaxBackoff     *time.Duration
+	MaintenanceWindows  []migrationv1alpha1.MaintenanceWindow
+	MigrationPriority   *int32
+	// Sources maps the settings to the names of the policies they are
+	// taken from.
+	Sources map[string]string
+	// Conflicts lists the settings of the matching policies that do not
+	// apply.
+	Conflicts []PolicyConflict
+}
+
+// policyField is a setting of migration policies.
+type policyField struct {
+	name string
+	// value returns the setting of the spec, or nil if it is unset.
+	value func(spec *migrationv1alpha1.MigrationPolicySpec) interface{}
+}
+
+var policyFields = []policyField{
+	{"action", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.Action == "" {
+			return nil
+		}
+		return spec.Action
+	}},
+	{"strategy", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.Strategy == "" {
+			return nil
+		}
+		return spec.Strategy
+	}},
+	{"chunkSize", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.ChunkSize == nil {
+			return nil
+		}
+		return *spec.ChunkSize
+	}},
+	{"concurrency", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.Concurrency == nil {
+			return nil
+		}
+		return *spec.Concurrency
+	}},
+	{"retry.maxAttempts", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.Retry == nil || spec.Retry.MaxAttempts == nil {
+			return nil
+		}
+		return *spec.Retry.MaxAttempts
+	}},
+	{"retry.initialBackoff", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.Retry == nil || spec.Retry.InitialBackoff == nil {
+			return nil
+		}
+		return spec.Retry.InitialBackoff.Duration
+	}},
+	{"retry.maxBackoff", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.Retry == nil || spec.Retry.MaxBackoff == nil {
+			return nil
+		}
+		return spec.Retry.MaxBackoff.Duration
+	}},
+	{"maintenanceWindows", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if len(spec.MaintenanceWindows) == 0 {
+			return nil
+		}
+		return spec.MaintenanceWindows
+	}},
+	{"migrationPriority", func(spec *migrationv1alpha1.MigrationPolicySpec) interface{} {
+		if spec.MigrationPriority == nil {
+			return nil
+		}
+		return *spec.MigrationPriority
+	}},
+}
+
+// MigrationPolicies resolves the migration policies of resources. The zero
+// value and nil have no policies.
+type MigrationPolicies struct {
+	// policies are sorted by precedence.
+	policies []migrationv1alpha1.MigrationPolicy
+}
+
+// NewMigrationPolicies returns the MigrationPolicies of the policies. The
+// policies with higher priorities take precedence, ties being broken by the
+// alphabetical order of the names.
+func NewMigrationPolicies(policies []migrationv1alpha1.MigrationPolicy) *MigrationPolicies {
+	sorted := make([]migrationv1alpha1.MigrationPolicy, len(policies))
+	copy(sorted, policies)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Spec.Priority != sorted[j].Spec.Priority {
+			return sorted[i].Spec.Priority > sorted[j].Spec.Priority
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	return &MigrationPolicies{policies: sorted}
+}
+
+// Policies returns the policies, sorted by precedence.
+func (p *MigrationPolicies) Policies() []migrationv1alpha1.MigrationPolicy {
+	if p == nil {
+		return nil
+	}
+	return p.policies
+}
+
+// Resolve returns the settings of the policies matching the resource of
+// the group. Every setting is taken from the policy of highest precedence
+// that sets it.
+func (p *MigrationPolicies) Resolve(group, resource string) ResolvedPolicy {
+	values := map[string]interface{}{}
+	r := ResolvedPolicy{Sources: map[string]string{}}
+	for i := range p.Policies() {
+		policy := &p.policies[i]
+		if !PolicyMatches(policy, group, resource) {
+			continue
+		}
+		for _, f := range policyFields {
+			v := f.value(&policy.Spec)
+			if v == nil {
+				continue
+			}
+			winner, ok := r.Sources[f.name]
+			if !ok {
+				values[f.name] = v
+				r.Sources[f.name] = policy.Name
+				continue
+			}
+			if !reflect.DeepEqual(values[f.name], v) {
+				r.Conflicts = append(r.Conflicts, PolicyConflict{Policy: policy.Name, Field: f.name, By: winner})
+			}
+		}
+	}
+	r.Action, _ = values["action"].(migrationv1alpha1.MigrationPolicyAction)
+	r.Strategy, _ = values["strategy"].(migrationv1alpha1.MigrationStrategy)
+	if v, ok := values["chunkSize"].(int64); ok {
+		r.ChunkSize = &v
+	}
+	if v, ok := values["concurrency"].(int32); ok {
+		r.Concurrency = &v
+	}
+	if v, ok := values["retry.maxAttempts"].(int32); ok {
+		r.RetryMaxAttempts = &v
+	}
+	if v, ok := values["retry.initialBackoff"].(time.Duration); ok {
+		r.RetryInitialBackoff = &v
+	}
+	if v, ok := values["retry.maxBackoff"].(time.Duration); ok {
+		r.RetryMaxBackoff = &v
+	}
+	r.MaintenanceWindows, _ = values["maintenanceWindows"].([]migrationv1alpha1.MaintenanceWindow)
+	if v, ok := values["migrationPriority"].(int32); ok {
+		r.MigrationPriority = &v
+	}
+	return r
+}
+
+// PolicyMatches returns true if a resource pattern of the policy matches the
+// resource of the group.
+func PolicyMatches(policy *migrationv1alpha1.MigrationPolicy, group, resource string) bool {
+	for _, pattern := range policy.Spec.Resources {
+		if patternMatches(pattern, group, resource) {
+			return true
+		}
+	}
+	return false
+}
+
+func patternMatches(pattern migrationv1alpha1.ResourcePattern, group, resource string) bool {
+	if pattern.Resource != "*" && pattern.Resource != resource {
+		return false
+	}
+	switch {
+	case pattern.Group == "*":
+		return true
+	case strings.HasPrefix(pattern.Group, "*."):
+		return strings.HasSuffix(group, pattern.Group[1:])
+	default:
+		return pattern.Group == group
+	}
+}
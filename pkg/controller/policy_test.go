@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clitesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+)
+
+func newPolicy(name string, priority int32, patterns ...migrationv1alpha1.ResourcePattern) migrationv1alpha1.MigrationPolicy {
+	return migrationv1alpha1.MigrationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: migrationv1alpha1.MigrationPolicySpec{
+			Resources: patterns,
+			Priority:  priority,
+		},
+	}
+}
+
+func TestPolicyMatches(t *testing.T) {
+	for _, tc := range []struct {
+		pattern  migrationv1alpha1.ResourcePattern
+		group    string
+		resource string
+		matches  bool
+	}{
+		{migrationv1alpha1.ResourcePattern{Resource: "pods"}, "", "pods", true},
+		{migrationv1alpha1.ResourcePattern{Resource: "pods"}, "apps", "pods", false},
+		{migrationv1alpha1.ResourcePattern{Group: "apps", Resource: "*"}, "apps", "deployments", true},
+		{migrationv1alpha1.ResourcePattern{Group: "*", Resource: "secrets"}, "", "secrets", true},
+		{migrationv1alpha1.ResourcePattern{Group: "*.example.com", Resource: "*"}, "a.example.com", "widgets", true},
+		{migrationv1alpha1.ResourcePattern{Group: "*.example.com", Resource: "*"}, "example.com", "widgets", false},
+		{migrationv1alpha1.ResourcePattern{Group: "*.example.com", Resource: "*"}, "badexample.com", "widgets", false},
+	} {
+		policy := newPolicy("p", 0, tc.pattern)
+		if a := PolicyMatches(&policy, tc.group, tc.resource); a != tc.matches {
+			t.Errorf("%+v matching %s: expected %v, got %v", tc.pattern, ResourceName(tc.group, tc.resource), tc.matches, a)
+		}
+	}
+}
+
+func TestResolvePolicy(t *testing.T) {
+	all := newPolicy("all", 0, migrationv1alpha1.ResourcePattern{Group: "*", Resource: "*"})
+	all.Spec.Strategy = migrationv1alpha1.MigrationStrategyDryRunFirst
+	all.Spec.Concurrency = int32Ptr(2)
+	apps := newPolicy("apps", 10, migrationv1alpha1.ResourcePattern{Group: "apps", Resource: "*"})
+	apps.Spec.Concurrency = int32Ptr(8)
+	// Same priority as apps, loses because of its name.
+	deployments := newPolicy("deployments", 10, migrationv1alpha1.ResourcePattern{Group: "apps", Resource: "deployments"})
+	deployments.Spec.Concurrency = int32Ptr(4)
+	deployments.Spec.Action = migrationv1alpha1.MigrationPolicyActionSkip
+	// Agrees with apps, so it does not conflict.
+	agreeing := newPolicy("agreeing", 1, migrationv1alpha1.ResourcePattern{Group: "apps", Resource: "*"})
+	agreeing.Spec.Concurrency = int32Ptr(8)
+
+	// The order of the input does not matter.
+	for _, policies := range [][]migrationv1alpha1.MigrationPolicy{
+		{all, apps, deployments, agreeing},
+		{agreeing, deployments, apps, all},
+	} {
+		p := NewMigrationPolicies(policies)
+		r := p.Resolve("apps", "deployments")
+		if r.Action != migrationv1alpha1.MigrationPolicyActionSkip {
+			t.Errorf("expected action Skip, got %q", r.Action)
+		}
+		if r.Strategy != migrationv1alpha1.MigrationStrategyDryRunFirst {
+			t.Errorf("expected strategy DryRunFirst, got %q", r.Strategy)
+		}
+		if r.Concurrency == nil || *r.Concurrency != 8 {
+			t.Errorf("expected concurrency 8, got %v", r.Concurrency)
+		}
+		if r.ChunkSize != nil {
+			t.Errorf("expected no chunk size, got %v", *r.ChunkSize)
+		}
+		expectedSources := map[string]string{"action": "deployments", "strategy": "all", "concurrency": "apps"}
+		if !reflect.DeepEqual(expectedSources, r.Sources) {
+			t.Errorf("expected sources %v, got %v", expectedSources, r.Sources)
+		}
+		expectedConflicts := []PolicyConflict{
+			{Policy: "deployments", Field: "concurrency", By: "apps"},
+			{Policy: "all", Field: "concurrency", By: "apps"},
+		}
+		if !reflect.DeepEqual(expectedConflicts, r.Conflicts) {
+			t.Errorf("expected conflicts %v, got %v", expectedConflicts, r.Conflicts)
+		}
+
+		r = p.Resolve("", "pods")
+		if r.Action != "" || r.Concurrency == nil || *r.Concurrency != 2 || len(r.Conflicts) != 0 {
+			t.Errorf("expected only the settings of the policy all for pods, got %+v", r)
+		}
+	}
+}
+
+func TestResolveNoPolicies(t *testing.T) {
+	var p *MigrationPolicies
+	r := p.Resolve("", "pods")
+	if r.Action != "" || r.Strategy != "" || r.ChunkSize != nil || r.RetryMaxAttempts != nil {
+		t.Errorf("expected no settings, got %+v", r)
+	}
+}
+
+func TestKubeMigratorPolicyOptions(t *testing.T) {
+	policy := newPolicy("pods", 0, migrationv1alpha1.ResourcePattern{Resource: "pods"})
+	chunkSize := int64(10)
+	policy.Spec.ChunkSize = &chunkSize
+	policy.Spec.Concurrency = int32Ptr(8)
+	policy.Spec.Strategy = migrationv1alpha1.MigrationStrategyDryRunFirst
+	client := fake.NewSimpleClientset(&policy)
+	km := NewKubeMigrator(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), client, migrator.DefaultOptions())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	km.runPolicyInformer(ctx)
+
+	policies := km.policies()
+	options, strategy := km.policyOptions(policies.Resolve("", "pods"))
+	if options.ChunkLimit != 10 || options.Concurrency != 8 || strategy != migrationv1alpha1.MigrationStrategyDryRunFirst {
+		t.Errorf("expected chunk limit 10, concurrency 8 and strategy DryRunFirst, got %+v and %s", options, strategy)
+	}
+	options, strategy = km.policyOptions(policies.Resolve("", "nodes"))
+	if options != migrator.DefaultOptions() || strategy != migrationv1alpha1.MigrationStrategyDirect {
+		t.Errorf("expected the default options and the Direct strategy, got %+v and %s", options, strategy)
+	}
+}
+
+func TestProcessWithoutPolicies(t *testing.T) {
+	m := newMigrationForResource("pods", migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	client := fake.NewSimpleClientset(m)
+	client.PrependReactor("list", "migrationpolicies", func(clitesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewForbidden(migrationv1alpha1.Resource("migrationpolicies"), "", fmt.Errorf("no RBAC policy matched"))
+	})
+	km := NewKubeMigrator(newDynamicClient(newPod("a")), client, migrator.DefaultOptions())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go km.migrationInformer.Run(ctx.Done())
+	cache.WaitForCacheSync(ctx.Done(), km.migrationInformer.HasSynced)
+	go km.policyInformer.Run(ctx.Done())
+	// The policies cannot be listed, the migration runs without them.
+	km.process(ctx)
+
+	if m := getMigration(t, client, "pods"); !HasCondition(m, migrationv1alpha1.MigrationSucceeded) {
+		t.Errorf("expected the migration to succeed, got %v", m.Status.Conditions)
+	}
+}
+
+func int32Ptr(i int32) *int32 {
+	return &i
+}
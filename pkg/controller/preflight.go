@@ -0,0 +1,186 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	authorizationclient "k8s.io/client-go/kubernetes/typed/authorization/v1"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+// preflightError is a reason a migration cannot be run, which running it
+// again will not fix.
+type preflightError struct {
+	reason  string
+	message string
+}
+
+func (e *preflightError) Error() string {
+	return e.message
+}
+
+// MigrationVerbs are the verbs the migrator needs on a resource to rewrite
+// its objects. The objects are rewritten with update requests, patch is never
+// used.
+var MigrationVerbs = []string{"get", "list", "update"}
+
+// SetPreflight sets the clients the migrator checks, before it runs a
+// migration, that the resource of the migration is served and that the
+// migrator is authorized to rewrite its objects. If they are nil, only the
+// duplicate migrations are checked. It must be called before Run.
+func (km *KubeMigrator) SetPreflight(discovery discovery.ServerResourcesInterface, reviews authorizationclient.SelfSubjectAccessReviewInterface) {
+	km.discovery = discovery
+	km.accessReviews = reviews
+}
+
+// preflight checks that the migration m can be run. It returns a
+// *preflightError if m must fail, or another error if the checks could not
+// be done.
+func (km *KubeMigrator) preflight(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration) error {
+	if err := km.checkDuplicate(m); err != nil {
+		return err
+	}
+	if km.discovery != nil {
+		if err := km.checkResource(m); err != nil {
+			return err
+		}
+	}
+	if km.accessReviews != nil {
+		if err := km.checkAccess(ctx, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDuplicate fails the migrations created by users while an older
+// migration of the same resource is pending or running. The migrations
+// created by the trigger, the initializer and the plans are left alone: the
+// trigger and the plans supersede the older migrations, and the initializer
+// skips the resources that have one.
+func (km *KubeMigrator) checkDuplicate(m *migrationv1alpha1.StorageVersionMigration) error {
+	if _, ok := m.Annotations[migrationv1alpha1.MigrationCreatedByAnnotation]; ok {
+		return nil
+	}
+	for _, status := range []string{StatusRunning, StatusPending} {
+		objs, err := km.migrationInformer.GetIndexer().ByIndex(StatusIndex, status)
+		if err != nil {
+			return err
+		}
+		others, err := toMigrations(objs)
+		if err != nil {
+			return err
+		}
+		for _, other := range others {
+			if other.Name == m.Name || ToIndex(other.Spec.Resource) != ToIndex(m.Spec.Resource) {
+				continue
+			}
+			if !other.CreationTimestamp.Before(&m.CreationTimestamp) {
+				continue
+			}
+			return &preflightError{
+				reason:  migrationv1alpha1.MigrationFailedReasonDuplicateMigration,
+				message: fmt.Sprintf("migration %s of %s is already %s", other.Name, resource(m).GroupResource(), status),
+			}
+		}
+	}
+	return nil
+}
+
+// checkPolicy checks that the migration policies do not skip the resource of
+// m. Migrations created by users are not launched by the trigger, so the
+// migrator enforces the Skip action itself.
+func checkPolicy(m *migrationv1alpha1.StorageVersionMigration, policy ResolvedPolicy) error {
+	if policy.Action != migrationv1alpha1.MigrationPolicyActionSkip {
+		return nil
+	}
+	return &preflightError{
+		reason:  migrationv1alpha1.MigrationFailedReasonSkippedByPolicy,
+		message: fmt.Sprintf("migration policy %s skips %s", policy.Sources["action"], resource(m).GroupResource()),
+	}
+}
+
+// checkResource checks in the discovery document that the resource of m is
+// served, and that it supports the verbs of the migrator.
+func (km *KubeMigrator) checkResource(m *migrationv1alpha1.StorageVersionMigration) error {
+	gvr := resource(m)
+	notFound := &preflightError{
+		reason:  migrationv1alpha1.MigrationFailedReasonResourceNotFound,
+		message: fmt.Sprintf("the server does not serve %s", gvr),
+	}
+	resources, err := km.discovery.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if errors.IsNotFound(err) {
+		return notFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to discover %s: %v", gvr.GroupVersion(), err)
+	}
+	for _, r := range resources.APIResources {
+		if r.Name != gvr.Resource {
+			continue
+		}
+		verbs := sets.NewString(r.Verbs...)
+		if !verbs.HasAll(MigrationVerbs...) {
+			return &preflightError{
+				reason:  migrationv1alpha1.MigrationFailedReasonResourceNotMigratable,
+				message: fmt.Sprintf("%s supports the verbs %v, the migrator needs %v", gvr, r.Verbs, MigrationVerbs),
+			}
+		}
+		return nil
+	}
+	return notFound
+}
+
+// checkAccess checks that the migrator is authorized to get, list and update
+// the objects of the resource of m in all the namespaces.
+func (km *KubeMigrator) checkAccess(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration) error {
+	gvr := resource(m)
+	for _, verb := range MigrationVerbs {
+		review := &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Verb:     verb,
+					Group:    gvr.Group,
+					Version:  gvr.Version,
+					Resource: gvr.Resource,
+				},
+			},
+		}
+		review, err := km.accessReviews.Create(ctx, review, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to review the access to %s: %v", gvr.GroupResource(), err)
+		}
+		if !review.Status.Allowed {
+			message := fmt.Sprintf("the migrator is not allowed to %s %s", verb, gvr.GroupResource())
+			if review.Status.Reason != "" {
+				message += ": " + review.Status.Reason
+			}
+			return &preflightError{
+				reason:  migrationv1alpha1.MigrationFailedReasonForbidden,
+				message: message,
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clitesting "k8s.io/client-go/testing"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+)
+
+// newKubeClient returns a client whose discovery document serves pods with
+// the verbs, and whose access reviews deny the denied verbs.
+func newKubeClient(verbs []string, denied ...string) *kubefake.Clientset {
+	client := kubefake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods", Namespaced: true, Kind: "Pod", Verbs: verbs}},
+		},
+	}
+	client.PrependReactor("create", "selfsubjectaccessreviews", func(a clitesting.Action) (bool, runtime.Object, error) {
+		review := a.(clitesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+		review.Status.Allowed = true
+		for _, verb := range denied {
+			if review.Spec.ResourceAttributes.Verb == verb {
+				review.Status.Allowed = false
+				review.Status.Reason = "no RBAC policy matched"
+			}
+		}
+		return true, review, nil
+	})
+	return client
+}
+
+func TestProcessOnePreflight(t *testing.T) {
+	allVerbs := []string{"get", "list", "watch", "update", "patch"}
+	tests := []struct {
+		name     string
+		resource migrationv1alpha1.GroupVersionResource
+		verbs    []string
+		denied   []string
+		reason   string
+	}{
+		{
+			name:     "allowed",
+			resource: migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			verbs:    allVerbs,
+			reason:   "",
+		},
+		{
+			name:     "unknown resource",
+			resource: migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "widgets"},
+			verbs:    allVerbs,
+			reason:   migrationv1alpha1.MigrationFailedReasonResourceNotFound,
+		},
+		{
+			name:     "unknown group version",
+			resource: migrationv1alpha1.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"},
+			verbs:    allVerbs,
+			reason:   migrationv1alpha1.MigrationFailedReasonResourceNotFound,
+		},
+		{
+			name:     "no update verb",
+			resource: migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			verbs:    []string{"get", "list"},
+			reason:   migrationv1alpha1.MigrationFailedReasonResourceNotMigratable,
+		},
+		{
+			name:     "update forbidden",
+			resource: migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			verbs:    allVerbs,
+			denied:   []string{"update"},
+			reason:   migrationv1alpha1.MigrationFailedReasonForbidden,
+		},
+		{
+			name:     "get forbidden",
+			resource: migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+			verbs:    allVerbs,
+			denied:   []string{"get"},
+			reason:   migrationv1alpha1.MigrationFailedReasonForbidden,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMigrationForResource("m", test.resource)
+			client := fake.NewSimpleClientset(m)
+			dynamic := newDynamicClient(newPod("a"))
+			km := NewKubeMigrator(dynamic, client, migrator.DefaultOptions())
+			kube := newKubeClient(test.verbs, test.denied...)
+			km.SetPreflight(kube.Discovery(), kube.AuthorizationV1().SelfSubjectAccessReviews())
+
+			if _, err := km.processOne(context.TODO(), m, nil); err != nil {
+				t.Fatal(err)
+			}
+			m = getMigration(t, client, "m")
+			if test.reason == "" {
+				if !HasCondition(m, migrationv1alpha1.MigrationSucceeded) {
+					t.Errorf("expected the migration to succeed, got %+v", m.Status.Conditions)
+				}
+				return
+			}
+			failed := GetCondition(m, migrationv1alpha1.MigrationFailed)
+			if failed == nil || failed.Reason != test.reason || failed.Message == "" {
+				t.Fatalf("expected the migration to fail with reason %s, got %+v", test.reason, m.Status.Conditions)
+			}
+			if len(dynamic.Actions()) != 0 {
+				t.Errorf("expected no request to the resource, got %v", dynamic.Actions())
+			}
+		})
+	}
+}
+
+func TestProcessOneFailsDuplicate(t *testing.T) {
+	pods := migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"}
+	now := time.Now()
+	older := newMigrationForResource("older", pods)
+	older.CreationTimestamp = metav1.NewTime(now.Add(-time.Minute))
+	user := newMigrationForResource("user", pods)
+	user.CreationTimestamp = metav1.NewTime(now)
+	trigger := newMigrationForResource("trigger", pods)
+	trigger.CreationTimestamp = metav1.NewTime(now)
+	trigger.Annotations = map[string]string{migrationv1alpha1.MigrationCreatedByAnnotation: "storage-version-migration-trigger"}
+
+	client := fake.NewSimpleClientset(older, user, trigger)
+	km := NewKubeMigrator(newDynamicClient(newPod("a")), client, migrator.DefaultOptions())
+	for _, m := range []*migrationv1alpha1.StorageVersionMigration{older, user, trigger} {
+		if err := km.migrationInformer.GetIndexer().Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := km.processOne(context.TODO(), user, nil); err != nil {
+		t.Fatal(err)
+	}
+	failed := GetCondition(getMigration(t, client, "user"), migrationv1alpha1.MigrationFailed)
+	if failed == nil || failed.Reason != migrationv1alpha1.MigrationFailedReasonDuplicateMigration {
+		t.Errorf("expected the migration of the user to fail as a duplicate, got %+v", failed)
+	}
+	if _, err := km.processOne(context.TODO(), trigger, nil); err != nil {
+		t.Fatal(err)
+	}
+	if m := getMigration(t, client, "trigger"); !HasCondition(m, migrationv1alpha1.MigrationSucceeded) {
+		t.Errorf("expected the migration of the trigger to succeed, got %+v", m.Status.Conditions)
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// coreGroup is how the core group is written in the ResourceRules.
+const coreGroup = "core"
+
+// ResourceRules select resources by group and by name.
+type ResourceRules struct {
+	// IncludeGroups limits the selected resources to these groups, if not
+	// empty. The core group is written as "core".
+	IncludeGroups []string `json:"includeGroups,omitempty"`
+	// ExcludeGroups are the groups whose resources are not selected.
+	ExcludeGroups []string `json:"excludeGroups,omitempty"`
+	// IncludeResources limits the selected resources to these resources,
+	// if not empty. Resources are written as "<resource>.<group>", or
+	// "<resource>" for the core group.
+	IncludeResources []string `json:"includeResources,omitempty"`
+	// ExcludeResources are the resources that are not selected.
+	// Exclusions take precedence over inclusions.
+	ExcludeResources []string `json:"excludeResources,omitempty"`
+}
+
+// ResourceFilter decides which resources are selected by ResourceRules.
+type ResourceFilter struct {
+	includeGroups    sets.String
+	excludeGroups    sets.String
+	includeResources sets.String
+	excludeResources sets.String
+}
+
+// NewResourceFilter returns the ResourceFilter of the rules.
+func NewResourceFilter(rules ResourceRules) *ResourceFilter {
+	return &ResourceFilter{
+		includeGroups:    sets.NewString(rules.IncludeGroups...),
+		excludeGroups:    sets.NewString(rules.ExcludeGroups...),
+		includeResources: sets.NewString(rules.IncludeResources...),
+		excludeResources: sets.NewString(rules.ExcludeResources...),
+	}
+}
+
+func groupName(group string) string {
+	if group == "" {
+		return coreGroup
+	}
+	return group
+}
+
+// ResourceName returns how the resource of the group is written in the
+// ResourceRules, "<resource>.<group>", or "<resource>" for the core group.
+func ResourceName(group, resource string) string {
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
+
+// Allowed returns true if the resource of the group is selected. If not, it
+// also returns why.
+func (f *ResourceFilter) Allowed(group, resource string) (bool, string) {
+	g, r := groupName(group), ResourceName(group, resource)
+	switch {
+	case f.excludeGroups.Has(g):
+		return false, fmt.Sprintf("group %s is excluded", g)
+	case f.excludeResources.Has(r):
+		return false, fmt.Sprintf("resource %s is excluded", r)
+	case f.includeGroups.Len() > 0 && !f.includeGroups.Has(g):
+		return false, fmt.Sprintf("group %s is not included", g)
+	case f.includeResources.Len() > 0 && !f.includeResources.Has(r):
+		return false, fmt.Sprintf("resource %s is not included", r)
+	}
+	return true, ""
+}
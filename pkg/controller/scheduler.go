@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+// DefaultPriorityAgingPeriod is how long a migration waits pending before
+// its priority grows by one, unless configured otherwise.
+const DefaultPriorityAgingPeriod = time.Hour
+
+// EffectivePriority returns the priority of the migration m at now: its
+// .spec.priority, plus one for every agingPeriod elapsed since its
+// creation. An agingPeriod of 0 disables the aging.
+func EffectivePriority(m *migrationv1alpha1.StorageVersionMigration, now time.Time, agingPeriod time.Duration) int64 {
+	priority := int64(m.Spec.Priority)
+	if agingPeriod <= 0 || m.CreationTimestamp.IsZero() {
+		return priority
+	}
+	if waited := now.Sub(m.CreationTimestamp.Time); waited > 0 {
+		priority += int64(waited / agingPeriod)
+	}
+	return priority
+}
+
+// SortMigrations sorts the migrations in the order the migrator runs them:
+// by decreasing effective priority at now, then by creation time, then by
+// name.
+func SortMigrations(migrations []*migrationv1alpha1.StorageVersionMigration, now time.Time, agingPeriod time.Duration) {
+	priorities := make(map[string]int64, len(migrations))
+	for _, m := range migrations {
+		priorities[m.Name] = EffectivePriority(m, now, agingPeriod)
+	}
+	sort.SliceStable(migrations, func(i, j int) bool {
+		a, b := migrations[i], migrations[j]
+		if priorities[a.Name] != priorities[b.Name] {
+			return priorities[a.Name] > priorities[b.Name]
+		}
+		if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+			return a.CreationTimestamp.Before(&b.CreationTimestamp)
+		}
+		return a.Name < b.Name
+	})
+}
+
+// toMigrations converts the objects of the informer.
+func toMigrations(objs []interface{}) ([]*migrationv1alpha1.StorageVersionMigration, error) {
+	migrations := make([]*migrationv1alpha1.StorageVersionMigration, 0, len(objs))
+	for _, obj := range objs {
+		m, ok := obj.(*migrationv1alpha1.StorageVersionMigration)
+		if !ok {
+			return nil, fmt.Errorf("expected StorageVersionMigration, got %#v", reflect.TypeOf(obj))
+		}
+		migrations = append(migrations, m)
+	}
+	return migrations, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	testingclock "k8s.io/utils/clock/testing"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator/metrics"
+)
+
+var schedulerNow = time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func newPrioritizedMigration(name, resource string, priority int32, age time.Duration) *migrationv1alpha1.StorageVersionMigration {
+	m := newMigrationForResource(name, migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: resource})
+	m.Spec.Priority = priority
+	m.CreationTimestamp = metav1.NewTime(schedulerNow.Add(-age))
+	return m
+}
+
+func TestEffectivePriority(t *testing.T) {
+	m := newPrioritizedMigration("m", "pods", 2, 150*time.Minute)
+	for _, tc := range []struct {
+		agingPeriod time.Duration
+		expected    int64
+	}{
+		{0, 2},
+		{time.Hour, 4},
+		{time.Minute, 152},
+	} {
+		if a := EffectivePriority(m, schedulerNow, tc.agingPeriod); a != tc.expected {
+			t.Errorf("aging period %v: expected %d, got %d", tc.agingPeriod, tc.expected, a)
+		}
+	}
+}
+
+func TestSortMigrations(t *testing.T) {
+	migrations := []*migrationv1alpha1.StorageVersionMigration{
+		newPrioritizedMigration("low-old", "pods", 0, 30*time.Minute),
+		newPrioritizedMigration("high-new", "nodes", 10, time.Minute),
+		newPrioritizedMigration("low-older", "secrets", 0, 40*time.Minute),
+		newPrioritizedMigration("b-same-age", "services", 0, 30*time.Minute),
+		newPrioritizedMigration("starving", "configmaps", -5, 10*time.Hour),
+	}
+	names := func() []string {
+		var names []string
+		for _, m := range migrations {
+			names = append(names, m.Name)
+		}
+		return names
+	}
+
+	SortMigrations(migrations, schedulerNow, 0)
+	expected := []string{"high-new", "low-older", "b-same-age", "low-old", "starving"}
+	if a := names(); !reflect.DeepEqual(expected, a) {
+		t.Errorf("without aging: expected %v, got %v", expected, a)
+	}
+
+	// After 10 hours, the starving migration has gained 10.
+	SortMigrations(migrations, schedulerNow, time.Hour)
+	expected = []string{"high-new", "starving", "low-older", "b-same-age", "low-old"}
+	if a := names(); !reflect.DeepEqual(expected, a) {
+		t.Errorf("with aging: expected %v, got %v", expected, a)
+	}
+}
+
+func gaugeValue(t *testing.T, name, resource string) (float64, bool) {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range families {
+		if mf.GetName() != name {
+			continue
+		}
+		for _, metric := range mf.GetMetric() {
+			for _, l := range metric.GetLabel() {
+				if l.GetName() == "resource" && l.GetValue() == resource {
+					return metric.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestProcessRunsHighestPriorityFirst(t *testing.T) {
+	metrics.Metrics.Reset()
+	pods := newPrioritizedMigration("pods", "pods", 0, time.Hour)
+	nodes := newPrioritizedMigration("nodes", "nodes", 5, time.Minute)
+	client := fake.NewSimpleClientset(pods, nodes)
+	km := NewKubeMigrator(newDynamicClient(), client, migrator.DefaultOptions())
+	km.clock = testingclock.NewFakePassiveClock(schedulerNow)
+	km.SetPriorityAgingPeriod(0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go km.migrationInformer.Run(ctx.Done())
+	cache.WaitForCacheSync(ctx.Done(), km.migrationInformer.HasSynced)
+	km.process(ctx)
+
+	if m := getMigration(t, client, "nodes"); !HasCondition(m, migrationv1alpha1.MigrationSucceeded) {
+		t.Errorf("expected the nodes migration to run first, got %v", m.Status.Conditions)
+	}
+	if m := getMigration(t, client, "pods"); len(m.Status.Conditions) != 0 {
+		t.Errorf("expected the pods migration to stay pending, got %v", m.Status.Conditions)
+	}
+	for _, tc := range []struct {
+		resource string
+		position float64
+		priority float64
+	}{
+		{"/v1, Resource=nodes", 1, 5},
+		{"/v1, Resource=pods", 2, 0},
+	} {
+		if v, ok := gaugeValue(t, "storage_migrator_core_migrator_pending_migration_position", tc.resource); !ok || v != tc.position {
+			t.Errorf("%s: expected position %v, got %v", tc.resource, tc.position, v)
+		}
+		if v, ok := gaugeValue(t, "storage_migrator_core_migrator_pending_migration_effective_priority", tc.resource); !ok || v != tc.priority {
+			t.Errorf("%s: expected effective priority %v, got %v", tc.resource, tc.priority, v)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+// StorageStateName returns the name of the storageState of the resource,
+// "<resource>.<group>", or "<resource>" for the core group. The version of
+// the resource is ignored.
+func StorageStateName(resource migrationv1alpha1.GroupVersionResource) string {
+	// TODO: add this rule to the CRD validation
+	// TODO: we might use ResourceID as the name in the future.
+	if resource.Group == "" {
+		return resource.Resource
+	}
+	return resource.Resource + "." + resource.Group
+}
+
+// NewStorageState returns a storageState of the resource with an empty
+// status. Note that the apiserver resets the status field for the POST
+// request, the status needs to be updated via the status endpoint.
+func NewStorageState(resource migrationv1alpha1.GroupVersionResource) *migrationv1alpha1.StorageState {
+	return &migrationv1alpha1.StorageState{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: StorageStateName(resource),
+		},
+		Spec: migrationv1alpha1.StorageStateSpec{
+			Resource: migrationv1alpha1.GroupResource{
+				Group:    resource.Group,
+				Resource: resource.Resource,
+			},
+		},
+	}
+}
+
+// IsStorageStateMigrated returns true if all the persisted objects of the
+// resource of the storageState are encoded in its current storage version.
+func IsStorageStateMigrated(ss *migrationv1alpha1.StorageState) bool {
+	if len(ss.Status.PersistedStorageVersionHashes) != 1 {
+		return false
+	}
+	return ss.Status.CurrentStorageVersionHash == ss.Status.PersistedStorageVersionHashes[0]
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
+)
+
+// UnfinishedMigrations returns the pending and running migrations of the
+// group and resource of r, found in an indexer with the ResourceIndex.
+func UnfinishedMigrations(indexer cache.Indexer, r migrationv1alpha1.GroupVersionResource) ([]*migrationv1alpha1.StorageVersionMigration, error) {
+	objs, err := indexer.ByIndex(ResourceIndex, ToIndex(r))
+	if err != nil {
+		return nil, err
+	}
+	migrations, err := toMigrations(objs)
+	if err != nil {
+		return nil, err
+	}
+	var unfinished []*migrationv1alpha1.StorageVersionMigration
+	for _, m := range migrations {
+		if !IsFinished(m) {
+			unfinished = append(unfinished, m)
+		}
+	}
+	return unfinished, nil
+}
+
+// SupersedeMigration adds the Superseded condition with the reason and the
+// message to the migration m, and removes its Running condition. The
+// migrator stops running a migration once it is superseded.
+func SupersedeMigration(ctx context.Context, client migrationclient.Interface, m *migrationv1alpha1.StorageVersionMigration, reason, message string) error {
+	name := m.Name
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if m == nil {
+			fresh, err := client.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			m = fresh
+		}
+		if IsFinished(m) {
+			return nil
+		}
+		updated := m.DeepCopy()
+		var conditions []migrationv1alpha1.MigrationCondition
+		for _, c := range updated.Status.Conditions {
+			if c.Type != migrationv1alpha1.MigrationRunning {
+				conditions = append(conditions, c)
+			}
+		}
+		updated.Status.Conditions = append(conditions, migrationv1alpha1.MigrationCondition{
+			Type:           migrationv1alpha1.MigrationSuperseded,
+			Status:         corev1.ConditionTrue,
+			LastUpdateTime: metav1.Now(),
+			Reason:         reason,
+			Message:        message,
+		})
+		_, err := client.MigrationV1alpha1().StorageVersionMigrations().UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+		if errors.IsConflict(err) {
+			m = nil
+		}
+		return err
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
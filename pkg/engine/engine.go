@@ -0,0 +1,240 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package engine is the stable Go API of the storage version migration
+// engine, for the programs migrating the objects of their own resources,
+// e.g. operators migrating their custom resources before removing a version.
+//
+// An Engine rewrites every object of a resource, so that the apiserver
+// stores it in the current storage version:
+//
+//	e, err := engine.New(engine.Options{
+//		Resource: schema.GroupVersionResource{Group: "example.com", Version: "v2", Resource: "widgets"},
+//		Client:   dynamicClient,
+//		Progress: engine.NewFileProgressStore("/var/lib/widgets/progress"),
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	result, err := e.Run(ctx)
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+
+	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/typed/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/migrator"
+)
+
+// Strategy is how the engine rewrites the objects.
+type Strategy string
+
+const (
+	// StrategyDirect rewrites the objects. It is the default.
+	StrategyDirect Strategy = "Direct"
+	// StrategyDryRun rewrites the objects in dry-run mode only, to check
+	// that they can be rewritten. The progress is neither loaded nor
+	// saved.
+	StrategyDryRun Strategy = "DryRun"
+	// StrategyDryRunFirst rewrites the objects in dry-run mode, then, if
+	// all of them could be rewritten, rewrites them. The dry run is skipped
+	// when the progress store has saved a continue token, i.e. when the
+	// engine resumes a run that already started rewriting the objects.
+	StrategyDryRunFirst Strategy = "DryRunFirst"
+)
+
+const (
+	// DefaultChunkSize is the default number of objects listed per request.
+	DefaultChunkSize = 100
+	// DefaultConcurrency is the default number of objects rewritten in
+	// parallel.
+	DefaultConcurrency = 1
+)
+
+// ProgressStore saves the continue token of the last migrated chunk, so that
+// an interrupted run resumes from it.
+type ProgressStore = migrator.ProgressStore
+
+// ProgressFlusher is implemented by the progress stores deferring their
+// writes.
+type ProgressFlusher = migrator.ProgressFlusher
+
+// ProgressCounter is implemented by the progress stores recording the number
+// of objects rewritten.
+type ProgressCounter = migrator.ProgressCounter
+
+// Result describes what a run rewrote, skipped and failed.
+type Result = migrator.Report
+
+// ObjectFailure is an object that could not be rewritten.
+type ObjectFailure = migrator.ObjectFailure
+
+// Hooks are callbacks run for every object, concurrently.
+type Hooks = migrator.Hooks
+
+// ObjectOutcome is what happened to an object.
+type ObjectOutcome = migrator.ObjectOutcome
+
+// ErrorAction tells the engine what to do with an object that could not be
+// rewritten.
+type ErrorAction = migrator.ErrorAction
+
+const (
+	ObjectRewritten = migrator.ObjectRewritten
+	ObjectSkipped   = migrator.ObjectSkipped
+	ObjectFailed    = migrator.ObjectFailed
+
+	ErrorActionFail   = migrator.ErrorActionFail
+	ErrorActionIgnore = migrator.ErrorActionIgnore
+)
+
+// Options configures an Engine. Resource and Client are required.
+type Options struct {
+	// Resource is the resource whose objects are migrated.
+	Resource schema.GroupVersionResource
+	// Client sends the requests to the apiserver.
+	Client dynamic.Interface
+	// Progress saves the progress of the run. If nil, every run starts
+	// from the beginning.
+	Progress ProgressStore
+	// Strategy is how the objects are rewritten. Defaults to
+	// StrategyDirect.
+	Strategy Strategy
+	// ChunkSize is the number of objects listed per request. Defaults to
+	// DefaultChunkSize.
+	ChunkSize int64
+	// Concurrency is the number of objects rewritten in parallel. Defaults
+	// to DefaultConcurrency.
+	Concurrency int
+	// LabelSelector and FieldSelector restrict the objects migrated.
+	LabelSelector string
+	FieldSelector string
+	// Hooks are called for every object.
+	Hooks Hooks
+}
+
+// Engine migrates the objects of a resource.
+type Engine struct {
+	options Options
+}
+
+// New validates the options, sets the defaults of the unset ones, and
+// returns an Engine.
+func New(options Options) (*Engine, error) {
+	if options.Resource.Resource == "" {
+		return nil, fmt.Errorf("the resource to migrate must be set")
+	}
+	if options.Client == nil {
+		return nil, fmt.Errorf("the client must be set")
+	}
+	switch options.Strategy {
+	case "":
+		options.Strategy = StrategyDirect
+	case StrategyDirect, StrategyDryRun, StrategyDryRunFirst:
+	default:
+		return nil, fmt.Errorf("unsupported strategy %q", options.Strategy)
+	}
+	if options.ChunkSize < 0 || options.Concurrency < 0 {
+		return nil, fmt.Errorf("the chunk size and the concurrency must not be negative")
+	}
+	if options.ChunkSize == 0 {
+		options.ChunkSize = DefaultChunkSize
+	}
+	if options.Concurrency == 0 {
+		options.Concurrency = DefaultConcurrency
+	}
+	if _, err := labels.Parse(options.LabelSelector); err != nil {
+		return nil, fmt.Errorf("invalid label selector: %v", err)
+	}
+	if _, err := fields.ParseSelector(options.FieldSelector); err != nil {
+		return nil, fmt.Errorf("invalid field selector: %v", err)
+	}
+	return &Engine{options: options}, nil
+}
+
+// Run migrates the objects. It returns the result of the last pass over the
+// objects, i.e. of the dry run if the dry run of StrategyDryRunFirst fails.
+// Run returns the error of the first object that failed, unless the OnError
+// hook ignored it, or the error of ctx if it is done before the end.
+func (e *Engine) Run(ctx context.Context) (Result, error) {
+	o := e.options
+	if o.Strategy == StrategyDryRun {
+		return e.pass(ctx, true)
+	}
+	if o.Strategy == StrategyDryRunFirst {
+		resuming := false
+		if o.Progress != nil {
+			token, err := o.Progress.Load(ctx)
+			if err != nil {
+				return Result{}, err
+			}
+			resuming = token != ""
+		}
+		if !resuming {
+			if result, err := e.pass(ctx, true); err != nil {
+				return result, fmt.Errorf("dry run failed: %v", err)
+			}
+		}
+	}
+	return e.pass(ctx, false)
+}
+
+// pass runs the migrator over the objects once.
+func (e *Engine) pass(ctx context.Context, dryRun bool) (Result, error) {
+	o := e.options
+	m := migrator.NewMigrator(o.Resource, o.Client, o.Progress, migrator.Options{
+		ChunkLimit:    o.ChunkSize,
+		Concurrency:   o.Concurrency,
+		DryRun:        dryRun,
+		LabelSelector: o.LabelSelector,
+		FieldSelector: o.FieldSelector,
+	})
+	m.SetHooks(o.Hooks)
+	err := m.Run(ctx)
+	return m.Report(), err
+}
+
+// NewFileProgressStore returns a ProgressStore saving the continue token in
+// a local file.
+func NewFileProgressStore(path string) ProgressStore {
+	return migrator.NewFileProgressStore(path)
+}
+
+// NewConfigMapProgressStore returns a ProgressStore saving the continue
+// token in a key of a ConfigMap.
+func NewConfigMapProgressStore(client kubernetes.Interface, namespace, name, key string) ProgressStore {
+	return migrator.NewConfigMapProgressStore(client, namespace, name, key)
+}
+
+// NewLeaseProgressStore returns a ProgressStore saving the continue token in
+// an annotation of a Lease.
+func NewLeaseProgressStore(client kubernetes.Interface, namespace, name string) ProgressStore {
+	return migrator.NewLeaseProgressStore(client, namespace, name)
+}
+
+// NewMigrationProgressStore returns a ProgressStore saving the continue
+// token in the status of a storageVersionMigration, at most every interval.
+func NewMigrationProgressStore(client migrationclient.StorageVersionMigrationInterface, name string, interval time.Duration) ProgressStore {
+	return migrator.NewMigrationProgressStore(client, name, interval)
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/fake"
+	clitesting "k8s.io/client-go/testing"
+)
+
+var pods = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+
+func newPod(name string, labels map[string]string) *unstructured.Unstructured {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("default")
+	pod.SetName(name)
+	pod.SetLabels(labels)
+	return pod
+}
+
+func newClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{pods: "PodList"}, objects...)
+}
+
+// pagedClient serves the lists of the fake client in chunks of opts.Limit
+// objects, which the fake client ignores. The continue token is the name of
+// the last object of the chunk.
+type pagedClient struct {
+	*fake.FakeDynamicClient
+}
+
+func (c pagedClient) Resource(resource schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return pagedResource{c.FakeDynamicClient.Resource(resource)}
+}
+
+type pagedResource struct {
+	dynamic.NamespaceableResourceInterface
+}
+
+func (r pagedResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return pagedNamespacedResource{r.NamespaceableResourceInterface.Namespace(namespace)}
+}
+
+type pagedNamespacedResource struct {
+	dynamic.ResourceInterface
+}
+
+func (r pagedNamespacedResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list, err := r.ResourceInterface.List(ctx, metav1.ListOptions{LabelSelector: opts.LabelSelector})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+	items := list.Items
+	for len(items) > 0 && opts.Continue != "" && items[0].GetName() <= opts.Continue {
+		items = items[1:]
+	}
+	list.SetContinue("")
+	if opts.Limit > 0 && int64(len(items)) > opts.Limit {
+		items = items[:opts.Limit]
+		list.SetContinue(items[len(items)-1].GetName())
+	}
+	list.Items = items
+	return list, nil
+}
+
+// updates returns the sorted names of the objects updated.
+func updates(client *fake.FakeDynamicClient) []string {
+	var names []string
+	for _, a := range client.Actions() {
+		if u, ok := a.(clitesting.UpdateAction); ok {
+			names = append(names, u.GetObject().(*unstructured.Unstructured).GetName())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// failUpdates makes the updates of the objects fail with an error that is
+// not retried.
+func failUpdates(client *fake.FakeDynamicClient, names ...string) {
+	client.PrependReactor("update", "pods", func(a clitesting.Action) (bool, runtime.Object, error) {
+		name := a.(clitesting.UpdateAction).GetObject().(*unstructured.Unstructured).GetName()
+		for _, n := range names {
+			if n == name {
+				return true, nil, errors.NewMethodNotSupported(pods.GroupResource(), "update "+name)
+			}
+		}
+		return false, nil, nil
+	})
+}
+
+func TestNewValidatesOptions(t *testing.T) {
+	client := newClient()
+	tests := []struct {
+		name    string
+		options Options
+		err     string
+	}{
+		{"no resource", Options{Client: client}, "the resource to migrate must be set"},
+		{"no client", Options{Resource: pods}, "the client must be set"},
+		{"unknown strategy", Options{Resource: pods, Client: client, Strategy: "Later"}, `unsupported strategy "Later"`},
+		{"negative concurrency", Options{Resource: pods, Client: client, Concurrency: -1}, "must not be negative"},
+		{"invalid label selector", Options{Resource: pods, Client: client, LabelSelector: "a in"}, "invalid label selector"},
+		{"invalid field selector", Options{Resource: pods, Client: client, FieldSelector: "a"}, "invalid field selector"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.options)
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+	e, err := New(Options{Resource: pods, Client: client})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.options.Strategy != StrategyDirect || e.options.ChunkSize != DefaultChunkSize || e.options.Concurrency != DefaultConcurrency {
+		t.Errorf("unexpected defaults %+v", e.options)
+	}
+}
+
+func TestRun(t *testing.T) {
+	client := newClient(newPod("a", nil), newPod("b", nil), newPod("c", nil))
+	progress := NewFileProgressStore(filepath.Join(t.TempDir(), "progress"))
+	e, err := New(Options{Resource: pods, Client: client, Progress: progress, Concurrency: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := e.Run(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Rewritten != 3 || result.Skipped != 0 || result.Failed != 0 || result.DryRun {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if names := updates(client); strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("expected a, b and c to be updated, got %v", names)
+	}
+}
+
+func TestRunLabelSelector(t *testing.T) {
+	client := newClient(newPod("a", map[string]string{"app": "web"}), newPod("b", nil))
+	e, err := New(Options{Resource: pods, Client: client, LabelSelector: "app=web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Run(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if names := updates(client); strings.Join(names, ",") != "a" {
+		t.Errorf("expected only a to be updated, got %v", names)
+	}
+	for _, a := range client.Actions() {
+		if l, ok := a.(clitesting.ListActionImpl); ok && l.GetListRestrictions().Labels.String() != "app=web" {
+			t.Errorf("expected the list to select app=web, got %v", l.GetListRestrictions().Labels)
+		}
+	}
+}
+
+func TestRunHooks(t *testing.T) {
+	client := newClient(newPod("a", nil), newPod("b", nil), newPod("c", nil))
+	failUpdates(client, "c")
+	var lock sync.Mutex
+	outcomes := map[string]ObjectOutcome{}
+	e, err := New(Options{
+		Resource: pods,
+		Client:   client,
+		Hooks: Hooks{
+			BeforeObject: func(_ context.Context, obj *unstructured.Unstructured) bool {
+				return obj.GetName() != "b"
+			},
+			AfterObject: func(_ context.Context, obj *unstructured.Unstructured, outcome ObjectOutcome, err error) {
+				lock.Lock()
+				defer lock.Unlock()
+				outcomes[obj.GetName()] = outcome
+			},
+			OnError: func(context.Context, *unstructured.Unstructured, error) ErrorAction {
+				return ErrorActionIgnore
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := e.Run(context.TODO())
+	if err != nil {
+		t.Fatalf("expected the error of c to be ignored, got %v", err)
+	}
+	expected := map[string]ObjectOutcome{"a": ObjectRewritten, "b": ObjectSkipped, "c": ObjectFailed}
+	for name, outcome := range expected {
+		if outcomes[name] != outcome {
+			t.Errorf("expected %s to be %s, got %s", name, outcome, outcomes[name])
+		}
+	}
+	if result.Rewritten != 1 || result.Skipped != 1 || result.Failed != 1 || len(result.FailedObjects) != 1 || result.FailedObjects[0].Name != "c" {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestRunFailsOnError(t *testing.T) {
+	client := newClient(newPod("a", nil), newPod("b", nil))
+	failUpdates(client, "b")
+	e, err := New(Options{Resource: pods, Client: client})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := e.Run(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "update b") {
+		t.Errorf("expected the error of b, got %v", err)
+	}
+	if result.Rewritten != 1 || result.Failed != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestRunDryRun(t *testing.T) {
+	client := newClient(newPod("a", nil))
+	progress := &memoryProgress{}
+	e, err := New(Options{Resource: pods, Client: client, Progress: progress, Strategy: StrategyDryRun})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := e.Run(context.TODO())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.DryRun || result.Rewritten != 1 {
+		t.Errorf("expected a dry run, got %+v", result)
+	}
+	if progress.loads != 0 {
+		t.Errorf("expected the progress not to be loaded by a dry run")
+	}
+}
+
+func TestRunDryRunFirst(t *testing.T) {
+	tests := []struct {
+		name            string
+		fail            []string
+		token           string
+		expectDryRun    bool
+		expectUpdates   int
+		expectErrPrefix string
+	}{
+		{
+			name:          "dry run then run",
+			expectUpdates: 4,
+		},
+		{
+			name:            "failed dry run",
+			fail:            []string{"b"},
+			expectDryRun:    true,
+			expectUpdates:   2,
+			expectErrPrefix: "dry run failed",
+		},
+		{
+			name:          "resumed run skips the dry run",
+			token:         "resume",
+			expectUpdates: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient(newPod("a", nil), newPod("b", nil))
+			failUpdates(client, tt.fail...)
+			e, err := New(Options{Resource: pods, Client: client, Progress: &memoryProgress{token: tt.token}, Strategy: StrategyDryRunFirst})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := e.Run(context.TODO())
+			if tt.expectErrPrefix == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.expectErrPrefix != "" && (err == nil || !strings.HasPrefix(err.Error(), tt.expectErrPrefix)) {
+				t.Errorf("expected an error starting with %q, got %v", tt.expectErrPrefix, err)
+			}
+			if result.DryRun != tt.expectDryRun {
+				t.Errorf("expected the result of the dry run %v, got %+v", tt.expectDryRun, result)
+			}
+			if n := len(updates(client)); n != tt.expectUpdates {
+				t.Errorf("expected %d updates, got %d", tt.expectUpdates, n)
+			}
+		})
+	}
+}
+
+func TestRunTwiceOnSameProgressStore(t *testing.T) {
+	client := newClient(newPod("a", nil), newPod("b", nil), newPod("c", nil))
+	progress := NewFileProgressStore(filepath.Join(t.TempDir(), "progress"))
+	e, err := New(Options{Resource: pods, Client: pagedClient{client}, Progress: progress, ChunkSize: 1, Strategy: StrategyDryRunFirst})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for run := 1; run <= 2; run++ {
+		client.ClearActions()
+		if _, err := e.Run(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		// Every object is updated by the dry run, then by the run.
+		if names := updates(client); strings.Join(names, ",") != "a,a,b,b,c,c" {
+			t.Errorf("run %d: expected a, b and c to be updated twice, got %v", run, names)
+		}
+		if token, err := progress.Load(context.TODO()); err != nil || token != "" {
+			t.Errorf("run %d: expected the progress to be cleared, got %q, %v", run, token, err)
+		}
+	}
+}
+
+type memoryProgress struct {
+	token string
+	loads int
+}
+
+func (p *memoryProgress) Load(context.Context) (string, error) {
+	p.loads++
+	return p.token, nil
+}
+
+func (p *memoryProgress) Save(_ context.Context, token string) error {
+	p.token = token
+	return nil
+}
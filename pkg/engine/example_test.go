@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package engine_test
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/engine"
+)
+
+var widgets = schema.GroupVersionResource{Group: "example.com", Version: "v2", Resource: "widgets"}
+
+func newWidget(name string) *unstructured.Unstructured {
+	w := &unstructured.Unstructured{}
+	w.SetAPIVersion("example.com/v2")
+	w.SetKind("Widget")
+	w.SetNamespace("default")
+	w.SetName(name)
+	return w
+}
+
+// The operator of the widgets migrates them before it removes their old
+// version. The fake client stands for the dynamic client of the operator.
+func Example() {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{widgets: "WidgetList"},
+		newWidget("a"), newWidget("b"))
+	e, err := engine.New(engine.Options{
+		Resource: widgets,
+		Client:   client,
+		Strategy: engine.StrategyDryRunFirst,
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	result, err := e.Run(context.TODO())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("rewritten: %d, failed: %d\n", result.Rewritten, result.Failed)
+	// Output: rewritten: 2, failed: 0
+}
+
+// The OnError hook lets the run go on when an object cannot be rewritten;
+// the failures are listed in the result.
+func ExampleHooks() {
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{widgets: "WidgetList"},
+		newWidget("a"), newWidget("legacy"))
+	e, err := engine.New(engine.Options{
+		Resource: widgets,
+		Client:   client,
+		Hooks: engine.Hooks{
+			// Leave the legacy widget alone.
+			BeforeObject: func(_ context.Context, obj *unstructured.Unstructured) bool {
+				return obj.GetName() != "legacy"
+			},
+			OnError: func(context.Context, *unstructured.Unstructured, error) engine.ErrorAction {
+				return engine.ErrorActionIgnore
+			},
+		},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	result, err := e.Run(context.TODO())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("rewritten: %d, skipped: %d\n", result.Rewritten, result.Skipped)
+	// Output: rewritten: 1, skipped: 1
+}
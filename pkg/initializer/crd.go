@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// crdSchemaVersionAnnotation records the schema version of a CRD
+	// installed by the initializer. A CRD without the annotation has
+	// schema version 0.
+	crdSchemaVersionAnnotation = "migration.k8s.io/crd-schema-version"
+	// fieldManager is the field manager of the server-side applied CRDs.
+	fieldManager = createdBy
+
+	defaultCRDEstablishedTimeout = time.Minute
+)
+
+// initializeCRDs installs or upgrades the CRDs of the migrator in place, and
+// waits for them to become established. Existing custom resources are kept.
+func (init *initializer) initializeCRDs(ctx context.Context) error {
+	for _, crd := range crds() {
+		if err := init.applyCRD(ctx, crd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// crds returns the CRDs of the migrator, in the order they are installed.
+func crds() []*v1.CustomResourceDefinition {
+	return []*v1.CustomResourceDefinition{migrationCRD(), storageStateCRD(), migrationPolicyCRD(), migrationPlanCRD(), migrationSummaryCRD()}
+}
+
+func crdSchemaVersion(crd *v1.CustomResourceDefinition) (int, error) {
+	v, ok := crd.Annotations[crdSchemaVersionAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	version, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation of CRD %s: %v", crdSchemaVersionAnnotation, crd.Name, err)
+	}
+	return version, nil
+}
+
+// applyCRD server-side applies the crd, unless the existing CRD of the same
+// name has a newer schema version, and waits for it to become established.
+func (init *initializer) applyCRD(ctx context.Context, crd *v1.CustomResourceDefinition) error {
+	version, err := crdSchemaVersion(crd)
+	if err != nil {
+		return err
+	}
+	existing, err := init.crdClient.Get(ctx, crd.Name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		existingVersion, err := crdSchemaVersion(existing)
+		if err != nil {
+			return err
+		}
+		if existingVersion > version {
+			return fmt.Errorf("refusing to downgrade CRD %s from schema version %d to %d", crd.Name, existingVersion, version)
+		}
+	}
+
+	crd = crd.DeepCopy()
+	crd.TypeMeta = metav1.TypeMeta{
+		APIVersion: v1.SchemeGroupVersion.String(),
+		Kind:       "CustomResourceDefinition",
+	}
+	data, err := json.Marshal(crd)
+	if err != nil {
+		return err
+	}
+	klog.Infof("applying CRD %s with schema version %d", crd.Name, version)
+	force := true
+	if _, err := init.crdClient.Patch(ctx, crd.Name, types.ApplyPatchType, data, metav1.PatchOptions{
+		FieldManager: fieldManager,
+		Force:        &force,
+	}); err != nil {
+		return fmt.Errorf("failed to apply CRD %s: %v", crd.Name, err)
+	}
+	return init.waitForCRDEstablished(ctx, crd.Name)
+}
+
+func (init *initializer) waitForCRDEstablished(ctx context.Context, name string) error {
+	err := wait.PollImmediate(500*time.Millisecond, init.crdEstablishedTimeout, func() (bool, error) {
+		crd, err := init.crdClient.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, c := range crd.Status.Conditions {
+			if c.Type == v1.Established && c.Status == v1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		return fmt.Errorf("CRD %s did not become established: %v", name, err)
+	}
+	return nil
+}
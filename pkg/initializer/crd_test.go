@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	core "k8s.io/client-go/testing"
+)
+
+// applyReactor emulates server-side apply, which the fake clientset does not
+// support for absent objects. If established is true, the applied CRDs are
+// established immediately.
+func applyReactor(client *apiextensionsfake.Clientset, established bool) core.ReactionFunc {
+	return func(action core.Action) (bool, runtime.Object, error) {
+		patch := action.(core.PatchAction)
+		if patch.GetPatchType() != types.ApplyPatchType {
+			return false, nil, nil
+		}
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := json.Unmarshal(patch.GetPatch(), crd); err != nil {
+			return true, nil, err
+		}
+		if established {
+			crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{{
+				Type:   apiextensionsv1.Established,
+				Status: apiextensionsv1.ConditionTrue,
+			}}
+		}
+		tracker := client.Tracker()
+		gvr := apiextensionsv1.SchemeGroupVersion.WithResource("customresourcedefinitions")
+		err := tracker.Update(gvr, crd, "")
+		if errors.IsNotFound(err) {
+			err = tracker.Add(crd)
+		}
+		return true, crd, err
+	}
+}
+
+func newCRDInitializer(client *apiextensionsfake.Clientset, established bool) *initializer {
+	client.PrependReactor("patch", "customresourcedefinitions", applyReactor(client, established))
+	return &initializer{
+		crdClient:             client.ApiextensionsV1().CustomResourceDefinitions(),
+		crdEstablishedTimeout: time.Second,
+	}
+}
+
+func TestInitializeCRDs(t *testing.T) {
+	existing := migrationCRD()
+	delete(existing.Annotations, crdSchemaVersionAnnotation)
+	client := apiextensionsfake.NewSimpleClientset(existing)
+	init := newCRDInitializer(client, true)
+	if err := init.initializeCRDs(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	var applied []string
+	for _, a := range client.Actions() {
+		switch a.GetVerb() {
+		case "patch":
+			applied = append(applied, a.(core.PatchAction).GetName())
+		case "create", "delete", "update":
+			t.Errorf("unexpected action %v", a)
+		}
+	}
+	if e, a := "storageversionmigrations.migration.k8s.io,storagestates.migration.k8s.io,migrationpolicies.migration.k8s.io,migrationplans.migration.k8s.io,migrationsummaries.migration.k8s.io", strings.Join(applied, ","); e != a {
+		t.Errorf("expected applied CRDs %s, got %s", e, a)
+	}
+	crd, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(context.TODO(), "storageversionmigrations.migration.k8s.io", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := crdSchemaVersion(crd); err != nil || v != migrationCRDSchemaVersion {
+		t.Errorf("expected schema version %d, got %d, %v", migrationCRDSchemaVersion, v, err)
+	}
+}
+
+func TestInitializeCRDsRefusesDowngrade(t *testing.T) {
+	newer := storageStateCRD()
+	newer.Annotations[crdSchemaVersionAnnotation] = "1000"
+	client := apiextensionsfake.NewSimpleClientset(newer)
+	init := newCRDInitializer(client, true)
+	err := init.initializeCRDs(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "refusing to downgrade") {
+		t.Fatalf("expected downgrade error, got %v", err)
+	}
+	for _, a := range client.Actions() {
+		if a.GetVerb() == "patch" && a.(core.PatchAction).GetName() == newer.Name {
+			t.Errorf("unexpected action %v", a)
+		}
+	}
+}
+
+func TestInitializeCRDsNotEstablished(t *testing.T) {
+	client := apiextensionsfake.NewSimpleClientset()
+	init := newCRDInitializer(client, false)
+	err := init.initializeCRDs(context.TODO())
+	if err == nil || !strings.Contains(err.Error(), "did not become established") {
+		t.Fatalf("expected established error, got %v", err)
+	}
+}
@@ -27,12 +27,16 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/klog/v2"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/typed/apiregistration/v1"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
 )
 
 type migrationDiscovery struct {
 	discoveryClient  discovery.ServerResourcesInterface
 	crdClient        v1.CustomResourceDefinitionInterface
 	apiserviceClient apiregistrationv1.APIServiceInterface
+	options          Options
+	filter           *controller.ResourceFilter
 }
 
 // NewDiscovery returns a migrationDiscovery struct.
@@ -40,24 +44,32 @@ func NewDiscovery(
 	discoveryClient discovery.ServerResourcesInterface,
 	crdClient v1.CustomResourceDefinitionInterface,
 	apiserviceClient apiregistrationv1.APIServiceInterface,
+	options Options,
 ) *migrationDiscovery {
 	return &migrationDiscovery{
 		discoveryClient:  discoveryClient,
 		crdClient:        crdClient,
 		apiserviceClient: apiserviceClient,
+		options:          options,
+		filter:           controller.NewResourceFilter(options.ResourceRules),
 	}
 }
 
-var blackListResources = sets.NewString(
-	"events",
-)
+// MigratableResource is a resource that potentially needs migration.
+type MigratableResource struct {
+	schema.GroupVersionResource
+	// The storage version hash of the resource in the discovery document.
+	// It might be empty.
+	StorageVersionHash string
+}
 
 // FindMigratableResources finds all the resources that potentially need
 // migration. Although all migratable resources are accessible via multiple
 // versions, the returned list only include one version.
 //
 // It builds the list in these steps:
-// 1. build a map from resource name to the groupVersions, excluding subresources, custom resources, or aggregated resources.
+// 1. build a map from resource name to the groupVersions, excluding subresources, custom resources, or aggregated resources,
+// unless the options include them, and the resources excluded by the options.
 // 2. exclude all the resource that is only available from one groupVersions.
 // 3. exclude the resource that does not support "list" and "update" (thus not migratable).
 //
@@ -68,16 +80,23 @@ var blackListResources = sets.NewString(
 //
 // TODO: if https://github.com/kubernetes/community/pull/2805 is realized,
 // refactor this method to build resource list accurately.
-func (d *migrationDiscovery) FindMigratableResources(ctx context.Context) ([]schema.GroupVersionResource, error) {
-	customGroups, err := d.findCustomGroups(ctx)
-	if err != nil {
-		return nil, err
+func (d *migrationDiscovery) FindMigratableResources(ctx context.Context) ([]MigratableResource, error) {
+	customGroups, aggregatedGroups := sets.NewString(), sets.NewString()
+	var err error
+	if !d.options.IncludeCustomResources {
+		customGroups, err = d.findCustomGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
-	aggregatedGroups, err := d.findAggregatedGroups(ctx)
-	if err != nil {
-		return nil, err
+	if !d.options.IncludeAggregatedResources {
+		aggregatedGroups, err = d.findAggregatedGroups(ctx)
+		if err != nil {
+			return nil, err
+		}
 	}
 	resourceToGroupVersions := make(map[string][]schema.GroupVersion)
+	resourceToHash := make(map[string]string)
 	_, resourceLists, err := d.discoveryClient.ServerGroupsAndResources()
 	if err != nil {
 		return nil, err
@@ -101,7 +120,8 @@ func (d *migrationDiscovery) FindMigratableResources(ctx context.Context) ([]sch
 			if strings.Contains(r.Name, "/") {
 				continue
 			}
-			if blackListResources.Has(r.Name) {
+			if ok, reason := d.filter.Allowed(gv.Group, r.Name); !ok {
+				klog.V(4).Infof("ignored resource %s because %s", controller.ResourceName(gv.Group, r.Name), reason)
 				continue
 			}
 			// ignore resources that cannot be listed and updated
@@ -111,15 +131,22 @@ func (d *migrationDiscovery) FindMigratableResources(ctx context.Context) ([]sch
 			gvs := resourceToGroupVersions[r.Name]
 			gvs = append(gvs, gv)
 			resourceToGroupVersions[r.Name] = gvs
+			// The hash of the returned groupVersion.
+			if gvs[0].Group == gv.Group && resourceToHash[r.Name] == "" {
+				resourceToHash[r.Name] = r.StorageVersionHash
+			}
 		}
 	}
 
-	var ret []schema.GroupVersionResource
+	var ret []MigratableResource
 	for resource, groupVersions := range resourceToGroupVersions {
 		if len(groupVersions) == 1 {
 			continue
 		}
-		ret = append(ret, groupVersions[0].WithResource(resource))
+		ret = append(ret, MigratableResource{
+			GroupVersionResource: groupVersions[0].WithResource(resource),
+			StorageVersionHash:   resourceToHash[resource],
+		})
 	}
 	return ret, nil
 }
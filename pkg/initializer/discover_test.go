@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"reflect"
+	"sort"
 	"testing"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -30,6 +31,8 @@ import (
 	"k8s.io/client-go/kubernetes/fake"
 	v1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
 	aggregatorfake "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/fake"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
 )
 
 const (
@@ -143,6 +146,7 @@ const (
   "resources": [
     {
       "name": "daemonsets",
+      "storageVersionHash": "dshash",
       "singularName": "",
       "namespaced": true,
       "kind": "DaemonSet",
@@ -349,14 +353,71 @@ func TestFindMigratableResources(t *testing.T) {
 
 	crdClient := apiextensionsfake.NewSimpleClientset(fakeCRDs(t)...).ApiextensionsV1().CustomResourceDefinitions()
 	apiserviceClient := aggregatorfake.NewSimpleClientset(fakeAPIServices(t)...).ApiregistrationV1().APIServices()
-	d := NewDiscovery(kubernetes.Discovery(), crdClient, apiserviceClient)
+	d := NewDiscovery(kubernetes.Discovery(), crdClient, apiserviceClient, DefaultOptions())
 	ctx := context.TODO()
 	got, err := d.FindMigratableResources(ctx)
 	if err != nil {
 		t.Fatal(err)
 	}
-	expected := []schema.GroupVersionResource{{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"}}
+	expected := []MigratableResource{{
+		GroupVersionResource: schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"},
+		StorageVersionHash:   "dshash",
+	}}
 	if !reflect.DeepEqual(expected, got) {
 		t.Errorf("expected %v, got %v", expected, got)
 	}
 }
+
+func TestFindMigratableResourcesOptions(t *testing.T) {
+	daemonsets := schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"}
+	events := schema.GroupVersionResource{Version: "v1", Resource: "events"}
+	for _, tc := range []struct {
+		name     string
+		options  Options
+		expected []schema.GroupVersionResource
+	}{
+		{
+			name:     "no exclusions",
+			options:  Options{},
+			expected: []schema.GroupVersionResource{daemonsets, events},
+		},
+		{
+			name:    "excluded group",
+			options: Options{ResourceRules: controller.ResourceRules{ExcludeGroups: []string{"extensions"}, ExcludeResources: []string{"events"}}},
+		},
+		{
+			name:     "included groups",
+			options:  Options{ResourceRules: controller.ResourceRules{IncludeGroups: []string{"core", "events.k8s.io"}}},
+			expected: []schema.GroupVersionResource{events},
+		},
+		{
+			name:    "included resource",
+			options: Options{ResourceRules: controller.ResourceRules{IncludeResources: []string{"events"}}},
+		},
+		{
+			name:     "exclusions take precedence",
+			options:  Options{ResourceRules: controller.ResourceRules{IncludeGroups: []string{"extensions", "apps", "core", "events.k8s.io"}, ExcludeResources: []string{"events"}}},
+			expected: []schema.GroupVersionResource{daemonsets},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kubernetes := fake.NewSimpleClientset()
+			kubernetes.Fake.Resources = fakeAPIResourceLists(t)
+			crdClient := apiextensionsfake.NewSimpleClientset(fakeCRDs(t)...).ApiextensionsV1().CustomResourceDefinitions()
+			apiserviceClient := aggregatorfake.NewSimpleClientset(fakeAPIServices(t)...).ApiregistrationV1().APIServices()
+			d := NewDiscovery(kubernetes.Discovery(), crdClient, apiserviceClient, tc.options)
+			resources, err := d.FindMigratableResources(context.TODO())
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []schema.GroupVersionResource
+			for _, r := range resources {
+				got = append(got, r.GroupVersionResource)
+			}
+			sort.Slice(got, func(i, j int) bool { return got[i].Resource < got[j].Resource })
+			if !reflect.DeepEqual(tc.expected, got) {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -19,14 +19,12 @@ package initializer
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1"
-	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	apiregistrationv1 "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/typed/apiregistration/v1"
@@ -35,10 +33,14 @@ import (
 )
 
 type initializer struct {
-	discovery       *migrationDiscovery
-	crdClient       apiextensionsv1.CustomResourceDefinitionInterface
-	namespaceClient corev1.NamespaceInterface
-	migrationClient v1alpha1.StorageVersionMigrationInterface
+	discovery          *migrationDiscovery
+	crdClient          apiextensionsv1.CustomResourceDefinitionInterface
+	namespaceClient    corev1.NamespaceInterface
+	migrationClient    v1alpha1.StorageVersionMigrationInterface
+	storageStateClient v1alpha1.StorageStateInterface
+	options            Options
+	// How long to wait for an installed CRD to become established.
+	crdEstablishedTimeout time.Duration
 }
 
 func NewInitializer(
@@ -46,22 +48,63 @@ func NewInitializer(
 	crdClient apiextensionsv1.CustomResourceDefinitionInterface,
 	apiserviceClient apiregistrationv1.APIServiceInterface,
 	namespaceClient corev1.NamespaceInterface,
-	migrationGetter v1alpha1.StorageVersionMigrationsGetter,
+	migrationClient v1alpha1.MigrationV1alpha1Interface,
+	options Options,
 ) *initializer {
-	d := NewDiscovery(disocveryClient, crdClient, apiserviceClient)
+	d := NewDiscovery(disocveryClient, crdClient, apiserviceClient, options)
 	return &initializer{
-		discovery:       d,
-		crdClient:       crdClient,
-		namespaceClient: namespaceClient,
-		migrationClient: migrationGetter.StorageVersionMigrations(),
+		discovery:          d,
+		crdClient:          crdClient,
+		namespaceClient:    namespaceClient,
+		migrationClient:    migrationClient.StorageVersionMigrations(),
+		storageStateClient: migrationClient.StorageStates(),
+		options:            options,
+
+		crdEstablishedTimeout: defaultCRDEstablishedTimeout,
 	}
 }
 
 const (
+	// createdBy is recorded in the migrations created by the initializer.
+	createdBy = "storage-version-migration-initializer"
+
 	singularCRDName = "storageversionmigration"
 	pluralCRDName   = "storageversionmigrations"
 	kind            = "StorageVersionMigration"
 	listKind        = "StorageVersionMigrationList"
+
+	singularStorageStateCRDName = "storagestate"
+	pluralStorageStateCRDName   = "storagestates"
+	storageStateKind            = "StorageState"
+	storageStateListKind        = "StorageStateList"
+
+	singularMigrationPolicyCRDName = "migrationpolicy"
+	pluralMigrationPolicyCRDName   = "migrationpolicies"
+	migrationPolicyKind            = "MigrationPolicy"
+	migrationPolicyListKind        = "MigrationPolicyList"
+
+	singularMigrationPlanCRDName = "migrationplan"
+	pluralMigrationPlanCRDName   = "migrationplans"
+	migrationPlanKind            = "MigrationPlan"
+	migrationPlanListKind        = "MigrationPlanList"
+
+	singularMigrationSummaryCRDName = "migrationsummary"
+	pluralMigrationSummaryCRDName   = "migrationsummaries"
+	migrationSummaryKind            = "MigrationSummary"
+	migrationSummaryListKind        = "MigrationSummaryList"
+
+	// migrationCategory groups the storageVersionMigrations and the
+	// storageStates, so that "kubectl get migration" lists both.
+	migrationCategory = "migration"
+
+	// The schema versions of the CRDs installed by the initializer. Bump
+	// them when the schemas change, the initializer refuses to replace a
+	// CRD with an older schema version.
+	migrationCRDSchemaVersion        = 6
+	storageStateCRDSchemaVersion     = 3
+	migrationPolicyCRDSchemaVersion  = 3
+	migrationPlanCRDSchemaVersion    = 1
+	migrationSummaryCRDSchemaVersion = 1
 )
 
 func migrationCRD() *v1.CustomResourceDefinition {
@@ -70,15 +113,18 @@ func migrationCRD() *v1.CustomResourceDefinition {
 			Name: "storageversionmigrations.migration.k8s.io",
 			Annotations: map[string]string{
 				"api-approved.kubernetes.io": "https://github.com/kubernetes/community/pull/2524",
+				crdSchemaVersionAnnotation:   strconv.Itoa(migrationCRDSchemaVersion),
 			},
 		},
 		Spec: v1.CustomResourceDefinitionSpec{
 			Group: "migration.k8s.io",
 			Names: v1.CustomResourceDefinitionNames{
-				Plural:   pluralCRDName,
-				Singular: singularCRDName,
-				Kind:     kind,
-				ListKind: listKind,
+				Plural:     pluralCRDName,
+				Singular:   singularCRDName,
+				ShortNames: []string{"svm"},
+				Kind:       kind,
+				ListKind:   listKind,
+				Categories: []string{migrationCategory},
 			},
 			Scope: v1.ClusterScoped,
 			Versions: []v1.CustomResourceDefinitionVersion{
@@ -89,6 +135,50 @@ func migrationCRD() *v1.CustomResourceDefinition {
 					Subresources: &v1.CustomResourceSubresources{
 						Status: &v1.CustomResourceSubresourceStatus{},
 					},
+					AdditionalPrinterColumns: []v1.CustomResourceColumnDefinition{
+						{
+							Name:        "Resource",
+							Type:        "string",
+							Description: "The resource that is being migrated.",
+							JSONPath:    ".spec.resource.resource",
+						},
+						{
+							Name:        "Group",
+							Type:        "string",
+							Description: "The group of the resource that is being migrated.",
+							JSONPath:    ".spec.resource.group",
+							Priority:    1,
+						},
+						{
+							Name:        "Status",
+							Type:        "string",
+							Description: "The true conditions of the migration.",
+							JSONPath:    `.status.conditions[?(@.status=="True")].type`,
+						},
+						{
+							Name:        "Progress",
+							Type:        "integer",
+							Description: "The number of objects rewritten by the migration.",
+							JSONPath:    ".status.objectsMigrated",
+						},
+						{
+							Name:        "Priority",
+							Type:        "integer",
+							Description: "The priority of the migration over the other pending migrations.",
+							JSONPath:    ".spec.priority",
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+						{
+							Name:        "Duration",
+							Type:        "string",
+							Description: "How long the migration ran.",
+							JSONPath:    ".status.duration",
+						},
+					},
 					Schema: &v1.CustomResourceValidation{
 						OpenAPIV3Schema: &v1.JSONSchemaProps{
 							Description: "StorageVersionMigration represents a migration of stored data to the latest storage version.",
@@ -113,12 +203,39 @@ func migrationCRD() *v1.CustomResourceDefinition {
 									},
 									Properties: map[string]v1.JSONSchemaProps{
 										"continueToken": {
-											Description: "The token used in the list options to get the next chunk of objects to migrate. When the .status.conditions indicates the migration is \"Running\", users can use this token to check the progress of the migration.",
+											Description: "The token used in the list options to get the next chunk of objects to migrate. Deprecated: the migrator saves its progress in .status.continueToken, and only reads this field to resume the migrations started by older migrators.",
 											Type:        "string",
 										},
+										"priority": {
+											Description: "The priority of the migration over the other pending migrations. The migrator runs the pending migrations by decreasing priority, then by creation time. The priority of a pending migration grows as it waits, so that migrations of low priority are eventually run. Defaults to 0.",
+											Type:        "integer",
+											Format:      "int32",
+										},
 										"resource": {
 											Description: "The resource that is being migrated. The migrator sends requests to the endpoint serving the resource. Immutable.",
 											Type:        "object",
+											XValidations: v1.ValidationRules{
+												{
+													Rule:    "self == oldSelf",
+													Message: "spec.resource is immutable",
+												},
+												{
+													Rule:    "has(self.resource) && self.resource != ''",
+													Message: "spec.resource.resource must be set",
+												},
+												{
+													Rule:    "has(self.version) && self.version != ''",
+													Message: "spec.resource.version must be set",
+												},
+												{
+													Rule:    "!has(self.group) || self.group == self.group.lowerAscii()",
+													Message: "spec.resource.group must be lowercase",
+												},
+												{
+													Rule:    "!has(self.resource) || self.resource == self.resource.lowerAscii()",
+													Message: "spec.resource.resource must be lowercase",
+												},
+											},
 											Properties: map[string]v1.JSONSchemaProps{
 												"group": {
 													Description: "The name of the group.",
@@ -134,6 +251,11 @@ func migrationCRD() *v1.CustomResourceDefinition {
 												},
 											},
 										},
+										"ttlSecondsAfterFinished": {
+											Description: "ttlSecondsAfterFinished limits the lifetime of a migration that has finished execution (either Succeeded or Failed). If this field is set, ttlSecondsAfterFinished after the migration finishes, it is eligible to be automatically deleted by the trigger controller. If this field is unset, the migration won't be automatically deleted. If this field is set to zero, the migration becomes eligible to be deleted immediately after it finishes.",
+											Type:        "integer",
+											Format:      "int32",
+										},
 									},
 								},
 								"status": {
@@ -177,6 +299,24 @@ func migrationCRD() *v1.CustomResourceDefinition {
 												},
 											},
 										},
+										"continueToken": {
+											Description: "The token used in the list options to get the next chunk of objects to migrate, saved by the migrator after every migrated chunk. When the .status.conditions indicates the migration is \"Running\", users can use this token to check the progress of the migration.",
+											Type:        "string",
+										},
+										"duration": {
+											Description: "How long the migration ran, from its start time to its completion. Set when the migration succeeds or fails.",
+											Type:        "string",
+										},
+										"objectsMigrated": {
+											Description: "The number of objects rewritten by the migration, saved by the migrator with the continue token.",
+											Type:        "integer",
+											Format:      "int64",
+										},
+										"startTime": {
+											Description: "The time the migrator started running the migration.",
+											Type:        "string",
+											Format:      "date-time",
+										},
 									},
 								},
 							},
@@ -188,74 +328,941 @@ func migrationCRD() *v1.CustomResourceDefinition {
 	}
 }
 
-func migrationForResource(resource schema.GroupVersionResource) *migrationv1alpha1.StorageVersionMigration {
-	var name string
-	if len(resource.Group) != 0 {
-		name = fmt.Sprintf("%s.%s.%s-", resource.Group, resource.Version, resource.Resource)
-	} else {
-		name = fmt.Sprintf("%s.%s-", resource.Version, resource.Resource)
-	}
-	return &migrationv1alpha1.StorageVersionMigration{
+func storageStateCRD() *v1.CustomResourceDefinition {
+	return &v1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: name,
+			Name: "storagestates.migration.k8s.io",
+			Annotations: map[string]string{
+				"api-approved.kubernetes.io": "https://github.com/kubernetes/enhancements/pull/747",
+				crdSchemaVersionAnnotation:   strconv.Itoa(storageStateCRDSchemaVersion),
+			},
 		},
-		Spec: migrationv1alpha1.StorageVersionMigrationSpec{
-			Resource: migrationv1alpha1.GroupVersionResource{
-				Group:    resource.Group,
-				Version:  resource.Version,
-				Resource: resource.Resource,
+		Spec: v1.CustomResourceDefinitionSpec{
+			Group: "migration.k8s.io",
+			Names: v1.CustomResourceDefinitionNames{
+				Plural:     pluralStorageStateCRDName,
+				Singular:   singularStorageStateCRDName,
+				ShortNames: []string{"ss"},
+				Kind:       storageStateKind,
+				ListKind:   storageStateListKind,
+				Categories: []string{migrationCategory},
+			},
+			Scope: v1.ClusterScoped,
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1alpha1",
+					Served:  true,
+					Storage: true,
+					Subresources: &v1.CustomResourceSubresources{
+						Status: &v1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []v1.CustomResourceColumnDefinition{
+						{
+							Name:        "Migrated",
+							Type:        "string",
+							Description: "Whether all the objects of the resource are encoded in the current storage version.",
+							JSONPath:    `.status.conditions[?(@.type=="Migrated")].status`,
+						},
+						{
+							Name:        "In Progress",
+							Type:        "string",
+							Description: "Whether a migration of the resource is pending or running.",
+							JSONPath:    `.status.conditions[?(@.type=="MigrationInProgress")].status`,
+						},
+						{
+							Name:        "Last Migration",
+							Type:        "string",
+							Description: "The last migration of the resource that succeeded.",
+							JSONPath:    ".status.lastSucceededMigration",
+						},
+						{
+							Name:        "Failed Attempts",
+							Type:        "integer",
+							Description: "The number of consecutive failed migrations of the resource.",
+							JSONPath:    ".status.failedMigrationAttempts",
+							Priority:    1,
+						},
+						{
+							Name:        "Current Hash",
+							Type:        "string",
+							Description: "The hash of the current storage version of the resource.",
+							JSONPath:    ".status.currentStorageVersionHash",
+							Priority:    1,
+						},
+						{
+							Name:        "Heartbeat",
+							Type:        "date",
+							Description: "The last time the trigger observed the storage versions of the resource.",
+							JSONPath:    ".status.lastHeartbeatTime",
+						},
+					},
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Description: "The state of the storage of a specific resource.",
+							Type:        "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"name": {
+											Description: "name must be \"<.spec.resource.resouce>.<.spec.resource.group>\".",
+											Type:        "string",
+										},
+									},
+								},
+								"spec": {
+									Description: "Specification of the storage state.",
+									Type:        "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"resource": {
+											Description: "The resource this storageState is about.",
+											Type:        "object",
+											Properties: map[string]v1.JSONSchemaProps{
+												"group": {
+													Description: "The name of the group.",
+													Type:        "string",
+												},
+												"resource": {
+													Description: "The name of the resource.",
+													Type:        "string",
+												},
+											},
+										},
+									},
+								},
+								"status": {
+									Description: "Status of the storage state.",
+									Type:        "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"conditions": {
+											Description: "The latest available observations of the storage state.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "Describes the state of the storage at a certain point.",
+													Type:        "object",
+													Required: []string{
+														"status",
+														"type",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"lastUpdateTime": {
+															Description: "The last time this condition was updated.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"message": {
+															Description: "A human readable message indicating details about the transition.",
+															Type:        "string",
+														},
+														"reason": {
+															Description: "The reason for the condition's last transition.",
+															Type:        "string",
+														},
+														"status": {
+															Description: "Status of the condition, one of True, False, Unknown.",
+															Type:        "string",
+														},
+														"type": {
+															Description: "Type of the condition.",
+															Type:        "string",
+														},
+													},
+												},
+											},
+										},
+										"currentStorageVersionHash": {
+											Description: "The hash value of the current storage version, as shown in the discovery document served by the API server. Storage Version is the version to which objects are converted to before persisted.",
+											Type:        "string",
+										},
+										"failedMigrationAttempts": {
+											Description: "The number of consecutive failed migrations of spec.resource since the storage version hash last changed, or since the last successful migration.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"lastFailedMigration": {
+											Description: "The name of the last failed migration counted in failedMigrationAttempts.",
+											Type:        "string",
+										},
+										"lastHeartbeatTime": {
+											Description: "LastHeartbeatTime is the last time the storage migration triggering controller checks the storage version hash of this resource in the discovery document and updates this field.",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"lastMigrationFailureMessage": {
+											Description: "The message of the Failed condition of the last migration of spec.resource that failed.",
+											Type:        "string",
+										},
+										"lastSucceededMigration": {
+											Description: "The name of the last migration of spec.resource that succeeded.",
+											Type:        "string",
+										},
+										"nextMigrationRetryTime": {
+											Description: "The earliest time the storage migration triggering controller launches a new migration of spec.resource after a failed migration.",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"persistedStorageVersionHashes": {
+											Description: "The hash values of storage versions that persisted instances of spec.resource might still be encoded in. \"Unknown\" is a valid value in the list, and is the default value. It is not safe to upgrade or downgrade to an apiserver binary that does not support all versions listed in this field, or if \"Unknown\" is listed. Once the storage version migration for this resource has completed, the value of this field is refined to only contain the currentStorageVersionHash. Once the apiserver has changed the storage version, the new storage version is appended to the list.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
+										},
+										"storageVersionHashHistory": {
+											Description: "The history of the values of persistedStorageVersionHashes, recording when each hash was first observed and when it was removed. The oldest removed hashes are dropped from the history when it grows too long.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "Records when a storage version hash was persisted.",
+													Type:        "object",
+													Required: []string{
+														"firstObservedTime",
+														"hash",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"firstObservedTime": {
+															Description: "The time the hash was added to persistedStorageVersionHashes.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"hash": {
+															Description: "The storage version hash, or \"Unknown\".",
+															Type:        "string",
+														},
+														"removedTime": {
+															Description: "The time the hash was removed from persistedStorageVersionHashes, unset while the hash is persisted.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
 			},
 		},
 	}
 }
 
-func (init *initializer) initializeCRD(ctx context.Context) error {
-	crdName := fmt.Sprintf("%s.%s", pluralCRDName, migrationv1alpha1.GroupName)
-	// check if crd already exists
-	_, err := init.crdClient.Get(ctx, crdName, metav1.GetOptions{})
-	if err != nil && !errors.IsNotFound(err) {
-		return err
-	}
-	if err != nil && errors.IsNotFound(err) {
-		_, err := init.crdClient.Create(ctx, migrationCRD(), metav1.CreateOptions{})
-		return err
-	}
-
-	// delete the crd, and wait for it's deletion
-	if err := init.crdClient.Delete(ctx, crdName, metav1.DeleteOptions{}); err != nil {
-		return err
-	}
-	err = wait.PollImmediate(500*time.Millisecond, 30*time.Second, func() (bool, error) {
-		_, err := init.crdClient.Get(ctx, crdName, metav1.GetOptions{})
-		if err == nil {
-			return false, nil
-		}
-		if errors.IsNotFound(err) {
-			return true, nil
-		}
-		return false, err
-	})
-	if err != nil {
-		return err
-	}
-	_, err = init.crdClient.Create(ctx, migrationCRD(), metav1.CreateOptions{})
-	return err
-}
-
-func (init *initializer) Initialize(ctx context.Context) error {
-	// TODO: remove deployment code.
-	if err := init.initializeCRD(ctx); err != nil {
-		return err
-	}
-
-	// run discovery
-	resources, err := init.discovery.FindMigratableResources(ctx)
-	if err != nil {
-		return err
-	}
-
-	for _, r := range resources {
-		if _, err := init.migrationClient.Create(ctx, migrationForResource(r), metav1.CreateOptions{}); err != nil {
+func migrationPolicyCRD() *v1.CustomResourceDefinition {
+	minimumOne, minimumZero := 1.0, 0.0
+	minLengthOne := int64(1)
+	minItemsOne := int64(1)
+	return &v1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "migrationpolicies.migration.k8s.io",
+			Annotations: map[string]string{
+				"api-approved.kubernetes.io": "https://github.com/kubernetes/enhancements/pull/747",
+				crdSchemaVersionAnnotation:   strconv.Itoa(migrationPolicyCRDSchemaVersion),
+			},
+		},
+		Spec: v1.CustomResourceDefinitionSpec{
+			Group: "migration.k8s.io",
+			Names: v1.CustomResourceDefinitionNames{
+				Plural:   pluralMigrationPolicyCRDName,
+				Singular: singularMigrationPolicyCRDName,
+				Kind:     migrationPolicyKind,
+				ListKind: migrationPolicyListKind,
+			},
+			Scope: v1.ClusterScoped,
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1alpha1",
+					Served:  true,
+					Storage: true,
+					Subresources: &v1.CustomResourceSubresources{
+						Status: &v1.CustomResourceSubresourceStatus{},
+					},
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Description: "MigrationPolicy sets how the resources it matches are migrated. When several policies match a resource, every setting is taken from the policy with the highest priority that sets it, ties being broken by the alphabetical order of the names of the policies.",
+							Type:        "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "Specification of the policy.",
+									Type:        "object",
+									Required: []string{
+										"resources",
+									},
+									Properties: map[string]v1.JSONSchemaProps{
+										"action": {
+											Description: "Whether the matched resources are migrated, one of Migrate or Skip. If unset, the configuration of the trigger decides.",
+											Type:        "string",
+											Enum: []v1.JSON{
+												{Raw: []byte(`"Migrate"`)},
+												{Raw: []byte(`"Skip"`)},
+											},
+										},
+										"chunkSize": {
+											Description: "The number of objects the migrator lists per request. If unset, the configuration of the migrator decides.",
+											Type:        "integer",
+											Format:      "int64",
+											Minimum:     &minimumOne,
+										},
+										"concurrency": {
+											Description: "The number of objects the migrator rewrites in parallel. If unset, the configuration of the migrator decides.",
+											Type:        "integer",
+											Format:      "int32",
+											Minimum:     &minimumOne,
+										},
+										"maintenanceWindows": {
+											Description: "The time windows in which the migrator rewrites the matched resources. A migration running when the windows close is suspended and resumed when one opens. If unset, the configuration of the migrator decides.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "A time window, opened on a cron schedule for a duration.",
+													Type:        "object",
+													Required: []string{
+														"duration",
+														"schedule",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"duration": {
+															Description: "How long the window stays open.",
+															Type:        "string",
+														},
+														"schedule": {
+															Description: "The cron schedule opening the window, made of five fields: minute, hour, day of month, month and day of week, e.g. \"0 1 * * 1-5\" for 01:00 on weekdays.",
+															Type:        "string",
+															MinLength:   &minLengthOne,
+														},
+														"timeZone": {
+															Description: "The time zone of the schedule, as a name of the IANA time zone database, e.g. \"Europe/Paris\". Defaults to UTC.",
+															Type:        "string",
+														},
+													},
+												},
+											},
+										},
+										"migrationPriority": {
+											Description: "The priority of the migrations the trigger creates for the matched resources. If unset, 0.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"priority": {
+											Description: "The priority of the policy over the other policies matching the same resources. Higher priorities win.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"resources": {
+											Description: "The resources the policy applies to. A resource is matched if it matches any of the patterns.",
+											Type:        "array",
+											MinItems:    &minItemsOne,
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "Matches resources by group and resource name.",
+													Type:        "object",
+													Required: []string{
+														"resource",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"group": {
+															Description: "The name of the group, empty for the core group. \"*\" matches every group, and \"*.<suffix>\" matches the groups ending in \".<suffix>\".",
+															Type:        "string",
+														},
+														"resource": {
+															Description: "The name of the resource. \"*\" matches every resource.",
+															Type:        "string",
+														},
+													},
+												},
+											},
+										},
+										"retry": {
+											Description: "How the trigger retries failed migrations. The unset fields are taken from the configuration of the trigger.",
+											Type:        "object",
+											Properties: map[string]v1.JSONSchemaProps{
+												"initialBackoff": {
+													Description: "The delay before retrying after the first failed migration. The delay doubles after every following failure.",
+													Type:        "string",
+												},
+												"maxAttempts": {
+													Description: "The number of consecutive failed migrations after which the trigger gives up migrating a resource. Zero means the trigger never gives up.",
+													Type:        "integer",
+													Format:      "int32",
+													Minimum:     &minimumZero,
+												},
+												"maxBackoff": {
+													Description: "The maximum delay between retries.",
+													Type:        "string",
+												},
+											},
+										},
+										"strategy": {
+											Description: "How the migrator rewrites the objects, one of Direct or DryRunFirst. If unset, Direct.",
+											Type:        "string",
+											Enum: []v1.JSON{
+												{Raw: []byte(`"Direct"`)},
+												{Raw: []byte(`"DryRunFirst"`)},
+											},
+										},
+									},
+								},
+								"status": {
+									Description: "Status of the policy.",
+									Type:        "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"conditions": {
+											Description: "The latest available observations of the policy.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "Describes the state of a migration policy at a certain point.",
+													Type:        "object",
+													Required: []string{
+														"status",
+														"type",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"lastUpdateTime": {
+															Description: "The last time this condition was updated.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"message": {
+															Description: "A human readable message indicating details about the transition.",
+															Type:        "string",
+														},
+														"reason": {
+															Description: "The reason for the condition's last transition.",
+															Type:        "string",
+														},
+														"status": {
+															Description: "Status of the condition, one of True, False, Unknown.",
+															Type:        "string",
+														},
+														"type": {
+															Description: "Type of the condition.",
+															Type:        "string",
+														},
+													},
+												},
+											},
+										},
+										"matchedResources": {
+											Description: "The number of resources served by the apiserver that the policy matches.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"observedGeneration": {
+											Description: "The generation of the policy observed by the trigger.",
+											Type:        "integer",
+											Format:      "int64",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func migrationPlanCRD() *v1.CustomResourceDefinition {
+	minLengthOne := int64(1)
+	minItemsOne := int64(1)
+	return &v1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "migrationplans.migration.k8s.io",
+			Annotations: map[string]string{
+				"api-approved.kubernetes.io": "https://github.com/kubernetes/enhancements/pull/747",
+				crdSchemaVersionAnnotation:   strconv.Itoa(migrationPlanCRDSchemaVersion),
+			},
+		},
+		Spec: v1.CustomResourceDefinitionSpec{
+			Group: "migration.k8s.io",
+			Names: v1.CustomResourceDefinitionNames{
+				Plural:   pluralMigrationPlanCRDName,
+				Singular: singularMigrationPlanCRDName,
+				Kind:     migrationPlanKind,
+				ListKind: migrationPlanListKind,
+			},
+			Scope: v1.ClusterScoped,
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1alpha1",
+					Served:  true,
+					Storage: true,
+					Subresources: &v1.CustomResourceSubresources{
+						Status: &v1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []v1.CustomResourceColumnDefinition{
+						{
+							Name:        "Succeeded",
+							Type:        "string",
+							Description: "Whether all the steps of the plan have succeeded.",
+							JSONPath:    `.status.conditions[?(@.type=="Succeeded")].status`,
+						},
+						{
+							Name:        "Progress",
+							Type:        "string",
+							Description: "How many steps of the plan have succeeded.",
+							JSONPath:    `.status.conditions[?(@.type=="Succeeded")].message`,
+						},
+						{
+							Name:     "Age",
+							Type:     "date",
+							JSONPath: ".metadata.creationTimestamp",
+						},
+					},
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Description: "MigrationPlan migrates a group of resources in the order of the dependencies between them. The plan controller creates the storageVersionMigration of a step once the steps it depends on have succeeded.",
+							Type:        "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"spec": {
+									Description: "Specification of the plan.",
+									Type:        "object",
+									Required: []string{
+										"steps",
+									},
+									Properties: map[string]v1.JSONSchemaProps{
+										"priority": {
+											Description: "The priority of the migrations created for the steps.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"steps": {
+											Description: "The steps of the plan.",
+											Type:        "array",
+											MinItems:    &minItemsOne,
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "A step of a migration plan, migrating a resource.",
+													Type:        "object",
+													Required: []string{
+														"name",
+														"resource",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"dependsOn": {
+															Description: "The names of the steps that must succeed before the step starts.",
+															Type:        "array",
+															Items: &v1.JSONSchemaPropsOrArray{
+																Schema: &v1.JSONSchemaProps{
+																	Type: "string",
+																},
+															},
+														},
+														"name": {
+															Description: "The name of the step, unique in the plan.",
+															Type:        "string",
+															MinLength:   &minLengthOne,
+														},
+														"resource": {
+															Description: "The resource migrated by the step.",
+															Type:        "object",
+															Properties: map[string]v1.JSONSchemaProps{
+																"group": {
+																	Description: "The name of the group.",
+																	Type:        "string",
+																},
+																"resource": {
+																	Description: "The name of the resource.",
+																	Type:        "string",
+																},
+																"version": {
+																	Description: "The name of the version.",
+																	Type:        "string",
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+								"status": {
+									Description: "Status of the plan.",
+									Type:        "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"conditions": {
+											Description: "The latest available observations of the plan.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "Describes the state of a migration plan at a certain point.",
+													Type:        "object",
+													Required: []string{
+														"status",
+														"type",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"lastUpdateTime": {
+															Description: "The last time this condition was updated.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"message": {
+															Description: "A human readable message indicating details about the transition.",
+															Type:        "string",
+														},
+														"reason": {
+															Description: "The reason for the condition's last transition.",
+															Type:        "string",
+														},
+														"status": {
+															Description: "Status of the condition, one of True, False, Unknown.",
+															Type:        "string",
+														},
+														"type": {
+															Description: "Type of the condition.",
+															Type:        "string",
+														},
+													},
+												},
+											},
+										},
+										"observedGeneration": {
+											Description: "The generation of the plan observed by the plan controller.",
+											Type:        "integer",
+											Format:      "int64",
+										},
+										"steps": {
+											Description: "The status of the steps, in the order of the spec.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "Status of a step of a migration plan.",
+													Type:        "object",
+													Required: []string{
+														"name",
+														"phase",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"message": {
+															Description: "A human readable message explaining the phase.",
+															Type:        "string",
+														},
+														"migration": {
+															Description: "The name of the storageVersionMigration of the step.",
+															Type:        "string",
+														},
+														"name": {
+															Description: "The name of the step.",
+															Type:        "string",
+														},
+														"phase": {
+															Description: "The phase of the step, one of Blocked, Pending, Running, Succeeded or Failed.",
+															Type:        "string",
+														},
+													},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func migrationSummaryCRD() *v1.CustomResourceDefinition {
+	return &v1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "migrationsummaries.migration.k8s.io",
+			Annotations: map[string]string{
+				"api-approved.kubernetes.io": "https://github.com/kubernetes/enhancements/pull/747",
+				crdSchemaVersionAnnotation:   strconv.Itoa(migrationSummaryCRDSchemaVersion),
+			},
+		},
+		Spec: v1.CustomResourceDefinitionSpec{
+			Group: "migration.k8s.io",
+			Names: v1.CustomResourceDefinitionNames{
+				Plural:   pluralMigrationSummaryCRDName,
+				Singular: singularMigrationSummaryCRDName,
+				Kind:     migrationSummaryKind,
+				ListKind: migrationSummaryListKind,
+			},
+			Scope: v1.ClusterScoped,
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{
+					Name:    "v1alpha1",
+					Served:  true,
+					Storage: true,
+					Subresources: &v1.CustomResourceSubresources{
+						Status: &v1.CustomResourceSubresourceStatus{},
+					},
+					AdditionalPrinterColumns: []v1.CustomResourceColumnDefinition{
+						{
+							Name:        "Migrated",
+							Type:        "string",
+							Description: "Whether all the resources are migrated.",
+							JSONPath:    `.status.conditions[?(@.type=="AllResourcesMigrated")].status`,
+						},
+						{
+							Name:        "Resources",
+							Type:        "integer",
+							Description: "The number of resources with a storageState.",
+							JSONPath:    ".status.resources",
+						},
+						{
+							Name:        "Failing",
+							Type:        "integer",
+							Description: "The number of resources whose last migration failed.",
+							JSONPath:    ".status.failingResources",
+						},
+						{
+							Name:        "Updated",
+							Type:        "date",
+							Description: "The last time the summary was updated.",
+							JSONPath:    ".status.lastUpdateTime",
+						},
+					},
+					Schema: &v1.CustomResourceValidation{
+						OpenAPIV3Schema: &v1.JSONSchemaProps{
+							Description: "MigrationSummary summarizes the storage states of all the resources of the cluster, e.g. to tell whether the apiserver can be upgraded. The storage migration triggering controller maintains a single summary, named \"cluster\".",
+							Type:        "object",
+							Properties: map[string]v1.JSONSchemaProps{
+								"apiVersion": {
+									Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+									Type:        "string",
+								},
+								"kind": {
+									Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+									Type:        "string",
+								},
+								"metadata": {
+									Type: "object",
+								},
+								"status": {
+									Description: "Status of the summary.",
+									Type:        "object",
+									Properties: map[string]v1.JSONSchemaProps{
+										"blockingResources": {
+											Description: "The resources that are not migrated, sorted by group and resource. The list is truncated if it is too long.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "A resource that is not migrated.",
+													Type:        "object",
+													Required: []string{
+														"reason",
+														"resource",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"message": {
+															Description: "A human readable message with the details.",
+															Type:        "string",
+														},
+														"reason": {
+															Description: "Why the resource is not migrated, one of MigrationInProgress, MigrationFailing or MigrationRequired.",
+															Type:        "string",
+														},
+														"resource": {
+															Description: "The resource.",
+															Type:        "object",
+															Properties: map[string]v1.JSONSchemaProps{
+																"group": {
+																	Description: "The name of the group.",
+																	Type:        "string",
+																},
+																"resource": {
+																	Description: "The name of the resource.",
+																	Type:        "string",
+																},
+															},
+														},
+													},
+												},
+											},
+										},
+										"conditions": {
+											Description: "The latest available observations of the summary.",
+											Type:        "array",
+											Items: &v1.JSONSchemaPropsOrArray{
+												Schema: &v1.JSONSchemaProps{
+													Description: "Describes the state of the migration summary at a certain point.",
+													Type:        "object",
+													Required: []string{
+														"status",
+														"type",
+													},
+													Properties: map[string]v1.JSONSchemaProps{
+														"lastUpdateTime": {
+															Description: "The last time this condition was updated.",
+															Type:        "string",
+															Format:      "date-time",
+														},
+														"message": {
+															Description: "A human readable message indicating details about the transition.",
+															Type:        "string",
+														},
+														"reason": {
+															Description: "The reason for the condition's last transition.",
+															Type:        "string",
+														},
+														"status": {
+															Description: "Status of the condition, one of True, False, Unknown.",
+															Type:        "string",
+														},
+														"type": {
+															Description: "Type of the condition.",
+															Type:        "string",
+														},
+													},
+												},
+											},
+										},
+										"excludedResources": {
+											Description: "The number of resources with a storageState that the trigger does not migrate: the resources skipped by a migration policy or excluded by the resource rules of the trigger, and the resources migrated by an active migration plan.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"failingResources": {
+											Description: "The number of resources that are not migrated, and whose last migration failed.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"inProgressResources": {
+											Description: "The number of resources with a pending or running migration.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"lastUpdateTime": {
+											Description: "The last time the summary was updated.",
+											Type:        "string",
+											Format:      "date-time",
+										},
+										"migratedResources": {
+											Description: "The number of resources whose objects are all encoded in the current storage version, and that have no pending or running migration.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"resources": {
+											Description: "The number of resources with a storageState that the trigger migrates. The excluded resources are not counted.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+										"staleResources": {
+											Description: "The number of resources whose storageState has not been refreshed by the recent discoveries.",
+											Type:        "integer",
+											Format:      "int32",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func migrationForResource(r MigratableResource) *migrationv1alpha1.StorageVersionMigration {
+	resource := r.GroupVersionResource
+	var name string
+	if len(resource.Group) != 0 {
+		name = fmt.Sprintf("%s.%s.%s-", resource.Group, resource.Version, resource.Resource)
+	} else {
+		name = fmt.Sprintf("%s.%s-", resource.Version, resource.Resource)
+	}
+	m := &migrationv1alpha1.StorageVersionMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: name,
+			Annotations: map[string]string{
+				migrationv1alpha1.MigrationReasonAnnotation:    migrationv1alpha1.MigrationReasonInitialization,
+				migrationv1alpha1.MigrationMessageAnnotation:   "the resource is migrated when the storage version migrator is installed",
+				migrationv1alpha1.MigrationCreatedByAnnotation: createdBy,
+			},
+		},
+		Spec: migrationv1alpha1.StorageVersionMigrationSpec{
+			Resource: migrationv1alpha1.GroupVersionResource{
+				Group:    resource.Group,
+				Version:  resource.Version,
+				Resource: resource.Resource,
+			},
+		},
+	}
+	if r.StorageVersionHash != "" {
+		m.Annotations[migrationv1alpha1.MigrationStorageVersionHashAnnotation] = r.StorageVersionHash
+	}
+	return m
+}
+
+func (init *initializer) Initialize(ctx context.Context) error {
+	// TODO: remove deployment code.
+	if !init.options.DryRun {
+		if err := init.initializeCRDs(ctx); err != nil {
+			return err
+		}
+	}
+
+	// run discovery
+	resources, err := init.discovery.FindMigratableResources(ctx)
+	if err != nil {
+		return err
+	}
+	storageStates, err := init.findNewStorageStates(ctx)
+	if err != nil {
+		return err
+	}
+	plan, err := init.plan(ctx, resources, storageStates)
+	if err != nil {
+		return err
+	}
+	init.printPlan(plan, storageStates)
+	if init.options.DryRun {
+		return nil
+	}
+
+	// The storageStates are created first, so that the trigger finds the
+	// migrations created below.
+	if err := init.createStorageStates(ctx, storageStates); err != nil {
+		return err
+	}
+
+	for _, item := range plan {
+		if item.skipReason != "" {
+			continue
+		}
+		if _, err := init.migrationClient.Create(ctx, migrationForResource(item.resource), metav1.CreateOptions{}); err != nil {
 			return err
 		}
 	}
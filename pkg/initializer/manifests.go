@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"encoding/json"
+	"fmt"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// manifestHeader starts the generated manifests of the CRDs.
+const manifestHeader = "# Code generated by hack/update-crd-manifests.sh from the CRDs of pkg/initializer. DO NOT EDIT.\n"
+
+// crdManifestFiles are the files of the manifests directory the CRDs are
+// written to, by CRD name.
+var crdManifestFiles = map[string]string{
+	"storageversionmigrations.migration.k8s.io": "storage_migration_crd.yaml",
+	"storagestates.migration.k8s.io":            "storage_state_crd.yaml",
+	"migrationpolicies.migration.k8s.io":        "migration_policy_crd.yaml",
+	"migrationplans.migration.k8s.io":           "migration_plan_crd.yaml",
+	"migrationsummaries.migration.k8s.io":       "migration_summary_crd.yaml",
+}
+
+// CRDManifests returns the YAML manifests of the CRDs installed by the
+// initializer, by their file name in the manifests directory. The manifests
+// are generated from the CRDs, so that they never differ.
+func CRDManifests() (map[string][]byte, error) {
+	manifests := map[string][]byte{}
+	for _, crd := range crds() {
+		file, ok := crdManifestFiles[crd.Name]
+		if !ok {
+			return nil, fmt.Errorf("no manifest file for CRD %s", crd.Name)
+		}
+		manifest, err := crdManifest(crd)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode CRD %s: %v", crd.Name, err)
+		}
+		manifests[file] = manifest
+	}
+	return manifests, nil
+}
+
+// crdManifest encodes the CRD in YAML, without the status and the fields
+// set by the apiserver, and with preserveUnknownFields disabled.
+func crdManifest(crd *v1.CustomResourceDefinition) ([]byte, error) {
+	crd = crd.DeepCopy()
+	crd.APIVersion = v1.SchemeGroupVersion.String()
+	crd.Kind = "CustomResourceDefinition"
+	data, err := json.Marshal(crd)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	delete(object, "status")
+	if metadata, ok := object["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+	// The field is omitted when false, but applying the manifest must
+	// reset it on the CRDs created by apiextensions.k8s.io/v1beta1.
+	if spec, ok := object["spec"].(map[string]interface{}); ok {
+		spec["preserveUnknownFields"] = false
+	}
+	manifest, err := yaml.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(manifestHeader), manifest...), nil
+}
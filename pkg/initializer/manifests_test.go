@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// TestCRDManifestsInSync fails when the manifests of the CRDs differ from
+// the CRDs installed by the initializer.
+func TestCRDManifestsInSync(t *testing.T) {
+	manifests, err := CRDManifests()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(manifests) != len(crds()) {
+		t.Fatalf("expected a manifest per CRD, got %d manifests", len(manifests))
+	}
+	for file, expected := range manifests {
+		actual, err := os.ReadFile(filepath.Join("..", "..", "manifests", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("manifests/%s differs from the CRD of the initializer, run hack/update-crd-manifests.sh", file)
+		}
+	}
+}
+
+func TestCRDManifestsDecode(t *testing.T) {
+	manifests, err := CRDManifests()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, crd := range crds() {
+		var decoded v1.CustomResourceDefinition
+		if err := yaml.UnmarshalStrict(manifests[crdManifestFiles[crd.Name]], &decoded); err != nil {
+			t.Fatalf("%s: %v", crd.Name, err)
+		}
+		if !reflect.DeepEqual(decoded.Spec, crd.Spec) || !reflect.DeepEqual(decoded.Annotations, crd.Annotations) {
+			t.Errorf("the manifest of %s does not decode to the CRD", crd.Name)
+		}
+	}
+}
+
+func TestMigrationCRDPrinterColumns(t *testing.T) {
+	for _, crd := range []*v1.CustomResourceDefinition{migrationCRD(), storageStateCRD()} {
+		if len(crd.Spec.Names.ShortNames) == 0 || len(crd.Spec.Names.Categories) == 0 {
+			t.Errorf("expected %s to have short names and categories", crd.Name)
+		}
+		if len(crd.Spec.Versions[0].AdditionalPrinterColumns) == 0 {
+			t.Errorf("expected %s to have printer columns", crd.Name)
+		}
+	}
+	columns := map[string]bool{}
+	for _, c := range migrationCRD().Spec.Versions[0].AdditionalPrinterColumns {
+		columns[c.Name] = true
+	}
+	for _, name := range []string{"Resource", "Status", "Progress", "Age", "Duration"} {
+		if !columns[name] {
+			t.Errorf("expected the %s column on storageVersionMigrations", name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// Options configures the initializer.
+type Options struct {
+	// ResourceRules select the migrated resources.
+	controller.ResourceRules `json:",inline"`
+	// IncludeCustomResources makes the initializer also migrate the
+	// resources of the groups defined by CRDs.
+	IncludeCustomResources bool `json:"includeCustomResources,omitempty"`
+	// IncludeAggregatedResources makes the initializer also migrate the
+	// resources of the groups served by aggregated apiservers.
+	IncludeAggregatedResources bool `json:"includeAggregatedResources,omitempty"`
+	// AssumeMigrated makes the initializer create the storageStates of the
+	// resources as already migrated to their current storage version, so
+	// that nothing is migrated. It is only safe on clusters created at
+	// the current version, whose objects were all written in the current
+	// storage versions.
+	AssumeMigrated bool `json:"assumeMigrated,omitempty"`
+	// DryRun makes the initializer print its plan to Out without
+	// installing the CRDs, or creating storageStates or migrations.
+	DryRun bool `json:"dryRun,omitempty"`
+	// Out is where the plan is printed. If nil, the plan is only logged.
+	Out io.Writer `json:"-"`
+}
+
+// DefaultOptions returns the default Options of the initializer.
+func DefaultOptions() Options {
+	return Options{
+		// Events are short lived, migrating them is pointless.
+		ResourceRules: controller.ResourceRules{
+			ExcludeResources: []string{"events", "events.events.k8s.io"},
+		},
+	}
+}
+
+// LoadOptionsFile reads the Options from a YAML or JSON file. The fields
+// absent from the file keep their default values.
+func LoadOptionsFile(path string) (Options, error) {
+	options := DefaultOptions()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return options, err
+	}
+	if err := yaml.UnmarshalStrict(data, &options); err != nil {
+		return options, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return options, nil
+}
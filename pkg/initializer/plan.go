@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// planItem is a resource found by the discovery, and whether the
+// initializer migrates it.
+type planItem struct {
+	resource MigratableResource
+	// skipReason explains why the resource is not migrated. It is empty if
+	// the resource is migrated.
+	skipReason string
+}
+
+// plan decides which of the resources need a migration. Resources that
+// already have a pending, running or succeeded migration for their current
+// storage version hash are skipped, so that running the initializer again
+// does not create duplicate migrations. So are the resources whose
+// storageState shows they are migrated, including the newStorageStates about
+// to be created.
+func (init *initializer) plan(ctx context.Context, resources []MigratableResource, newStorageStates []*migrationv1alpha1.StorageState) ([]planItem, error) {
+	// The CRDs are not installed in dry-run mode, in which case there
+	// are no migrations or storageStates yet.
+	migrations, err := init.migrationClient.List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	storageStates, err := init.storageStateClient.List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	migrationsByResource := make(map[string][]*migrationv1alpha1.StorageVersionMigration)
+	if migrations != nil {
+		for i := range migrations.Items {
+			m := &migrations.Items[i]
+			name := controller.ResourceName(m.Spec.Resource.Group, m.Spec.Resource.Resource)
+			migrationsByResource[name] = append(migrationsByResource[name], m)
+		}
+	}
+	storageStatesByName := make(map[string]*migrationv1alpha1.StorageState)
+	if storageStates != nil {
+		for i := range storageStates.Items {
+			storageStatesByName[storageStates.Items[i].Name] = &storageStates.Items[i]
+		}
+	}
+	for _, ss := range newStorageStates {
+		storageStatesByName[ss.Name] = ss
+	}
+
+	var plan []planItem
+	for _, r := range resources {
+		name := controller.ResourceName(r.Group, r.Resource)
+		plan = append(plan, planItem{
+			resource:   r,
+			skipReason: skipReason(r, migrationsByResource[name], storageStatesByName[name]),
+		})
+	}
+	sort.Slice(plan, func(i, j int) bool {
+		return controller.ResourceName(plan[i].resource.Group, plan[i].resource.Resource) < controller.ResourceName(plan[j].resource.Group, plan[j].resource.Resource)
+	})
+	return plan, nil
+}
+
+// skipReason returns why the resource r does not need a migration, or an
+// empty string if it does. migrations are the existing migrations of r, and
+// ss is its storageState, or nil if there is none.
+func skipReason(r MigratableResource, migrations []*migrationv1alpha1.StorageVersionMigration, ss *migrationv1alpha1.StorageState) string {
+	if ss != nil && r.StorageVersionHash != "" && ss.Status.CurrentStorageVersionHash == r.StorageVersionHash &&
+		len(ss.Status.PersistedStorageVersionHashes) == 1 && ss.Status.PersistedStorageVersionHashes[0] == r.StorageVersionHash {
+		return fmt.Sprintf("storageState %s shows the resource is migrated to storage version hash %s", ss.Name, r.StorageVersionHash)
+	}
+	for _, m := range migrations {
+		if controller.HasCondition(m, migrationv1alpha1.MigrationFailed) || controller.HasCondition(m, migrationv1alpha1.MigrationSuperseded) {
+			continue
+		}
+		succeeded := controller.HasCondition(m, migrationv1alpha1.MigrationSucceeded)
+		hash, ok := m.Annotations[migrationv1alpha1.MigrationStorageVersionHashAnnotation]
+		switch {
+		case ok && hash != r.StorageVersionHash:
+			continue
+		case !ok && succeeded:
+			// The migration might have migrated the resource to
+			// a previous storage version.
+			continue
+		}
+		state := "pending"
+		switch {
+		case succeeded:
+			state = "succeeded"
+		case controller.HasCondition(m, migrationv1alpha1.MigrationRunning):
+			state = "running"
+		}
+		return fmt.Sprintf("migration %s is %s", m.Name, state)
+	}
+	return ""
+}
+
+// printPlan prints the storageStates to create and the plan to the Out of
+// the options, or logs them if Out is nil.
+func (init *initializer) printPlan(plan []planItem, newStorageStates []*migrationv1alpha1.StorageState) {
+	var lines []string
+	for _, ss := range newStorageStates {
+		lines = append(lines, fmt.Sprintf("create storageState %s, storage version hash %q, persisted storage version hashes %v", ss.Name, ss.Status.CurrentStorageVersionHash, ss.Status.PersistedStorageVersionHashes))
+	}
+	for _, item := range plan {
+		r := item.resource
+		var line string
+		if item.skipReason == "" {
+			line = fmt.Sprintf("migrate %s (%s), storage version hash %q", controller.ResourceName(r.Group, r.Resource), r.GroupVersion(), r.StorageVersionHash)
+		} else {
+			line = fmt.Sprintf("skip %s: %s", controller.ResourceName(r.Group, r.Resource), item.skipReason)
+		}
+		lines = append(lines, line)
+	}
+	for _, line := range lines {
+		if init.options.Out != nil {
+			fmt.Fprintln(init.options.Out, line)
+		} else {
+			klog.Info(line)
+		}
+	}
+}
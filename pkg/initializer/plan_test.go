@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsfake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	aggregatorfake "k8s.io/kube-aggregator/pkg/client/clientset_generated/clientset/fake"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	migrationfake "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+)
+
+func migrationWith(hash string, conditions ...migrationv1alpha1.MigrationConditionType) *migrationv1alpha1.StorageVersionMigration {
+	m := &migrationv1alpha1.StorageVersionMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "daemonsets-1", Annotations: map[string]string{}},
+		Spec: migrationv1alpha1.StorageVersionMigrationSpec{
+			Resource: migrationv1alpha1.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"},
+		},
+	}
+	if hash != "" {
+		m.Annotations[migrationv1alpha1.MigrationStorageVersionHashAnnotation] = hash
+	}
+	for _, c := range conditions {
+		m.Status.Conditions = append(m.Status.Conditions, migrationv1alpha1.MigrationCondition{Type: c, Status: corev1.ConditionTrue})
+	}
+	return m
+}
+
+func TestSkipReason(t *testing.T) {
+	daemonsets := MigratableResource{
+		GroupVersionResource: schema.GroupVersionResource{Group: "extensions", Version: "v1beta1", Resource: "daemonsets"},
+		StorageVersionHash:   "dshash",
+	}
+	for _, tc := range []struct {
+		name         string
+		migrations   []*migrationv1alpha1.StorageVersionMigration
+		storageState *migrationv1alpha1.StorageState
+		expectSkip   bool
+	}{
+		{
+			name: "no migration",
+		},
+		{
+			name:       "pending migration",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("dshash")},
+			expectSkip: true,
+		},
+		{
+			name:       "running migration",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("dshash", migrationv1alpha1.MigrationRunning)},
+			expectSkip: true,
+		},
+		{
+			name:       "succeeded migration",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("dshash", migrationv1alpha1.MigrationSucceeded)},
+			expectSkip: true,
+		},
+		{
+			name:       "failed migration",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("dshash", migrationv1alpha1.MigrationFailed)},
+		},
+		{
+			name:       "superseded migration",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("dshash", migrationv1alpha1.MigrationSuperseded)},
+		},
+		{
+			name:       "succeeded migration for another hash",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("oldhash", migrationv1alpha1.MigrationSucceeded)},
+		},
+		{
+			name:       "succeeded migration without hash",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("", migrationv1alpha1.MigrationSucceeded)},
+		},
+		{
+			name:       "pending migration without hash",
+			migrations: []*migrationv1alpha1.StorageVersionMigration{migrationWith("")},
+			expectSkip: true,
+		},
+		{
+			name: "migrated storageState",
+			storageState: &migrationv1alpha1.StorageState{
+				ObjectMeta: metav1.ObjectMeta{Name: "daemonsets.extensions"},
+				Status: migrationv1alpha1.StorageStateStatus{
+					CurrentStorageVersionHash:     "dshash",
+					PersistedStorageVersionHashes: []string{"dshash"},
+				},
+			},
+			expectSkip: true,
+		},
+		{
+			name: "storageState not migrated",
+			storageState: &migrationv1alpha1.StorageState{
+				ObjectMeta: metav1.ObjectMeta{Name: "daemonsets.extensions"},
+				Status: migrationv1alpha1.StorageStateStatus{
+					CurrentStorageVersionHash:     "dshash",
+					PersistedStorageVersionHashes: []string{"oldhash", "dshash"},
+				},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			reason := skipReason(daemonsets, tc.migrations, tc.storageState)
+			if skip := reason != ""; skip != tc.expectSkip {
+				t.Errorf("expected skip %v, got reason %q", tc.expectSkip, reason)
+			}
+		})
+	}
+}
+
+func newTestInitializer(t *testing.T, migration *migrationfake.Clientset, options Options) *initializer {
+	kubernetes := fake.NewSimpleClientset()
+	kubernetes.Fake.Resources = fakeAPIResourceLists(t)
+	crd := apiextensionsfake.NewSimpleClientset(fakeCRDs(t)...)
+	crd.PrependReactor("patch", "customresourcedefinitions", applyReactor(crd, true))
+	apiservice := aggregatorfake.NewSimpleClientset(fakeAPIServices(t)...)
+	init := NewInitializer(
+		kubernetes.Discovery(),
+		crd.ApiextensionsV1().CustomResourceDefinitions(),
+		apiservice.ApiregistrationV1().APIServices(),
+		kubernetes.CoreV1().Namespaces(),
+		migration.MigrationV1alpha1(),
+		options,
+	)
+	init.crdEstablishedTimeout = time.Second
+	return init
+}
+
+func createdMigrations(migration *migrationfake.Clientset) int {
+	count := 0
+	for _, a := range migration.Actions() {
+		if a.GetVerb() == "create" && a.GetResource().Resource == "storageversionmigrations" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestInitializeIsIdempotent(t *testing.T) {
+	migration := migrationfake.NewSimpleClientset()
+	init := newTestInitializer(t, migration, DefaultOptions())
+	if err := init.Initialize(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if n := createdMigrations(migration); n != 1 {
+		t.Fatalf("expected 1 migration to be created, got %d", n)
+	}
+	migration.ClearActions()
+	if err := init.Initialize(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if n := createdMigrations(migration); n != 0 {
+		t.Fatalf("expected no migration to be created, got %d", n)
+	}
+}
+
+func TestInitializeDryRun(t *testing.T) {
+	migration := migrationfake.NewSimpleClientset(migrationWith("dshash", migrationv1alpha1.MigrationFailed))
+	out := &bytes.Buffer{}
+	options := DefaultOptions()
+	options.DryRun = true
+	options.Out = out
+	init := newTestInitializer(t, migration, options)
+	if err := init.Initialize(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if n := createdMigrations(migration); n != 0 {
+		t.Fatalf("expected no migration to be created, got %d", n)
+	}
+	for _, a := range migration.Actions() {
+		if a.GetVerb() != "list" {
+			t.Errorf("unexpected action %v", a)
+		}
+	}
+	expected := "create storageState daemonsets.extensions, storage version hash \"dshash\", persisted storage version hashes [Unknown]\n" +
+		"migrate daemonsets.extensions (extensions/v1beta1), storage version hash \"dshash\"\n"
+	if e, a := expected, out.String(); e != a {
+		t.Errorf("expected plan %q, got %q", e, a)
+	}
+}
+
+func TestLoadOptionsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `includeGroups: [apps]
+includeCustomResources: true
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	options, err := LoadOptionsFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DefaultOptions()
+	expected.IncludeGroups = []string{"apps"}
+	expected.IncludeCustomResources = true
+	if !reflect.DeepEqual(expected, options) {
+		t.Errorf("expected %#v, got %#v", expected, options)
+	}
+
+	if err := os.WriteFile(path, []byte("unknownField: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOptionsFile(path); err == nil || !strings.Contains(err.Error(), "unknownField") {
+		t.Errorf("expected an error about the unknown field, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// findNewStorageStates returns the storageStates to create for the
+// resources in the discovery document that have a storage version hash but
+// no storageState, in the format the trigger uses. The persisted storage
+// version hashes are "Unknown", unless the options assume the resources are
+// migrated.
+func (init *initializer) findNewStorageStates(ctx context.Context) ([]*migrationv1alpha1.StorageState, error) {
+	groups, resourceLists, err := init.discovery.discoveryClient.ServerGroupsAndResources()
+	if err != nil {
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return nil, err
+		}
+		// The trigger handles the failed groups once they are
+		// discovered.
+		klog.Warningf("failed to discover some groups: %v", err)
+	}
+	// The trigger tracks the preferred version of each group.
+	preferred := sets.NewString()
+	for _, g := range groups {
+		preferred.Insert(g.PreferredVersion.GroupVersion)
+	}
+	existing := make(map[string]bool)
+	l, err := init.storageStateClient.List(ctx, metav1.ListOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if l != nil {
+		for _, ss := range l.Items {
+			existing[ss.Name] = true
+		}
+	}
+
+	now := metav1.Now()
+	var ret []*migrationv1alpha1.StorageState
+	for _, resourceList := range resourceLists {
+		if !preferred.Has(resourceList.GroupVersion) {
+			continue
+		}
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			klog.Errorf("cannot parse group version %s, ignored", resourceList.GroupVersion)
+			continue
+		}
+		for _, r := range resourceList.APIResources {
+			if strings.Contains(r.Name, "/") || r.StorageVersionHash == "" {
+				continue
+			}
+			resource := migrationv1alpha1.GroupVersionResource{Group: gv.Group, Version: gv.Version, Resource: r.Name}
+			if existing[controller.StorageStateName(resource)] {
+				continue
+			}
+			ss := controller.NewStorageState(resource)
+			ss.Status.CurrentStorageVersionHash = r.StorageVersionHash
+			ss.Status.PersistedStorageVersionHashes = []string{migrationv1alpha1.Unknown}
+			if init.options.AssumeMigrated {
+				ss.Status.PersistedStorageVersionHashes = []string{r.StorageVersionHash}
+			}
+			ss.Status.LastHeartbeatTime = now
+			ret = append(ret, ss)
+		}
+	}
+	return ret, nil
+}
+
+// createStorageStates creates the storageStates, together with their status.
+// The storageStates created by the trigger in the meantime are kept.
+func (init *initializer) createStorageStates(ctx context.Context, storageStates []*migrationv1alpha1.StorageState) error {
+	for _, ss := range storageStates {
+		created, err := init.storageStateClient.Create(ctx, ss, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create storageState %s: %v", ss.Name, err)
+		}
+		// The apiserver resets the status field for the POST request.
+		created.Status = ss.Status
+		if _, err := init.storageStateClient.UpdateStatus(ctx, created, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("failed to update the status of storageState %s: %v", ss.Name, err)
+		}
+	}
+	return nil
+}
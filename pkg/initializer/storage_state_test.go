@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package initializer
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	migrationfake "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+)
+
+func TestInitializeCreatesStorageStates(t *testing.T) {
+	for _, tc := range []struct {
+		name              string
+		assumeMigrated    bool
+		expectedPersisted []string
+		expectedCreated   int
+	}{
+		{
+			name:              "not migrated",
+			expectedPersisted: []string{migrationv1alpha1.Unknown},
+			expectedCreated:   1,
+		},
+		{
+			name:              "assume migrated",
+			assumeMigrated:    true,
+			expectedPersisted: []string{"dshash"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			migration := migrationfake.NewSimpleClientset()
+			options := DefaultOptions()
+			options.AssumeMigrated = tc.assumeMigrated
+			init := newTestInitializer(t, migration, options)
+			if err := init.Initialize(context.TODO()); err != nil {
+				t.Fatal(err)
+			}
+			ss, err := migration.MigrationV1alpha1().StorageStates().Get(context.TODO(), "daemonsets.extensions", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e, a := (migrationv1alpha1.GroupResource{Group: "extensions", Resource: "daemonsets"}), ss.Spec.Resource; e != a {
+				t.Errorf("expected resource %v, got %v", e, a)
+			}
+			if e, a := "dshash", ss.Status.CurrentStorageVersionHash; e != a {
+				t.Errorf("expected current storage version hash %q, got %q", e, a)
+			}
+			if e, a := tc.expectedPersisted, ss.Status.PersistedStorageVersionHashes; !reflect.DeepEqual(e, a) {
+				t.Errorf("expected persisted storage version hashes %v, got %v", e, a)
+			}
+			if ss.Status.LastHeartbeatTime.IsZero() {
+				t.Errorf("expected the heartbeat to be set")
+			}
+			if n := createdMigrations(migration); n != tc.expectedCreated {
+				t.Errorf("expected %d migrations to be created, got %d", tc.expectedCreated, n)
+			}
+		})
+	}
+}
+
+func TestInitializeKeepsExistingStorageStates(t *testing.T) {
+	existing := &migrationv1alpha1.StorageState{
+		ObjectMeta: metav1.ObjectMeta{Name: "daemonsets.extensions"},
+		Status: migrationv1alpha1.StorageStateStatus{
+			CurrentStorageVersionHash:     "dshash",
+			PersistedStorageVersionHashes: []string{"oldhash", "dshash"},
+		},
+	}
+	migration := migrationfake.NewSimpleClientset(existing)
+	options := DefaultOptions()
+	options.AssumeMigrated = true
+	init := newTestInitializer(t, migration, options)
+	if err := init.Initialize(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range migration.Actions() {
+		if a.GetResource().Resource == "storagestates" && a.GetVerb() != "list" {
+			t.Errorf("unexpected action %v", a)
+		}
+	}
+	if n := createdMigrations(migration); n != 1 {
+		t.Errorf("expected 1 migration to be created, got %d", n)
+	}
+}
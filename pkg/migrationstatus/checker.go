@@ -0,0 +1,173 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package migrationstatus answers whether resources are migrated to their
+// current storage version, from the storageStates maintained by the
+// trigger, e.g. for the controllers that must wait for a migration before
+// they remove an old version of their resources.
+//
+// A resource is migrated when all its persisted objects are encoded in its
+// current storage version, with the semantics of the trigger:
+//
+//	factory := informer.NewSharedInformerFactory(client, 0)
+//	checker := migrationstatus.NewChecker(factory.Migration().V1alpha1().StorageStates())
+//	factory.Start(ctx.Done())
+//	err := checker.WaitForMigrated(ctx, schema.GroupResource{Group: "example.com", Resource: "widgets"}, "")
+package migrationstatus
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	migrationinformer "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer/migration/v1alpha1"
+	migrationlister "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/lister/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// Checker answers whether resources are migrated from the storageStates of
+// a shared informer. The informer must be started by the caller.
+type Checker struct {
+	informer cache.SharedIndexInformer
+	lister   migrationlister.StorageStateLister
+}
+
+// NewChecker returns a Checker reading the storageStates of the informer.
+// It must be called before the informer is started, so that the informer
+// shares its storageStates with the checker.
+func NewChecker(informer migrationinformer.StorageStateInformer) *Checker {
+	return &Checker{
+		informer: informer.Informer(),
+		lister:   informer.Lister(),
+	}
+}
+
+// IsMigrated returns true if all the persisted objects of the resource are
+// encoded in the storage version whose hash is hash. If hash is empty, any
+// current storage version of the resource is accepted. A resource without
+// a storageState is not migrated: the trigger has not observed it yet.
+func (c *Checker) IsMigrated(resource schema.GroupResource, hash string) (bool, error) {
+	ss, err := c.lister.Get(storageStateName(resource))
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return isMigratedAt(ss, hash), nil
+}
+
+// WaitForMigrated blocks until the resource is migrated to the storage
+// version whose hash is hash, as defined by IsMigrated, or until ctx is
+// done.
+func (c *Checker) WaitForMigrated(ctx context.Context, resource schema.GroupResource, hash string) error {
+	if !cache.WaitForCacheSync(ctx.Done(), c.informer.HasSynced) {
+		return fmt.Errorf("failed to sync the storageStates: %v", ctx.Err())
+	}
+	// changed is notified of every change of the storageState of the
+	// resource, so that it is checked again.
+	changed := make(chan struct{}, 1)
+	notify := func(obj interface{}) {
+		if ss, ok := obj.(*migrationv1alpha1.StorageState); ok && ss.Name != storageStateName(resource) {
+			return
+		}
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	}
+	registration, err := c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    notify,
+		UpdateFunc: func(_, obj interface{}) { notify(obj) },
+	})
+	if err != nil {
+		return err
+	}
+	defer c.informer.RemoveEventHandler(registration)
+
+	for {
+		migrated, err := c.IsMigrated(resource, hash)
+		if err != nil {
+			return err
+		}
+		if migrated {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fmt.Errorf("%s is not migrated: %v", resource, ctx.Err())
+		}
+	}
+}
+
+// Migrated returns a channel receiving the result of WaitForMigrated, i.e.
+// nil once the resource is migrated, or the error of ctx if it is done
+// before. The channel is closed after the result.
+func (c *Checker) Migrated(ctx context.Context, resource schema.GroupResource, hash string) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		defer close(result)
+		result <- c.WaitForMigrated(ctx, resource, hash)
+	}()
+	return result
+}
+
+// BlockingResources returns the sorted resources whose persisted objects
+// might be encoded in other storage versions than the current one, i.e.
+// the resources that would block the removal of a storage version.
+func (c *Checker) BlockingResources() ([]schema.GroupResource, error) {
+	states, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	var blocking []schema.GroupResource
+	for _, ss := range states {
+		if controller.IsStorageStateMigrated(ss) {
+			continue
+		}
+		blocking = append(blocking, schema.GroupResource{
+			Group:    ss.Spec.Resource.Group,
+			Resource: ss.Spec.Resource.Resource,
+		})
+	}
+	sort.Slice(blocking, func(i, j int) bool {
+		return blocking[i].String() < blocking[j].String()
+	})
+	return blocking, nil
+}
+
+// isMigratedAt returns true if the resource of the storageState is
+// migrated, and its current storage version is hash, if set.
+func isMigratedAt(ss *migrationv1alpha1.StorageState, hash string) bool {
+	if hash != "" && ss.Status.CurrentStorageVersionHash != hash {
+		return false
+	}
+	return controller.IsStorageStateMigrated(ss)
+}
+
+func storageStateName(resource schema.GroupResource) string {
+	return controller.StorageStateName(migrationv1alpha1.GroupVersionResource{
+		Group:    resource.Group,
+		Resource: resource.Resource,
+	})
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationstatus
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+var widgets = schema.GroupResource{Group: "example.com", Resource: "widgets"}
+
+func newStorageState(resource schema.GroupResource, current string, persisted ...string) *migrationv1alpha1.StorageState {
+	ss := controller.NewStorageState(migrationv1alpha1.GroupVersionResource{Group: resource.Group, Resource: resource.Resource})
+	ss.Status.CurrentStorageVersionHash = current
+	ss.Status.PersistedStorageVersionHashes = persisted
+	return ss
+}
+
+// newChecker returns a started checker, and the fake client serving its
+// storageStates.
+func newChecker(ctx context.Context, objects ...runtime.Object) (*Checker, *fake.Clientset) {
+	client := fake.NewSimpleClientset(objects...)
+	factory := informer.NewSharedInformerFactory(client, 0)
+	checker := NewChecker(factory.Migration().V1alpha1().StorageStates())
+	factory.Start(ctx.Done())
+	factory.WaitForCacheSync(ctx.Done())
+	return checker, client
+}
+
+func TestIsMigrated(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checker, _ := newChecker(ctx,
+		newStorageState(widgets, "v2", "v2"),
+		newStorageState(schema.GroupResource{Resource: "pods"}, "v2", "v1", "v2"),
+	)
+	tests := []struct {
+		name     string
+		resource schema.GroupResource
+		hash     string
+		expected bool
+	}{
+		{"migrated", widgets, "", true},
+		{"migrated at hash", widgets, "v2", true},
+		{"migrated at another hash", widgets, "v3", false},
+		{"several persisted versions", schema.GroupResource{Resource: "pods"}, "", false},
+		{"no storageState", schema.GroupResource{Resource: "secrets"}, "", false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			migrated, err := checker.IsMigrated(test.resource, test.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if migrated != test.expected {
+				t.Errorf("expected %v, got %v", test.expected, migrated)
+			}
+		})
+	}
+}
+
+func TestWaitForMigrated(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), wait.ForeverTestTimeout)
+	defer cancel()
+	ss := newStorageState(widgets, "v2", "v1", "v2")
+	checker, client := newChecker(ctx, ss)
+
+	result := checker.Migrated(ctx, widgets, "v2")
+	select {
+	case err := <-result:
+		t.Fatalf("expected to wait for the migration, got %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	ss = ss.DeepCopy()
+	ss.Status.PersistedStorageVersionHashes = []string{"v2"}
+	if _, err := client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, ss, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-result; err != nil {
+		t.Errorf("expected widgets to be migrated, got %v", err)
+	}
+}
+
+func TestWaitForMigratedTimesOut(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checker, _ := newChecker(ctx, newStorageState(widgets, "v2", "v1", "v2"))
+
+	timeout, cancelTimeout := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer cancelTimeout()
+	if err := checker.WaitForMigrated(timeout, widgets, ""); err == nil {
+		t.Errorf("expected an error")
+	}
+}
+
+func TestBlockingResources(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	checker, _ := newChecker(ctx,
+		newStorageState(widgets, "v2", "v1", "v2"),
+		newStorageState(schema.GroupResource{Resource: "pods"}, "v2", "v2"),
+		newStorageState(schema.GroupResource{Group: "apps", Resource: "deployments"}, "v2", migrationv1alpha1.Unknown),
+	)
+	blocking, err := checker.BlockingResources()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []schema.GroupResource{{Group: "apps", Resource: "deployments"}, widgets}
+	if !reflect.DeepEqual(blocking, expected) {
+		t.Errorf("expected %v, got %v", expected, blocking)
+	}
+}
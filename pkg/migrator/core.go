@@ -43,24 +43,68 @@ const (
 	defaultConcurrency = 1
 )
 
-type migrator struct {
-	resource    schema.GroupVersionResource
-	client      dynamic.Interface
-	progress    progressInterface
-	concurrency int
+// Options configures the migrator.
+type Options struct {
+	// ChunkLimit is the number of objects listed per request.
+	ChunkLimit int64
+	// Concurrency is the number of objects migrated in parallel.
+	Concurrency int
+	// DryRun rewrites the objects in dry-run mode. The progress of a dry
+	// run is neither loaded nor saved, and it is not observed by the
+	// metrics.
+	DryRun bool
+	// LabelSelector and FieldSelector restrict the objects migrated. The
+	// objects are selected when they are listed.
+	LabelSelector string
+	FieldSelector string
 }
 
-// NewMigrator creates a migrator that can migrate a single resource type.
-func NewMigrator(resource schema.GroupVersionResource, client dynamic.Interface, progress progressInterface) *migrator {
-	return &migrator{
-		resource:    resource,
-		client:      client,
-		progress:    progress,
-		concurrency: defaultConcurrency,
+// DefaultOptions returns the default Options of the migrator.
+func DefaultOptions() Options {
+	return Options{
+		ChunkLimit:  defaultChunkLimit,
+		Concurrency: defaultConcurrency,
 	}
 }
 
-func (m *migrator) get(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
+// Migrator migrates the objects of a resource by rewriting them.
+type Migrator struct {
+	resource      schema.GroupVersionResource
+	client        dynamic.Interface
+	progress      ProgressStore
+	chunkLimit    int64
+	concurrency   int
+	dryRun        bool
+	labelSelector string
+	fieldSelector string
+	hooks         Hooks
+	report        reportCollector
+}
+
+// NewMigrator creates a migrator that can migrate a single resource type. The
+// migrator resumes from the progress saved in progress, and saves its
+// progress after every chunk. If progress is nil, the migrator starts from
+// the beginning and saves nothing.
+func NewMigrator(resource schema.GroupVersionResource, client dynamic.Interface, progress ProgressStore, options Options) *Migrator {
+	return &Migrator{
+		resource:      resource,
+		client:        client,
+		progress:      progress,
+		chunkLimit:    options.ChunkLimit,
+		concurrency:   options.Concurrency,
+		dryRun:        options.DryRun,
+		labelSelector: options.LabelSelector,
+		fieldSelector: options.FieldSelector,
+	}
+}
+
+// SetHooks sets the callbacks run for every object. It must be called before
+// Run.
+func (m *Migrator) SetHooks(hooks Hooks) {
+	m.hooks = hooks
+}
+
+func (m *Migrator) get(ctx context.Context, namespace, name string) (*unstructured.Unstructured, error) {
 	// if namespace is empty, .Namespace(namespace) is ineffective.
 	return m.client.
 		Resource(m.resource).
@@ -68,32 +112,56 @@ func (m *migrator) get(ctx context.Context, namespace, name string) (*unstructur
 		Get(ctx, name, metav1.GetOptions{})
 }
 
-func (m *migrator) put(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+func (m *Migrator) put(ctx context.Context, namespace string, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	// if namespace is empty, .Namespace(namespace) is ineffective.
 	return m.client.
 		Resource(m.resource).
 		Namespace(namespace).
-		Update(ctx, obj, metav1.UpdateOptions{})
+		Update(ctx, obj, m.updateOptions())
 }
 
-func (m *migrator) list(ctx context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+func (m *Migrator) updateOptions() metav1.UpdateOptions {
+	if m.dryRun {
+		return metav1.UpdateOptions{DryRun: []string{metav1.DryRunAll}}
+	}
+	return metav1.UpdateOptions{}
+}
+
+func (m *Migrator) list(ctx context.Context, options metav1.ListOptions) (*unstructured.UnstructuredList, error) {
 	return m.client.
 		Resource(m.resource).
 		Namespace(metav1.NamespaceAll).
 		List(ctx, options)
 }
 
+// Report returns the record of what the last Run touched.
+func (m *Migrator) Report() Report {
+	return m.report.get()
+}
+
 // Run migrates all the instances of the resource type managed by the migrator.
-func (m *migrator) Run(ctx context.Context) error {
-	continueToken, err := m.progress.load(ctx)
-	if err != nil {
-		return err
+func (m *Migrator) Run(ctx context.Context) error {
+	m.report.start(time.Now(), m.dryRun)
+	defer func() { m.report.complete(time.Now()) }()
+	var continueToken string
+	if m.tracksProgress() {
+		var err error
+		continueToken, err = m.progress.Load(ctx)
+		if err != nil {
+			return err
+		}
+		defer m.flushProgress()
 	}
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		list, listError := m.list(ctx,
 			metav1.ListOptions{
-				Limit:    defaultChunkLimit,
-				Continue: continueToken,
+				Limit:         m.chunkLimit,
+				Continue:      continueToken,
+				LabelSelector: m.labelSelector,
+				FieldSelector: m.fieldSelector,
 			},
 		)
 		if errors.IsNotFound(listError) {
@@ -102,6 +170,7 @@ func (m *migrator) Run(ctx context.Context) error {
 		}
 		if listError != nil && !errors.IsResourceExpired(listError) {
 			if canRetry(listError) {
+				m.report.retried()
 				if seconds, delay := errors.SuggestsClientDelay(listError); delay {
 					time.Sleep(time.Duration(seconds) * time.Second)
 				}
@@ -115,34 +184,79 @@ func (m *migrator) Run(ctx context.Context) error {
 				return err
 			}
 			continueToken = token
-			err = m.progress.save(ctx, continueToken)
-			if err != nil {
-				utilruntime.HandleError(err)
-			}
+			m.saveProgress(ctx, continueToken)
 			continue
 		}
-		if err := m.migrateList(list); err != nil {
+		m.report.listed(list.GetResourceVersion())
+		if err := m.migrateList(ctx, list); err != nil {
+			return err
+		}
+		// migrateList stops feeding the items once ctx is done, so the
+		// chunk may not have been fully migrated.
+		if err := ctx.Err(); err != nil {
 			return err
 		}
 		token, err := metadataAccessor.Continue(list)
 		if err != nil {
 			return err
 		}
-		metrics.Metrics.ObserveObjectsMigrated(len(list.Items), m.resource.String())
+		if !m.dryRun {
+			metrics.Metrics.ObserveObjectsMigrated(len(list.Items), m.resource.String())
+		}
 		// TODO: call ObserveObjectsRemaining as well, once https://github.com/kubernetes/kubernetes/pull/75993 is in.
 		if len(token) == 0 {
+			// Clear the saved token, so that a store reused by the next
+			// run does not resume it from the last chunk.
+			m.saveProgress(ctx, "")
 			return nil
 		}
 		continueToken = token
-		err = m.progress.save(ctx, continueToken)
-		if err != nil {
-			utilruntime.HandleError(err)
-		}
+		m.saveProgress(ctx, continueToken)
 	}
 }
 
-func (m *migrator) migrateList(l *unstructured.UnstructuredList) error {
-	ctx, cancel := context.WithCancel(context.Background())
+// tracksProgress returns true if the migrator loads and saves its progress.
+func (m *Migrator) tracksProgress() bool {
+	return !m.dryRun && m.progress != nil
+}
+
+func (m *Migrator) saveProgress(ctx context.Context, continueToken string) {
+	if !m.tracksProgress() {
+		return
+	}
+	m.countProgress()
+	if err := m.progress.Save(ctx, continueToken); err != nil {
+		utilruntime.HandleError(err)
+	}
+}
+
+// countProgress sets the number of objects rewritten in the progress store,
+// if it records it.
+func (m *Migrator) countProgress() {
+	if c, ok := m.progress.(ProgressCounter); ok && m.tracksProgress() {
+		c.SetRewritten(m.report.get().Rewritten)
+	}
+}
+
+// flushTimeout limits the flush of the progress when Run returns, which
+// outlives the context of Run.
+const flushTimeout = 30 * time.Second
+
+// flushProgress writes the progress deferred by the store, if any.
+func (m *Migrator) flushProgress() {
+	f, ok := m.progress.(ProgressFlusher)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), flushTimeout)
+	defer cancel()
+	if err := f.Flush(ctx); err != nil {
+		utilruntime.HandleError(err)
+	}
+}
+
+func (m *Migrator) migrateList(ctx context.Context, l *unstructured.UnstructuredList) error {
+	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	workc := make(chan *unstructured.Unstructured)
@@ -179,7 +293,7 @@ func (m *migrator) migrateList(l *unstructured.UnstructuredList) error {
 	return utilerrors.NewAggregate(errors)
 }
 
-func (m *migrator) worker(ctx context.Context, workc <-chan *unstructured.Unstructured, errc chan<- error) {
+func (m *Migrator) worker(ctx context.Context, workc <-chan *unstructured.Unstructured, errc chan<- error) {
 	for item := range workc {
 		err := m.migrateOneItem(ctx, item)
 		if err != nil {
@@ -193,7 +307,7 @@ func (m *migrator) worker(ctx context.Context, workc <-chan *unstructured.Unstru
 	}
 }
 
-func (m *migrator) migrateOneItem(ctx context.Context, item *unstructured.Unstructured) error {
+func (m *Migrator) migrateOneItem(ctx context.Context, item *unstructured.Unstructured) error {
 	namespace, err := metadataAccessor.Namespace(item)
 	if err != nil {
 		return err
@@ -202,13 +316,48 @@ func (m *migrator) migrateOneItem(ctx context.Context, item *unstructured.Unstru
 	if err != nil {
 		return err
 	}
+	if !m.hooks.before(ctx, item) {
+		m.report.skipped()
+		m.hooks.after(ctx, item, ObjectSkipped, nil)
+		return nil
+	}
+	err = m.rewrite(ctx, namespace, name, item)
+	switch {
+	case err == nil:
+		m.report.rewritten()
+		m.hooks.after(ctx, item, ObjectRewritten, nil)
+		return nil
+	case errors.IsNotFound(err):
+		m.report.skipped()
+		m.hooks.after(ctx, item, ObjectSkipped, nil)
+		return nil
+	case ctx.Err() != nil:
+		// The migration is interrupted, the object is neither migrated
+		// nor failed.
+		return err
+	}
+	m.report.failed(namespace, name, err)
+	m.hooks.after(ctx, item, ObjectFailed, err)
+	if m.hooks.onError(ctx, item, err) == ErrorActionIgnore {
+		return nil
+	}
+	return err
+}
+
+// rewrite rewrites the object, retrying the retriable errors.
+func (m *Migrator) rewrite(ctx context.Context, namespace, name string, item *unstructured.Unstructured) error {
 	getBeforePut := false
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var err error
 		getBeforePut, err = m.try(ctx, namespace, name, item, getBeforePut)
 		if err == nil || errors.IsNotFound(err) {
-			return nil
+			return err
 		}
 		if canRetry(err) {
+			m.report.retried()
 			seconds, delay := errors.SuggestsClientDelay(err)
 			switch {
 			case delay && len(namespace) > 0:
@@ -232,7 +381,7 @@ func (m *migrator) migrateOneItem(ctx context.Context, item *unstructured.Unstru
 // try tries to migrate the single object by PUT. It refreshes the object via
 // GET if "get" is true. If the PUT fails due to conflicts, or the GET fails,
 // the function requests the next try to GET the new object.
-func (m *migrator) try(ctx context.Context, namespace, name string, item *unstructured.Unstructured, get bool) (bool, error) {
+func (m *Migrator) try(ctx context.Context, namespace, name string, item *unstructured.Unstructured, get bool) (bool, error) {
 	var err error
 	if get {
 		item, err = m.get(ctx, namespace, name)
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -156,8 +157,8 @@ func TestMigrateList(t *testing.T) {
 		return false, nil, nil
 	})
 
-	migrator := NewMigrator(v1.SchemeGroupVersion.WithResource("pods"), client, &progressTracker{})
-	migratorError := migrator.migrateList(toUnstructuredListOrDie(podList))
+	migrator := NewMigrator(v1.SchemeGroupVersion.WithResource("pods"), client, nil, DefaultOptions())
+	migratorError := migrator.migrateList(context.TODO(), toUnstructuredListOrDie(podList))
 
 	// Validating sent requests.
 	nsSet := sets.NewString()
@@ -199,6 +200,14 @@ func TestMigrateList(t *testing.T) {
 	if migratorError.Error() != `update is not supported on resources of kind "pods"` {
 		t.Errorf("unexpected error message %s", migratorError)
 	}
+
+	report := migrator.Report()
+	if report.Rewritten != 98 || report.Skipped != 1 || report.Failed != 1 || report.Retries != 1 {
+		t.Errorf("expected 98 rewritten, 1 skipped, 1 failed and 1 retried objects, got %+v", report)
+	}
+	if len(report.FailedObjects) != 1 || report.FailedObjects[0].Name != "pod50" || report.FailedObjects[0].Namespace != "namespace50" {
+		t.Errorf("expected pod50 to fail, got %v", report.FailedObjects)
+	}
 }
 
 func TestMigrateListClusterScoped(t *testing.T) {
@@ -206,8 +215,8 @@ func TestMigrateListClusterScoped(t *testing.T) {
 	nodeList := newNodeList(100)
 	client := fake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, nil, &nodeList)
 
-	migrator := NewMigrator(v1.SchemeGroupVersion.WithResource("nodes"), client, &progressTracker{})
-	err := migrator.migrateList(toUnstructuredListOrDie(nodeList))
+	migrator := NewMigrator(v1.SchemeGroupVersion.WithResource("nodes"), client, nil, DefaultOptions())
+	err := migrator.migrateList(context.TODO(), toUnstructuredListOrDie(nodeList))
 	if err != nil {
 		t.Errorf("unexpected migration error, %v", err)
 	}
@@ -243,11 +252,11 @@ func TestMigrateListClusterScoped(t *testing.T) {
 
 type fakeProgress struct{}
 
-func (f *fakeProgress) load(ctx context.Context) (string, error) {
+func (f *fakeProgress) Load(ctx context.Context) (string, error) {
 	return "", nil
 }
 
-func (f *fakeProgress) save(context.Context, string) error {
+func (f *fakeProgress) Save(context.Context, string) error {
 	return nil
 }
 
@@ -256,7 +265,7 @@ func TestMetrics(t *testing.T) {
 	// fake client doesn't support pagination, so we can't test complex behavior.
 	nodeList := newNodeList(100)
 	client := fake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, nil, &nodeList)
-	migrator := NewMigrator(v1.SchemeGroupVersion.WithResource("nodes"), client, &fakeProgress{})
+	migrator := NewMigrator(v1.SchemeGroupVersion.WithResource("nodes"), client, &fakeProgress{}, DefaultOptions())
 	ctx := context.TODO()
 	migrator.Run(ctx)
 	expectCounterCount(t,
@@ -266,7 +275,10 @@ func TestMetrics(t *testing.T) {
 		},
 		100,
 	)
-
+	report := migrator.Report()
+	if report.Rewritten != 100 || report.StartTime.IsZero() || report.CompletionTime.Before(report.StartTime) {
+		t.Errorf("expected a report of 100 rewritten objects, got %+v", report)
+	}
 }
 
 func labelsMatch(metric *ptype.Metric, labelFilter map[string]string) bool {
@@ -313,3 +325,48 @@ func expectCounterCount(t *testing.T, name string, labelFilter map[string]string
 		}
 	}
 }
+
+type unexpectedProgress struct {
+	t *testing.T
+}
+
+func (p *unexpectedProgress) Load(ctx context.Context) (string, error) {
+	p.t.Errorf("unexpected load of the progress")
+	return "", nil
+}
+
+func (p *unexpectedProgress) Save(context.Context, string) error {
+	p.t.Errorf("unexpected save of the progress")
+	return nil
+}
+
+func TestDryRun(t *testing.T) {
+	metrics.Metrics.Reset()
+	nodeList := newNodeList(10)
+	client := fake.NewSimpleDynamicClientWithCustomListKinds(scheme.Scheme, nil, &nodeList)
+	options := DefaultOptions()
+	options.DryRun = true
+	migrator := NewMigrator(v1.SchemeGroupVersion.WithResource("nodes"), client, &unexpectedProgress{t: t}, options)
+	if err := migrator.Run(context.TODO()); err != nil {
+		t.Fatalf("unexpected migration error, %v", err)
+	}
+	if e, a := []string{metav1.DryRunAll}, migrator.updateOptions().DryRun; !reflect.DeepEqual(e, a) {
+		t.Errorf("expected dry run %v, got %v", e, a)
+	}
+	updates := 0
+	for _, a := range client.Actions() {
+		if a.GetVerb() == "update" {
+			updates++
+		}
+	}
+	if updates != 10 {
+		t.Errorf("expected 10 updates, got %d", updates)
+	}
+	expectCounterCount(t,
+		"storage_migrator_core_migrator_migrated_objects",
+		map[string]string{
+			"resource": "/v1, Resource=nodes",
+		},
+		0,
+	)
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ObjectOutcome is what happened to an object of a migration.
+type ObjectOutcome string
+
+const (
+	// ObjectRewritten means the object was rewritten.
+	ObjectRewritten ObjectOutcome = "Rewritten"
+	// ObjectSkipped means the object was deleted before it was rewritten,
+	// or skipped by the BeforeObject hook.
+	ObjectSkipped ObjectOutcome = "Skipped"
+	// ObjectFailed means the object could not be rewritten.
+	ObjectFailed ObjectOutcome = "Failed"
+)
+
+// ErrorAction tells the migrator what to do with an object that could not
+// be rewritten.
+type ErrorAction int
+
+const (
+	// ErrorActionFail fails the run once the other objects of the chunk are
+	// migrated. It is the default.
+	ErrorActionFail ErrorAction = iota
+	// ErrorActionIgnore records the failure in the report, and goes on.
+	ErrorActionIgnore
+)
+
+// Hooks are callbacks run for every object of a migration. They are called
+// concurrently by the workers of the migrator, and must be safe for
+// concurrent use. All of them are optional.
+type Hooks struct {
+	// BeforeObject is called before the object is rewritten. Returning
+	// false skips the object.
+	BeforeObject func(ctx context.Context, obj *unstructured.Unstructured) bool
+	// AfterObject is called once the outcome of the object is known, with
+	// the error of a failed object.
+	AfterObject func(ctx context.Context, obj *unstructured.Unstructured, outcome ObjectOutcome, err error)
+	// OnError decides whether the error of an object, which the migrator
+	// does not retry, fails the run.
+	OnError func(ctx context.Context, obj *unstructured.Unstructured, err error) ErrorAction
+}
+
+func (h Hooks) before(ctx context.Context, obj *unstructured.Unstructured) bool {
+	return h.BeforeObject == nil || h.BeforeObject(ctx, obj)
+}
+
+func (h Hooks) after(ctx context.Context, obj *unstructured.Unstructured, outcome ObjectOutcome, err error) {
+	if h.AfterObject != nil {
+		h.AfterObject(ctx, obj, outcome, err)
+	}
+}
+
+func (h Hooks) onError(ctx context.Context, obj *unstructured.Unstructured, err error) ErrorAction {
+	if h.OnError == nil {
+		return ErrorActionFail
+	}
+	return h.OnError(ctx, obj, err)
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package metrics
 
 import (
+	"time"
+
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -35,6 +37,9 @@ type CoreMigratorMetrics struct {
 	objectsMigrated  *prometheus.CounterVec
 	objectsRemaining *prometheus.GaugeVec
 	migration        *prometheus.CounterVec
+	queuePosition    *prometheus.GaugeVec
+	queuePriority    *prometheus.GaugeVec
+	queueWait        *prometheus.HistogramVec
 }
 
 // newCoreMigratorMetrics create a new CoreMigratorMetrics, configured with default metric names.
@@ -66,10 +71,42 @@ func newCoreMigratorMetrics() *CoreMigratorMetrics {
 		}, []string{"resource", "status"})
 	prometheus.MustRegister(migration)
 
+	queuePosition := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "pending_migration_position",
+			Help:      "The position of the pending migrations in the order the migrator runs them, starting at 1, labeled with the full resource name",
+		}, []string{"resource"})
+	prometheus.MustRegister(queuePosition)
+
+	queuePriority := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "pending_migration_effective_priority",
+			Help:      "The priority of the pending migrations, including the aging, labeled with the full resource name",
+		}, []string{"resource"})
+	prometheus.MustRegister(queuePriority)
+
+	queueWait := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "migration_wait_seconds",
+			Help:      "How long the migrations waited pending before they started running, labeled with the full resource name",
+			// From 1 second to about 3 days.
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{"resource"})
+	prometheus.MustRegister(queueWait)
+
 	return &CoreMigratorMetrics{
 		objectsMigrated:  objectsMigrated,
 		objectsRemaining: objectsRemaining,
 		migration:        migration,
+		queuePosition:    queuePosition,
+		queuePriority:    queuePriority,
+		queueWait:        queueWait,
 	}
 }
 
@@ -77,6 +114,9 @@ func (m *CoreMigratorMetrics) Reset() {
 	m.objectsMigrated.Reset()
 	m.objectsRemaining.Reset()
 	m.migration.Reset()
+	m.queuePosition.Reset()
+	m.queuePriority.Reset()
+	m.queueWait.Reset()
 }
 
 // ObserveObjectsMigrated adds the number of migrated objects for a resource type..
@@ -98,3 +138,23 @@ func (m *CoreMigratorMetrics) ObserveSucceededMigration(resource string) {
 func (m *CoreMigratorMetrics) ObserveFailedMigration(resource string) {
 	m.migration.WithLabelValues(resource, "Failed").Add(float64(1))
 }
+
+// ResetPendingMigrations forgets the pending migrations, before they are
+// observed again.
+func (m *CoreMigratorMetrics) ResetPendingMigrations() {
+	m.queuePosition.Reset()
+	m.queuePriority.Reset()
+}
+
+// ObservePendingMigration records the position and the effective priority of
+// a pending migration of a resource type.
+func (m *CoreMigratorMetrics) ObservePendingMigration(resource string, position int, priority int64) {
+	m.queuePosition.WithLabelValues(resource).Set(float64(position))
+	m.queuePriority.WithLabelValues(resource).Set(float64(priority))
+}
+
+// ObserveMigrationWait records how long a migration of a resource type
+// waited before it started running.
+func (m *CoreMigratorMetrics) ObserveMigrationWait(resource string, wait time.Duration) {
+	m.queueWait.WithLabelValues(resource).Observe(wait.Seconds())
+}
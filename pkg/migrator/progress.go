@@ -1,5 +1,5 @@
 /*
-Copyright 2018 The Kubernetes Authors.
+Copyright 2026 The Kubernetes Authors.
 
 Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
@@ -17,48 +17,146 @@ limitations under the License.
 package migrator
 
 import (
-	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/typed/migration/v1alpha1"
-
 	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/util/retry"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+
+	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/typed/migration/v1alpha1"
 )
 
-type progressInterface interface {
-	save(ctx context.Context, continueToken string) error
-	load(ctx context.Context) (continueToken string, err error)
+// ProgressStore saves the continue token of the last migrated chunk, so that
+// an interrupted migration resumes from it.
+type ProgressStore interface {
+	// Save saves the continue token of the last migrated chunk. The
+	// migrator saves "" once it has migrated the last chunk.
+	Save(ctx context.Context, continueToken string) error
+	// Load returns the saved continue token, or "" if there is none.
+	Load(ctx context.Context) (continueToken string, err error)
 }
 
-type progressTracker struct {
-	client migrationclient.StorageVersionMigrationInterface
-	name   string
+// ProgressFlusher is implemented by the progress stores deferring their
+// writes. The migrator flushes the store when Run returns.
+type ProgressFlusher interface {
+	// Flush writes the continue token saved last, if it has not been
+	// written yet.
+	Flush(ctx context.Context) error
 }
 
-// NewProgressTracker returns a progress tracker.
-func NewProgressTracker(client migrationclient.StorageVersionMigrationInterface, name string) progressInterface {
-	return &progressTracker{
-		client: client,
-		name:   name,
+// ProgressCounter is implemented by the progress stores recording the number
+// of objects rewritten. The migrator sets the count before it saves the
+// progress, and once the last chunk is migrated, before the store is
+// flushed.
+type ProgressCounter interface {
+	// SetRewritten sets the number of objects rewritten by the run.
+	SetRewritten(rewritten int)
+}
+
+// DefaultCheckpointInterval is the default minimum interval between the
+// writes of the progress store of the storageVersionMigrations.
+const DefaultCheckpointInterval = 10 * time.Second
+
+// migrationProgressStore saves the progress in the status of a
+// storageVersionMigration.
+type migrationProgressStore struct {
+	client   migrationclient.StorageVersionMigrationInterface
+	name     string
+	interval time.Duration
+	clock    clock.PassiveClock
+
+	// lock protects pending, dirty, lastWrite, resumed and rewritten.
+	lock      sync.Mutex
+	pending   string
+	dirty     bool
+	lastWrite time.Time
+	// resumed is the number of objects rewritten by the previous runs of
+	// the migration, if the run resumes from their continue token.
+	resumed int64
+	// rewritten is the number of objects rewritten by the run.
+	rewritten int64
+}
+
+// NewMigrationProgressStore returns a ProgressStore saving the continue token
+// in the .status.continueToken of the storageVersionMigration name, and the
+// number of objects rewritten in its .status.objectsMigrated. They are
+// written by a merge patch of the status subresource, so that they do not
+// conflict with the edits of the spec. The writes are debounced:
+// a token saved less than interval after the last write is only written by
+// a later Save or by Flush.
+func NewMigrationProgressStore(client migrationclient.StorageVersionMigrationInterface, name string, interval time.Duration) ProgressStore {
+	return &migrationProgressStore{
+		client:   client,
+		name:     name,
+		interval: interval,
+		clock:    clock.RealClock{},
 	}
 }
 
-func (p *progressTracker) save(ctx context.Context, continueToken string) error {
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		migration, err := p.client.Get(ctx, p.name, metav1.GetOptions{})
-		if err != nil {
-			return err
-		}
-		migration.Spec.ContinueToken = continueToken
-		_, err = p.client.Update(ctx, migration, metav1.UpdateOptions{})
-		return err
+func (p *migrationProgressStore) Save(ctx context.Context, continueToken string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.pending, p.dirty = continueToken, true
+	if now := p.clock.Now(); !p.lastWrite.IsZero() && now.Sub(p.lastWrite) < p.interval {
+		return nil
+	}
+	return p.write(ctx)
+}
+
+func (p *migrationProgressStore) SetRewritten(rewritten int) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if int64(rewritten) != p.rewritten {
+		p.rewritten, p.dirty = int64(rewritten), true
+	}
+}
+
+func (p *migrationProgressStore) Flush(ctx context.Context) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if !p.dirty {
+		return nil
+	}
+	return p.write(ctx)
+}
+
+// write patches the pending token. p.lock must be held.
+func (p *migrationProgressStore) write(ctx context.Context) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"continueToken":   p.pending,
+			"objectsMigrated": p.resumed + p.rewritten,
+		},
 	})
+	if err != nil {
+		return err
+	}
+	if _, err := p.client.Patch(ctx, p.name, types.MergePatchType, patch, metav1.PatchOptions{}, "status"); err != nil {
+		return fmt.Errorf("failed to save the progress of migration %s: %v", p.name, err)
+	}
+	p.dirty = false
+	p.lastWrite = p.clock.Now()
+	return nil
 }
 
-func (p *progressTracker) load(ctx context.Context) (continueToken string, err error) {
+// Load returns the .status.continueToken of the migration, or its
+// deprecated .spec.continueToken if the migration was started by an older
+// migrator.
+func (p *migrationProgressStore) Load(ctx context.Context) (string, error) {
 	migration, err := p.client.Get(ctx, p.name, metav1.GetOptions{})
 	if err != nil {
 		return "", err
 	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if migration.Status.ContinueToken != "" {
+		p.resumed = migration.Status.ObjectsMigrated
+		return migration.Status.ContinueToken, nil
+	}
+	p.resumed = 0
 	return migration.Spec.ContinueToken, nil
 }
@@ -0,0 +1,174 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ContinueTokenAnnotation is the annotation of the Lease progress stores.
+const ContinueTokenAnnotation = "migration.k8s.io/continue-token"
+
+// configMapProgressStore saves the progress in a key of a ConfigMap.
+type configMapProgressStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+	key       string
+}
+
+// NewConfigMapProgressStore returns a ProgressStore saving the continue token
+// in the key of the ConfigMap namespace/name, which is created if it does
+// not exist. The other keys of the ConfigMap are left untouched.
+func NewConfigMapProgressStore(client kubernetes.Interface, namespace, name, key string) ProgressStore {
+	return &configMapProgressStore{client: client, namespace: namespace, name: name, key: key}
+}
+
+func (p *configMapProgressStore) Save(ctx context.Context, continueToken string) error {
+	configMaps := p.client.CoreV1().ConfigMaps(p.namespace)
+	patch, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{p.key: continueToken},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = configMaps.Patch(ctx, p.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		_, err = configMaps.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: p.name, Namespace: p.namespace},
+			Data:       map[string]string{p.key: continueToken},
+		}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save the progress to the ConfigMap %s/%s: %v", p.namespace, p.name, err)
+	}
+	return nil
+}
+
+func (p *configMapProgressStore) Load(ctx context.Context) (string, error) {
+	cm, err := p.client.CoreV1().ConfigMaps(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return cm.Data[p.key], nil
+}
+
+// leaseProgressStore saves the progress in an annotation of a Lease.
+type leaseProgressStore struct {
+	client    kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewLeaseProgressStore returns a ProgressStore saving the continue token in
+// the ContinueTokenAnnotation of the Lease namespace/name, which is created if
+// it does not exist. The spec of the Lease is left untouched, so the Lease of
+// a leader election can carry the progress of the leader.
+func NewLeaseProgressStore(client kubernetes.Interface, namespace, name string) ProgressStore {
+	return &leaseProgressStore{client: client, namespace: namespace, name: name}
+}
+
+func (p *leaseProgressStore) Save(ctx context.Context, continueToken string) error {
+	leases := p.client.CoordinationV1().Leases(p.namespace)
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{ContinueTokenAnnotation: continueToken},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = leases.Patch(ctx, p.name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if errors.IsNotFound(err) {
+		_, err = leases.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        p.name,
+				Namespace:   p.namespace,
+				Annotations: map[string]string{ContinueTokenAnnotation: continueToken},
+			},
+		}, metav1.CreateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save the progress to the Lease %s/%s: %v", p.namespace, p.name, err)
+	}
+	return nil
+}
+
+func (p *leaseProgressStore) Load(ctx context.Context) (string, error) {
+	lease, err := p.client.CoordinationV1().Leases(p.namespace).Get(ctx, p.name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return lease.Annotations[ContinueTokenAnnotation], nil
+}
+
+// fileProgressStore saves the progress in a local file.
+type fileProgressStore struct {
+	path string
+}
+
+// NewFileProgressStore returns a ProgressStore saving the continue token in
+// the file at path. The file is replaced atomically, so that a crash never
+// leaves a truncated token.
+func NewFileProgressStore(path string) ProgressStore {
+	return &fileProgressStore{path: path}
+}
+
+func (p *fileProgressStore) Save(_ context.Context, continueToken string) error {
+	f, err := os.CreateTemp(filepath.Dir(p.path), filepath.Base(p.path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(continueToken); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), p.path)
+}
+
+func (p *fileProgressStore) Load(_ context.Context) (string, error) {
+	data, err := os.ReadFile(p.path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	clitesting "k8s.io/client-go/testing"
+	testingclock "k8s.io/utils/clock/testing"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+)
+
+func newMigration(name string) *migrationv1alpha1.StorageVersionMigration {
+	return &migrationv1alpha1.StorageVersionMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: migrationv1alpha1.StorageVersionMigrationSpec{
+			Resource: migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+		},
+	}
+}
+
+func patches(client *fake.Clientset) []clitesting.PatchAction {
+	var patches []clitesting.PatchAction
+	for _, a := range client.Actions() {
+		if p, ok := a.(clitesting.PatchAction); ok {
+			patches = append(patches, p)
+		}
+	}
+	return patches
+}
+
+func TestMigrationProgressStore(t *testing.T) {
+	client := fake.NewSimpleClientset(newMigration("pods"))
+	store := NewMigrationProgressStore(client.MigrationV1alpha1().StorageVersionMigrations(), "pods", 10*time.Second).(*migrationProgressStore)
+	clock := testingclock.NewFakePassiveClock(time.Date(2026, 3, 10, 1, 0, 0, 0, time.UTC))
+	store.clock = clock
+	ctx := context.TODO()
+
+	// The first token is written, the next ones are debounced.
+	for _, token := range []string{"a", "b", "c"} {
+		if err := store.Save(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p := patches(client); len(p) != 1 || p[0].GetSubresource() != "status" || string(p[0].GetPatch()) != `{"status":{"continueToken":"a","objectsMigrated":0}}` {
+		t.Fatalf("expected a single patch of the status, got %v", p)
+	}
+	clock.SetTime(clock.Now().Add(10 * time.Second))
+	if err := store.Save(ctx, "d"); err != nil {
+		t.Fatal(err)
+	}
+	if p := patches(client); len(p) != 2 {
+		t.Fatalf("expected the token to be written once the interval elapsed, got %v", p)
+	}
+	if err := store.Save(ctx, "e"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if p := patches(client); len(p) != 3 {
+		t.Fatalf("expected Flush to write the pending token once, got %v", p)
+	}
+	token, err := store.Load(ctx)
+	if err != nil || token != "e" {
+		t.Errorf("expected token e, got %q, %v", token, err)
+	}
+	m, err := client.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, "pods", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Spec.ContinueToken != "" {
+		t.Errorf("expected the spec to be untouched, got %q", m.Spec.ContinueToken)
+	}
+}
+
+func TestMigrationProgressStoreCountsObjects(t *testing.T) {
+	m := newMigration("pods")
+	m.Status.ContinueToken = "a"
+	m.Status.ObjectsMigrated = 100
+	client := fake.NewSimpleClientset(m)
+	store := NewMigrationProgressStore(client.MigrationV1alpha1().StorageVersionMigrations(), "pods", time.Hour)
+	ctx := context.TODO()
+
+	// The run resumes from the token, counting the objects rewritten by
+	// the previous runs.
+	if _, err := store.Load(ctx); err != nil {
+		t.Fatal(err)
+	}
+	store.(ProgressCounter).SetRewritten(10)
+	if err := store.Save(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	// The count of the last chunk is written by Flush.
+	store.(ProgressCounter).SetRewritten(15)
+	if err := store.(ProgressFlusher).Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	m, err := client.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, "pods", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Status.ContinueToken != "b" || m.Status.ObjectsMigrated != 115 {
+		t.Errorf("expected token b and 115 objects migrated, got %q and %d", m.Status.ContinueToken, m.Status.ObjectsMigrated)
+	}
+}
+
+func TestMigrationProgressStoreLoadsLegacyToken(t *testing.T) {
+	m := newMigration("pods")
+	m.Spec.ContinueToken = "legacy"
+	client := fake.NewSimpleClientset(m)
+	store := NewMigrationProgressStore(client.MigrationV1alpha1().StorageVersionMigrations(), "pods", 0)
+	token, err := store.Load(context.TODO())
+	if err != nil || token != "legacy" {
+		t.Errorf("expected the token of the spec, got %q, %v", token, err)
+	}
+}
+
+func testProgressStore(t *testing.T, store ProgressStore) {
+	t.Helper()
+	ctx := context.TODO()
+	token, err := store.Load(ctx)
+	if err != nil || token != "" {
+		t.Fatalf("expected no token, got %q, %v", token, err)
+	}
+	for _, token := range []string{"a", "b"} {
+		if err := store.Save(ctx, token); err != nil {
+			t.Fatal(err)
+		}
+	}
+	token, err = store.Load(ctx)
+	if err != nil || token != "b" {
+		t.Errorf("expected token b, got %q, %v", token, err)
+	}
+}
+
+func TestConfigMapProgressStore(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	testProgressStore(t, NewConfigMapProgressStore(client, "default", "progress", "pods"))
+	cm, err := client.CoreV1().ConfigMaps("default").Get(context.TODO(), "progress", metav1.GetOptions{})
+	if err != nil || cm.Data["pods"] != "b" {
+		t.Errorf("expected the token in the ConfigMap, got %v, %v", cm, err)
+	}
+}
+
+func TestLeaseProgressStore(t *testing.T) {
+	client := kubefake.NewSimpleClientset()
+	testProgressStore(t, NewLeaseProgressStore(client, "default", "migrator"))
+	lease, err := client.CoordinationV1().Leases("default").Get(context.TODO(), "migrator", metav1.GetOptions{})
+	if err != nil || lease.Annotations[ContinueTokenAnnotation] != "b" {
+		t.Errorf("expected the token in the Lease, got %v, %v", lease, err)
+	}
+}
+
+func TestFileProgressStore(t *testing.T) {
+	testProgressStore(t, NewFileProgressStore(filepath.Join(t.TempDir(), "progress")))
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrator
+
+import (
+	"sync"
+	"time"
+)
+
+// maxReportedFailures caps the failed objects listed in a Report, so that
+// the report of a resource with many failing objects stays small.
+const maxReportedFailures = 100
+
+// Report is the record of what a run of the migrator touched.
+type Report struct {
+	// DryRun is true if the objects were rewritten in dry-run mode.
+	DryRun bool `json:"dryRun,omitempty"`
+	// StartTime and CompletionTime bound the run.
+	StartTime      time.Time `json:"startTime"`
+	CompletionTime time.Time `json:"completionTime"`
+	// Rewritten is the number of objects rewritten.
+	Rewritten int `json:"rewritten"`
+	// Skipped is the number of objects deleted before they were rewritten,
+	// or skipped by the BeforeObject hook.
+	Skipped int `json:"skipped"`
+	// Failed is the number of objects that could not be rewritten.
+	Failed int `json:"failed"`
+	// FailedObjects lists the first failed objects, with the reasons of
+	// their failures.
+	FailedObjects []ObjectFailure `json:"failedObjects,omitempty"`
+	// Retries is the number of requests retried, listing the objects or
+	// rewriting them.
+	Retries int `json:"retries"`
+	// FirstResourceVersion and LastResourceVersion are the resourceVersions
+	// of the first and the last chunks listed by the run.
+	FirstResourceVersion string `json:"firstResourceVersion,omitempty"`
+	LastResourceVersion  string `json:"lastResourceVersion,omitempty"`
+}
+
+// ObjectFailure is an object that could not be rewritten.
+type ObjectFailure struct {
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+}
+
+// reportCollector collects the Report of a run from the workers of the
+// migrator.
+type reportCollector struct {
+	lock   sync.Mutex
+	report Report
+}
+
+func (c *reportCollector) start(now time.Time, dryRun bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.report = Report{StartTime: now, DryRun: dryRun}
+}
+
+func (c *reportCollector) complete(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.report.CompletionTime = now
+}
+
+func (c *reportCollector) listed(resourceVersion string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.report.FirstResourceVersion == "" {
+		c.report.FirstResourceVersion = resourceVersion
+	}
+	c.report.LastResourceVersion = resourceVersion
+}
+
+func (c *reportCollector) rewritten() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.report.Rewritten++
+}
+
+func (c *reportCollector) skipped() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.report.Skipped++
+}
+
+func (c *reportCollector) retried() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.report.Retries++
+}
+
+func (c *reportCollector) failed(namespace, name string, err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.report.Failed++
+	if len(c.report.FailedObjects) < maxReportedFailures {
+		c.report.FailedObjects = append(c.report.FailedObjects, ObjectFailure{Namespace: namespace, Name: name, Reason: err.Error()})
+	}
+}
+
+func (c *reportCollector) get() Report {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	r := c.report
+	r.FailedObjects = append([]ObjectFailure(nil), c.report.FailedObjects...)
+	return r
+}
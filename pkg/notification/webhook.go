@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notification delivers notifications of the state transitions of
+// the storageVersionMigrations to HTTP webhooks.
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+const (
+	// SignatureHeader is the header carrying the HMAC-SHA256 signature of
+	// the body, "sha256=<hex digest>", keyed by the secret of the webhook.
+	SignatureHeader = "X-Migration-Signature"
+	// EventHeader is the header carrying the outcome of the event.
+	EventHeader = "X-Migration-Event"
+
+	// DefaultTimeout is the default timeout of a delivery attempt.
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxAttempts is the default number of delivery attempts.
+	DefaultMaxAttempts = 3
+	// DefaultBackoff is the default delay before the second delivery
+	// attempt. The delay doubles after every following attempt.
+	DefaultBackoff = time.Second
+)
+
+// Outcome is the state a migration transitioned to.
+type Outcome string
+
+const (
+	OutcomeRunning   Outcome = "Running"
+	OutcomeSucceeded Outcome = "Succeeded"
+	OutcomeFailed    Outcome = "Failed"
+)
+
+// Event is the JSON payload posted to the webhooks when a migration changes
+// state.
+type Event struct {
+	// The name of the storageVersionMigration.
+	Migration string `json:"migration"`
+	// The resource being migrated.
+	Resource migrationv1alpha1.GroupVersionResource `json:"resource"`
+	// The state the migration transitioned to.
+	Outcome Outcome `json:"outcome"`
+	// The number of objects migrated by the run of the migration that
+	// finished. Unset for Running.
+	ObjectsMigrated *int `json:"objectsMigrated,omitempty"`
+	// When the migration first started running. A migration suspended
+	// and resumed keeps its start time.
+	StartTime time.Time `json:"startTime"`
+	// When the migration finished. Unset for Running.
+	CompletionTime *time.Time `json:"completionTime,omitempty"`
+	// How long the migration took since it started running, in seconds,
+	// including its suspensions. Unset for Running.
+	DurationSeconds *float64 `json:"durationSeconds,omitempty"`
+	// Why the migration failed. Only set for Failed.
+	FailureReason string `json:"failureReason,omitempty"`
+}
+
+// Webhook posts the events to an HTTP endpoint.
+type Webhook struct {
+	// URL is the endpoint the events are posted to.
+	URL string
+	// Secret, if not empty, signs the body with HMAC-SHA256 in the
+	// SignatureHeader.
+	Secret []byte
+	// Outcomes are the outcomes of the events posted to the webhook. If
+	// empty, the Succeeded and Failed events are posted.
+	Outcomes []Outcome
+	// Timeout limits every delivery attempt.
+	Timeout time.Duration
+	// MaxAttempts is the number of delivery attempts. The delivery is
+	// retried on network errors, on 429 and on 5xx responses.
+	MaxAttempts int
+	// Backoff is the delay before the second attempt. It doubles after
+	// every following attempt.
+	Backoff time.Duration
+	// Client sends the requests. If nil, http.DefaultClient is used.
+	Client *http.Client
+}
+
+// NewWebhook returns a Webhook posting the events to url, with the default
+// timeout and retries.
+func NewWebhook(url string, secret []byte, outcomes ...Outcome) *Webhook {
+	return &Webhook{
+		URL:         url,
+		Secret:      secret,
+		Outcomes:    outcomes,
+		Timeout:     DefaultTimeout,
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+	}
+}
+
+// Accepts returns true if the events of the outcome are posted to the
+// webhook.
+func (w *Webhook) Accepts(outcome Outcome) bool {
+	if len(w.Outcomes) == 0 {
+		return outcome == OutcomeSucceeded || outcome == OutcomeFailed
+	}
+	for _, o := range w.Outcomes {
+		if o == outcome {
+			return true
+		}
+	}
+	return false
+}
+
+// Sign returns the value of the SignatureHeader of the body.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliver posts the event to the webhook, retrying until an attempt
+// succeeds, the attempts are exhausted, or ctx is done.
+func (w *Webhook) Deliver(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	attempts := w.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := w.Backoff
+	for attempt := 1; ; attempt++ {
+		retry, err := w.post(ctx, e.Outcome, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= attempts {
+			return fmt.Errorf("failed to deliver the %s event of migration %s to %s after %d attempts: %v", e.Outcome, e.Migration, w.URL, attempt, err)
+		}
+		klog.V(2).Infof("attempt %d to deliver the %s event of migration %s to %s failed, retrying in %v: %v", attempt, e.Outcome, e.Migration, w.URL, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes a delivery attempt. It returns whether a failed attempt can be
+// retried.
+func (w *Webhook) post(ctx context.Context, outcome Outcome, body []byte) (bool, error) {
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(outcome))
+	if len(w.Secret) > 0 {
+		req.Header.Set(SignatureHeader, Sign(w.Secret, body))
+	}
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection is reused.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("unexpected status %s", resp.Status)
+	default:
+		return false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+}
+
+// Notifier delivers the events to webhooks in the background, so that
+// slow endpoints do not delay the migrations. A nil Notifier delivers
+// nothing.
+type Notifier struct {
+	webhooks []*Webhook
+}
+
+// NewNotifier returns a Notifier delivering the events to the webhooks.
+func NewNotifier(webhooks ...*Webhook) *Notifier {
+	return &Notifier{webhooks: webhooks}
+}
+
+// Notify delivers the event to the webhooks accepting its outcome, in the
+// background. The delivery errors are logged.
+func (n *Notifier) Notify(e Event) {
+	if n == nil {
+		return
+	}
+	for _, w := range n.webhooks {
+		if !w.Accepts(e.Outcome) {
+			continue
+		}
+		go func(w *Webhook) {
+			if err := w.Deliver(context.Background(), e); err != nil {
+				utilruntime.HandleError(err)
+			}
+		}(w)
+	}
+}
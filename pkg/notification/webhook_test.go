@@ -0,0 +1,251 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+type request struct {
+	header http.Header
+	body   []byte
+}
+
+// recorder is an HTTP handler recording the requests and answering them
+// with the statuses, then with 200.
+type recorder struct {
+	lock     sync.Mutex
+	requests []request
+	statuses []int
+	delay    time.Duration
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.lock.Lock()
+	r.requests = append(r.requests, request{header: req.Header, body: body})
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	r.lock.Unlock()
+	if r.delay > 0 {
+		select {
+		case <-time.After(r.delay):
+		case <-req.Context().Done():
+		}
+	}
+	w.WriteHeader(status)
+}
+
+func (r *recorder) received() []request {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]request(nil), r.requests...)
+}
+
+func succeededEvent() Event {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	completion := start.Add(90 * time.Second)
+	migrated := 42
+	duration := completion.Sub(start).Seconds()
+	return Event{
+		Migration:       "pods-1",
+		Resource:        migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+		Outcome:         OutcomeSucceeded,
+		ObjectsMigrated: &migrated,
+		StartTime:       start,
+		CompletionTime:  &completion,
+		DurationSeconds: &duration,
+	}
+}
+
+func testWebhook(url string) *Webhook {
+	w := NewWebhook(url, []byte("secret"))
+	w.Backoff = time.Millisecond
+	return w
+}
+
+func TestDeliver(t *testing.T) {
+	r := &recorder{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	if err := testWebhook(server.URL).Deliver(context.Background(), succeededEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	requests := r.received()
+	if len(requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(requests))
+	}
+	req := requests[0]
+	if e, a := "application/json", req.header.Get("Content-Type"); e != a {
+		t.Errorf("expected content type %q, got %q", e, a)
+	}
+	if e, a := "Succeeded", req.header.Get(EventHeader); e != a {
+		t.Errorf("expected event %q, got %q", e, a)
+	}
+	if e, a := Sign([]byte("secret"), req.body), req.header.Get(SignatureHeader); e != a {
+		t.Errorf("expected signature %q, got %q", e, a)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string]interface{}{
+		"migration":       "pods-1",
+		"resource":        map[string]interface{}{"version": "v1", "resource": "pods"},
+		"outcome":         "Succeeded",
+		"objectsMigrated": float64(42),
+		"startTime":       "2020-01-01T00:00:00Z",
+		"completionTime":  "2020-01-01T00:01:30Z",
+		"durationSeconds": float64(90),
+	}
+	for k, v := range expected {
+		got, _ := json.Marshal(payload[k])
+		want, _ := json.Marshal(v)
+		if string(got) != string(want) {
+			t.Errorf("expected %s to be %s, got %s", k, want, got)
+		}
+	}
+	if _, ok := payload["failureReason"]; ok {
+		t.Errorf("unexpected failureReason in %s", req.body)
+	}
+}
+
+func TestDeliverUnsigned(t *testing.T) {
+	r := &recorder{}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	w := testWebhook(server.URL)
+	w.Secret = nil
+	if err := w.Deliver(context.Background(), succeededEvent()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s := r.received()[0].header.Get(SignatureHeader); s != "" {
+		t.Errorf("expected no signature, got %q", s)
+	}
+}
+
+func TestDeliverRetries(t *testing.T) {
+	tests := []struct {
+		name             string
+		statuses         []int
+		expectedRequests int
+		expectError      bool
+	}{
+		{
+			name:             "retries server errors",
+			statuses:         []int{http.StatusInternalServerError, http.StatusTooManyRequests},
+			expectedRequests: 3,
+		},
+		{
+			name:             "gives up after the attempts",
+			statuses:         []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway},
+			expectedRequests: 3,
+			expectError:      true,
+		},
+		{
+			name:             "does not retry client errors",
+			statuses:         []int{http.StatusBadRequest},
+			expectedRequests: 1,
+			expectError:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{statuses: tt.statuses}
+			server := httptest.NewServer(r)
+			defer server.Close()
+
+			err := testWebhook(server.URL).Deliver(context.Background(), succeededEvent())
+			if tt.expectError != (err != nil) {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+			if e, a := tt.expectedRequests, len(r.received()); e != a {
+				t.Errorf("expected %d requests, got %d", e, a)
+			}
+		})
+	}
+}
+
+func TestDeliverTimeout(t *testing.T) {
+	r := &recorder{delay: time.Second}
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	w := testWebhook(server.URL)
+	w.Timeout = 10 * time.Millisecond
+	w.MaxAttempts = 2
+	start := time.Now()
+	err := w.Deliver(context.Background(), succeededEvent())
+	if err == nil || !strings.Contains(err.Error(), "after 2 attempts") {
+		t.Errorf("expected the delivery to time out twice, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("expected the attempts to time out, took %v", elapsed)
+	}
+}
+
+func TestAccepts(t *testing.T) {
+	w := NewWebhook("http://example.com", nil)
+	if w.Accepts(OutcomeRunning) || !w.Accepts(OutcomeSucceeded) || !w.Accepts(OutcomeFailed) {
+		t.Errorf("expected the default webhook to accept only Succeeded and Failed")
+	}
+	w = NewWebhook("http://example.com", nil, OutcomeFailed)
+	if w.Accepts(OutcomeRunning) || w.Accepts(OutcomeSucceeded) || !w.Accepts(OutcomeFailed) {
+		t.Errorf("expected the webhook to accept only Failed")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	all, failed := &recorder{}, &recorder{}
+	allServer, failedServer := httptest.NewServer(all), httptest.NewServer(failed)
+	defer allServer.Close()
+	defer failedServer.Close()
+
+	n := NewNotifier(
+		testWebhook(allServer.URL),
+		NewWebhook(failedServer.URL, nil, OutcomeFailed),
+	)
+	n.Notify(succeededEvent())
+	deadline := time.Now().Add(5 * time.Second)
+	for len(all.received()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(all.received()) != 1 {
+		t.Errorf("expected the event to be delivered")
+	}
+	if len(failed.received()) != 0 {
+		t.Errorf("expected the Succeeded event not to be delivered to the Failed webhook")
+	}
+
+	// A nil notifier delivers nothing.
+	var nilNotifier *Notifier
+	nilNotifier.Notify(succeededEvent())
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package options contains the options shared by the commands of the
+// migrator: how to connect to the apiserver, and how to serve metrics and
+// health checks.
+package options
+
+import (
+	flag "github.com/spf13/pflag"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/version"
+)
+
+// ClientOptions configures the clients of the apiserver.
+type ClientOptions struct {
+	// Kubeconfig is the path of the kubeconfig file. If it and Context are
+	// empty, the in-cluster configuration is used.
+	Kubeconfig string
+	// Context is the kubeconfig context to use. If empty, the current
+	// context of the kubeconfig file is used.
+	Context string
+	// Impersonate is the user to impersonate.
+	Impersonate string
+	// ImpersonateGroups are the groups to impersonate.
+	ImpersonateGroups []string
+	// QPS and Burst limit the requests to the apiserver. If zero, the
+	// client-go defaults are used.
+	QPS   float32
+	Burst int
+}
+
+// DefaultClientOptions returns the client options using the in-cluster
+// configuration and the client-go rate limits.
+func DefaultClientOptions() *ClientOptions {
+	return &ClientOptions{}
+}
+
+// AddFlags adds the flags of the client options to fs.
+func (o *ClientOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.Kubeconfig, "kubeconfig", o.Kubeconfig, "absolute path to the kubeconfig file specifying the apiserver instance. If unspecified, fallback to in-cluster configuration")
+	fs.StringVar(&o.Context, "context", o.Context, "the kubeconfig context to use. If unspecified, the current context of the kubeconfig file is used.")
+	fs.StringVar(&o.Impersonate, "as", o.Impersonate, "the user to impersonate when talking with kubernetes apiserver.")
+	fs.StringSliceVar(&o.ImpersonateGroups, "as-group", o.ImpersonateGroups, "the groups to impersonate when talking with kubernetes apiserver. Can be repeated.")
+	fs.Float32Var(&o.QPS, "kube-api-qps", o.QPS, "QPS to use while talking with kubernetes apiserver.")
+	fs.IntVar(&o.Burst, "kube-api-burst", o.Burst, "Burst to use while talking with kubernetes apiserver.")
+}
+
+// Config returns the configuration of the clients of the apiserver,
+// identified by the userAgent and the version of the migrator.
+func (o *ClientOptions) Config(userAgent string) (*rest.Config, error) {
+	var config *rest.Config
+	var err error
+	if o.Kubeconfig == "" && o.Context == "" {
+		config, err = rest.InClusterConfig()
+	} else {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		rules.ExplicitPath = o.Kubeconfig
+		overrides := &clientcmd.ConfigOverrides{CurrentContext: o.Context}
+		config, err = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, overrides).ClientConfig()
+	}
+	if err != nil {
+		return nil, err
+	}
+	config.Impersonate = rest.ImpersonationConfig{
+		UserName: o.Impersonate,
+		Groups:   o.ImpersonateGroups,
+	}
+	if o.QPS != 0 {
+		config.QPS = o.QPS
+	}
+	if o.Burst != 0 {
+		config.Burst = o.Burst
+	}
+	config.UserAgent = userAgent + "/" + version.VERSION
+	return config, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	flag "github.com/spf13/pflag"
+)
+
+const kubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: management
+  cluster:
+    server: https://management.example.com
+- name: workload
+  cluster:
+    server: https://workload.example.com
+users:
+- name: admin
+  user:
+    token: token
+contexts:
+- name: management
+  context:
+    cluster: management
+    user: admin
+- name: workload
+  context:
+    cluster: workload
+    user: admin
+current-context: management
+`
+
+func TestClientOptionsConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(kubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		name           string
+		args           []string
+		expectedHost   string
+		expectedQPS    float32
+		expectedGroups []string
+	}{
+		{
+			name:         "current context",
+			args:         []string{"--kubeconfig", path},
+			expectedHost: "https://management.example.com",
+		},
+		{
+			name:           "explicit context",
+			args:           []string{"--kubeconfig", path, "--context", "workload", "--kube-api-qps", "20", "--as", "migrator", "--as-group", "a,b"},
+			expectedHost:   "https://workload.example.com",
+			expectedQPS:    20,
+			expectedGroups: []string{"a", "b"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			o := DefaultClientOptions()
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			o.AddFlags(fs)
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			config, err := o.Config("test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if e, a := tc.expectedHost, config.Host; e != a {
+				t.Errorf("expected host %q, got %q", e, a)
+			}
+			if e, a := tc.expectedQPS, config.QPS; e != a {
+				t.Errorf("expected QPS %v, got %v", e, a)
+			}
+			if e, a := tc.expectedGroups, config.Impersonate.Groups; !reflect.DeepEqual(e, a) {
+				t.Errorf("expected impersonated groups %v, got %v", e, a)
+			}
+			if !strings.HasPrefix(config.UserAgent, "test/") {
+				t.Errorf("unexpected user agent %q", config.UserAgent)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	flag "github.com/spf13/pflag"
+	"k8s.io/klog/v2"
+)
+
+// ServingOptions configures the endpoints serving the metrics and the health
+// checks.
+type ServingOptions struct {
+	// MetricsBindAddress is the address serving /metrics. If empty, the
+	// metrics are not served.
+	MetricsBindAddress string
+	// HealthBindAddress is the address serving /healthz. If empty, the
+	// health checks are not served. If it is the same as
+	// MetricsBindAddress, both are served by the same server.
+	HealthBindAddress string
+	// TLSCertFile and TLSPrivateKeyFile make the metrics served over TLS.
+	// Both must be set, or neither.
+	TLSCertFile       string
+	TLSPrivateKeyFile string
+}
+
+// DefaultServingOptions returns the serving options serving the metrics and
+// the health checks on the same address.
+func DefaultServingOptions(bindAddress string) *ServingOptions {
+	return &ServingOptions{
+		MetricsBindAddress: bindAddress,
+		HealthBindAddress:  bindAddress,
+	}
+}
+
+// AddFlags adds the flags of the serving options to fs.
+func (o *ServingOptions) AddFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.MetricsBindAddress, "metrics-bind-address", o.MetricsBindAddress, "the address serving /metrics. Empty disables the metrics.")
+	fs.StringVar(&o.HealthBindAddress, "health-bind-address", o.HealthBindAddress, "the address serving /healthz. Empty disables the health checks. If the same as --metrics-bind-address, both are served by the same server.")
+	fs.StringVar(&o.TLSCertFile, "metrics-tls-cert-file", o.TLSCertFile, "the certificate file of the metrics endpoint. If set with --metrics-tls-private-key-file, the metrics are served over TLS.")
+	fs.StringVar(&o.TLSPrivateKeyFile, "metrics-tls-private-key-file", o.TLSPrivateKeyFile, "the private key file of the metrics endpoint.")
+}
+
+// Validate checks the serving options.
+func (o *ServingOptions) Validate() error {
+	if (o.TLSCertFile == "") != (o.TLSPrivateKeyFile == "") {
+		return fmt.Errorf("--metrics-tls-cert-file and --metrics-tls-private-key-file must be set together")
+	}
+	return nil
+}
+
+// Serve serves the metrics and the health checks in the background until
+// ctx is done. A health check served with the metrics is served over TLS
+// too.
+func (o *ServingOptions) Serve(ctx context.Context) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+	metrics := http.NewServeMux()
+	health := metrics
+	if o.HealthBindAddress != o.MetricsBindAddress {
+		health = http.NewServeMux()
+	}
+	metrics.Handle("/metrics", promhttp.Handler())
+	health.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "ok")
+	})
+	if o.MetricsBindAddress != "" {
+		o.serve(ctx, o.MetricsBindAddress, metrics, o.TLSCertFile != "")
+	}
+	if o.HealthBindAddress != "" && o.HealthBindAddress != o.MetricsBindAddress {
+		o.serve(ctx, o.HealthBindAddress, health, false)
+	}
+	return nil
+}
+
+func (o *ServingOptions) serve(ctx context.Context, address string, handler http.Handler, tls bool) {
+	server := &http.Server{Addr: address, Handler: handler}
+	go func() {
+		var err error
+		if tls {
+			err = server.ListenAndServeTLS(o.TLSCertFile, o.TLSPrivateKeyFile)
+		} else {
+			err = server.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			klog.Errorf("failed to serve %s: %v", address, err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package options
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func freeAddress(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func get(address, path string) (int, error) {
+	var err error
+	for i := 0; i < 50; i++ {
+		var resp *http.Response
+		resp, err = http.Get(fmt.Sprintf("http://%s%s", address, path))
+		if err == nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			return resp.StatusCode, nil
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	return 0, err
+}
+
+func TestServingOptionsServe(t *testing.T) {
+	shared := freeAddress(t)
+	metrics, health := freeAddress(t), freeAddress(t)
+	type request struct {
+		address, path string
+		expected      int
+	}
+	for _, tc := range []struct {
+		name     string
+		options  *ServingOptions
+		requests []request
+	}{
+		{
+			name:    "shared address",
+			options: DefaultServingOptions(shared),
+			requests: []request{
+				{shared, "/metrics", http.StatusOK},
+				{shared, "/healthz", http.StatusOK},
+			},
+		},
+		{
+			name:    "separate addresses",
+			options: &ServingOptions{MetricsBindAddress: metrics, HealthBindAddress: health},
+			requests: []request{
+				{metrics, "/metrics", http.StatusOK},
+				{metrics, "/healthz", http.StatusNotFound},
+				{health, "/healthz", http.StatusOK},
+				{health, "/metrics", http.StatusNotFound},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if err := tc.options.Serve(ctx); err != nil {
+				t.Fatal(err)
+			}
+			for _, r := range tc.requests {
+				code, err := get(r.address, r.path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if code != r.expected {
+					t.Errorf("expected %s%s to return %d, got %d", r.address, r.path, r.expected, code)
+				}
+			}
+		})
+	}
+}
+
+func TestServingOptionsValidate(t *testing.T) {
+	o := DefaultServingOptions(":2112")
+	o.TLSCertFile = "tls.crt"
+	if err := o.Validate(); err == nil {
+		t.Errorf("expected an error when only the certificate is set")
+	}
+	o.TLSPrivateKeyFile = "tls.key"
+	if err := o.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,438 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan implements the controller of the MigrationPlans, which
+// creates the storageVersionMigrations of the steps of the plans in the
+// order of their dependencies.
+package plan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
+	migrationinformer "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+const (
+	// createdBy is recorded in the migrations created by the controller.
+	createdBy = "storage-version-migration-plan-controller"
+	// PlanIndex indexes the storageVersionMigrations by the name of the
+	// migration plan they were created for.
+	PlanIndex = "Plan"
+)
+
+// migrationPlanIndexFunc indexes the storageVersionMigrations by their
+// MigrationPlanLabel.
+func migrationPlanIndexFunc(obj interface{}) ([]string, error) {
+	m, ok := obj.(*migrationv1alpha1.StorageVersionMigration)
+	if !ok {
+		return []string{}, fmt.Errorf("expected StorageVersionMigration, got %#v", reflect.TypeOf(obj))
+	}
+	if plan, ok := m.Labels[migrationv1alpha1.MigrationPlanLabel]; ok {
+		return []string{plan}, nil
+	}
+	return []string{}, nil
+}
+
+// Controller creates the storageVersionMigrations of the steps of the
+// MigrationPlans once the steps they depend on have succeeded, and records
+// the progress of the steps in the status of the plans.
+type Controller struct {
+	client            migrationclient.Interface
+	planInformer      cache.SharedIndexInformer
+	migrationInformer cache.SharedIndexInformer
+	// runMigrationInformer is true if the controller owns
+	// migrationInformer.
+	runMigrationInformer bool
+	queue                workqueue.RateLimitingInterface
+}
+
+// NewController creates the Controller. If migrationInformer is not nil, it
+// is an informer of the storageVersionMigrations shared with other
+// controllers, which has not been started yet, and which its owner runs.
+// Otherwise the controller creates and runs its own informer.
+func NewController(c migrationclient.Interface, migrationInformer cache.SharedIndexInformer) (*Controller, error) {
+	pc := &Controller{
+		client:            c,
+		planInformer:      migrationinformer.NewMigrationPlanInformer(c, 0, cache.Indexers{}),
+		migrationInformer: migrationInformer,
+		queue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "migration_plan_controller"),
+	}
+	if pc.migrationInformer == nil {
+		pc.migrationInformer = controller.NewStatusAndResourceIndexedInformer(c)
+		pc.runMigrationInformer = true
+	}
+	if err := controller.AddMigrationIndexers(pc.migrationInformer); err != nil {
+		return nil, err
+	}
+	if _, ok := pc.migrationInformer.GetIndexer().GetIndexers()[PlanIndex]; !ok {
+		if err := pc.migrationInformer.AddIndexers(cache.Indexers{PlanIndex: migrationPlanIndexFunc}); err != nil {
+			return nil, err
+		}
+	}
+	pc.planInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: pc.enqueuePlan,
+		UpdateFunc: func(oldObj, obj interface{}) {
+			pc.enqueuePlan(obj)
+		},
+	})
+	pc.migrationInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: pc.enqueueMigrationPlan,
+		UpdateFunc: func(oldObj, obj interface{}) {
+			pc.enqueueMigrationPlan(obj)
+		},
+		DeleteFunc: pc.enqueueMigrationPlan,
+	})
+	return pc, nil
+}
+
+func (pc *Controller) enqueuePlan(obj interface{}) {
+	p, ok := obj.(*migrationv1alpha1.MigrationPlan)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("expected MigrationPlan, got %#v", reflect.TypeOf(obj)))
+		return
+	}
+	pc.queue.Add(p.Name)
+}
+
+// enqueueMigrationPlan enqueues the plan the migration was created for.
+func (pc *Controller) enqueueMigrationPlan(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+	m, ok := obj.(*migrationv1alpha1.StorageVersionMigration)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("expected StorageVersionMigration, got %#v", reflect.TypeOf(obj)))
+		return
+	}
+	if plan, ok := m.Labels[migrationv1alpha1.MigrationPlanLabel]; ok {
+		pc.queue.Add(plan)
+	}
+}
+
+// Run runs the controller until ctx is done. It returns immediately if the
+// CRD of the MigrationPlans is not installed.
+func (pc *Controller) Run(ctx context.Context) {
+	defer utilruntime.HandleCrash()
+	defer pc.queue.ShutDown()
+	_, err := pc.client.MigrationV1alpha1().MigrationPlans().List(ctx, metav1.ListOptions{Limit: 1})
+	if errors.IsNotFound(err) {
+		klog.Infof("the MigrationPlan CRD is not installed, the migration plans are not processed")
+		return
+	}
+	go pc.planInformer.Run(ctx.Done())
+	if pc.runMigrationInformer {
+		go pc.migrationInformer.Run(ctx.Done())
+	}
+	if !cache.WaitForCacheSync(ctx.Done(), pc.planInformer.HasSynced, pc.migrationInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+		return
+	}
+	wait.UntilWithContext(ctx, pc.work, time.Second)
+}
+
+func (pc *Controller) work(ctx context.Context) {
+	for pc.processNext(ctx) {
+	}
+}
+
+func (pc *Controller) processNext(ctx context.Context) bool {
+	key, quit := pc.queue.Get()
+	if quit {
+		return false
+	}
+	defer pc.queue.Done(key)
+	if err := pc.sync(ctx, key.(string)); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to process migration plan %v: %v", key, err))
+		pc.queue.AddRateLimited(key)
+		return true
+	}
+	pc.queue.Forget(key)
+	return true
+}
+
+// sync creates the migrations of the steps of the plan that can start, and
+// updates the status of the plan.
+func (pc *Controller) sync(ctx context.Context, name string) error {
+	obj, exists, err := pc.planInformer.GetIndexer().GetByKey(name)
+	if err != nil || !exists {
+		return err
+	}
+	plan, ok := obj.(*migrationv1alpha1.MigrationPlan)
+	if !ok {
+		return fmt.Errorf("expected MigrationPlan, got %#v", reflect.TypeOf(obj))
+	}
+	status := migrationv1alpha1.MigrationPlanStatus{
+		ObservedGeneration: plan.Generation,
+		Conditions:         plan.Status.Conditions,
+	}
+	steps, err := OrderSteps(plan.Spec.Steps)
+	if err != nil {
+		status.Steps = plan.Status.Steps
+		status.Conditions = setCondition(status.Conditions, migrationv1alpha1.MigrationPlanInvalid, corev1.ConditionTrue, "InvalidSteps", err.Error())
+		return pc.updateStatus(ctx, plan, status)
+	}
+	status.Conditions = setCondition(status.Conditions, migrationv1alpha1.MigrationPlanInvalid, corev1.ConditionFalse, "ValidSteps", "")
+
+	migrations, err := pc.stepMigrations(plan)
+	if err != nil {
+		return err
+	}
+	phases := map[string]migrationv1alpha1.MigrationPlanStepStatus{}
+	for _, step := range steps {
+		s, err := pc.syncStep(ctx, plan, step, migrations[step.Name], phases)
+		if err != nil {
+			return err
+		}
+		phases[step.Name] = s
+	}
+	var succeeded int
+	var failed []string
+	for _, step := range plan.Spec.Steps {
+		s := phases[step.Name]
+		status.Steps = append(status.Steps, s)
+		switch s.Phase {
+		case migrationv1alpha1.MigrationPlanStepSucceeded:
+			succeeded++
+		case migrationv1alpha1.MigrationPlanStepFailed:
+			failed = append(failed, step.Name)
+		}
+	}
+	progress := fmt.Sprintf("%d of %d steps succeeded", succeeded, len(plan.Spec.Steps))
+	if succeeded == len(plan.Spec.Steps) {
+		status.Conditions = setCondition(status.Conditions, migrationv1alpha1.MigrationPlanSucceeded, corev1.ConditionTrue, "AllStepsSucceeded", progress)
+	} else {
+		status.Conditions = setCondition(status.Conditions, migrationv1alpha1.MigrationPlanSucceeded, corev1.ConditionFalse, "StepsInProgress", progress)
+	}
+	if len(failed) != 0 {
+		status.Conditions = setCondition(status.Conditions, migrationv1alpha1.MigrationPlanFailed, corev1.ConditionTrue, "StepsFailed",
+			fmt.Sprintf("the steps %s failed, delete their migrations to retry them", strings.Join(failed, ", ")))
+	} else {
+		status.Conditions = setCondition(status.Conditions, migrationv1alpha1.MigrationPlanFailed, corev1.ConditionFalse, "NoFailedSteps", "")
+	}
+	return pc.updateStatus(ctx, plan, status)
+}
+
+// syncStep returns the status of the step, given the status of the steps it
+// depends on, and creates its migration if they have all succeeded.
+func (pc *Controller) syncStep(ctx context.Context, plan *migrationv1alpha1.MigrationPlan, step migrationv1alpha1.MigrationPlanStep, m *migrationv1alpha1.StorageVersionMigration, phases map[string]migrationv1alpha1.MigrationPlanStepStatus) (migrationv1alpha1.MigrationPlanStepStatus, error) {
+	s := migrationv1alpha1.MigrationPlanStepStatus{Name: step.Name}
+	if m != nil && controller.IsFinished(m) {
+		s.Migration = m.Name
+		s.Phase, s.Message = migrationPhase(m)
+		return s, nil
+	}
+	// The plan, not the trigger, migrates the resource of an unfinished
+	// step, in the order of the dependencies.
+	if err := pc.supersedeMigrations(ctx, plan, step.Resource); err != nil {
+		return s, err
+	}
+	if m != nil {
+		s.Migration = m.Name
+		s.Phase, s.Message = migrationPhase(m)
+		return s, nil
+	}
+	var failed, waiting []string
+	for _, dep := range step.DependsOn {
+		switch phases[dep].Phase {
+		case migrationv1alpha1.MigrationPlanStepSucceeded:
+		case migrationv1alpha1.MigrationPlanStepFailed:
+			failed = append(failed, dep)
+		default:
+			waiting = append(waiting, dep)
+		}
+	}
+	switch {
+	case len(failed) != 0:
+		s.Phase = migrationv1alpha1.MigrationPlanStepBlocked
+		s.Message = fmt.Sprintf("the steps %s failed", strings.Join(failed, ", "))
+		return s, nil
+	case len(waiting) != 0:
+		s.Phase = migrationv1alpha1.MigrationPlanStepBlocked
+		s.Message = fmt.Sprintf("waiting for the steps %s", strings.Join(waiting, ", "))
+		return s, nil
+	}
+	m, err := pc.createMigration(ctx, plan, step)
+	if err != nil {
+		return s, err
+	}
+	klog.V(2).Infof("migration plan %s: created migration %s for step %s", plan.Name, m.Name, step.Name)
+	s.Migration = m.Name
+	s.Phase = migrationv1alpha1.MigrationPlanStepPending
+	return s, nil
+}
+
+// supersedeMigrations marks the unfinished migrations of the resource as
+// superseded by the plan, like the trigger does for the migrations it
+// launches. The migrations of the plans are left alone.
+func (pc *Controller) supersedeMigrations(ctx context.Context, plan *migrationv1alpha1.MigrationPlan, resource migrationv1alpha1.GroupVersionResource) error {
+	migrations, err := controller.UnfinishedMigrations(pc.migrationInformer.GetIndexer(), resource)
+	if err != nil {
+		return err
+	}
+	for _, other := range migrations {
+		if _, ok := other.Labels[migrationv1alpha1.MigrationPlanLabel]; ok {
+			continue
+		}
+		klog.V(2).Infof("migration plan %s supersedes migration %s", plan.Name, other.Name)
+		message := fmt.Sprintf("superseded by migration plan %s", plan.Name)
+		if err := controller.SupersedeMigration(ctx, pc.client, other, migrationv1alpha1.MigrationReasonMigrationPlan, message); err != nil {
+			return fmt.Errorf("failed to supersede migration %s: %v", other.Name, err)
+		}
+	}
+	return nil
+}
+
+// migrationPhase returns the phase of a step whose migration is m.
+func migrationPhase(m *migrationv1alpha1.StorageVersionMigration) (migrationv1alpha1.MigrationPlanStepPhase, string) {
+	if c := controller.GetCondition(m, migrationv1alpha1.MigrationSucceeded); c != nil {
+		return migrationv1alpha1.MigrationPlanStepSucceeded, ""
+	}
+	if c := controller.GetCondition(m, migrationv1alpha1.MigrationFailed); c != nil {
+		return migrationv1alpha1.MigrationPlanStepFailed, c.Message
+	}
+	if c := controller.GetCondition(m, migrationv1alpha1.MigrationWaiting); c != nil {
+		return migrationv1alpha1.MigrationPlanStepPending, c.Message
+	}
+	if controller.HasCondition(m, migrationv1alpha1.MigrationRunning) {
+		return migrationv1alpha1.MigrationPlanStepRunning, ""
+	}
+	return migrationv1alpha1.MigrationPlanStepPending, ""
+}
+
+// stepMigrations returns the latest migration of every step of the plan.
+// The migrations that are superseded, or that do not migrate the resource
+// of their step anymore, are ignored, so that the step is migrated again.
+func (pc *Controller) stepMigrations(plan *migrationv1alpha1.MigrationPlan) (map[string]*migrationv1alpha1.StorageVersionMigration, error) {
+	objs, err := pc.migrationInformer.GetIndexer().ByIndex(PlanIndex, plan.Name)
+	if err != nil {
+		return nil, err
+	}
+	resources := map[string]migrationv1alpha1.GroupVersionResource{}
+	for _, step := range plan.Spec.Steps {
+		resources[step.Name] = step.Resource
+	}
+	var migrations []*migrationv1alpha1.StorageVersionMigration
+	for _, obj := range objs {
+		m, ok := obj.(*migrationv1alpha1.StorageVersionMigration)
+		if !ok {
+			return nil, fmt.Errorf("expected StorageVersionMigration, got %#v", reflect.TypeOf(obj))
+		}
+		// A plan recreated with the same name does not reuse the
+		// migrations of the previous one.
+		if !metav1.IsControlledBy(m, plan) {
+			continue
+		}
+		r, ok := resources[m.Labels[migrationv1alpha1.MigrationPlanStepLabel]]
+		if !ok || r != m.Spec.Resource || controller.HasCondition(m, migrationv1alpha1.MigrationSuperseded) {
+			continue
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].CreationTimestamp.Before(&migrations[j].CreationTimestamp)
+	})
+	latest := map[string]*migrationv1alpha1.StorageVersionMigration{}
+	for _, m := range migrations {
+		latest[m.Labels[migrationv1alpha1.MigrationPlanStepLabel]] = m
+	}
+	return latest, nil
+}
+
+func (pc *Controller) createMigration(ctx context.Context, plan *migrationv1alpha1.MigrationPlan, step migrationv1alpha1.MigrationPlanStep) (*migrationv1alpha1.StorageVersionMigration, error) {
+	m := &migrationv1alpha1.StorageVersionMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: plan.Name + "-" + step.Name + "-",
+			Labels: map[string]string{
+				migrationv1alpha1.MigrationPlanLabel:     plan.Name,
+				migrationv1alpha1.MigrationPlanStepLabel: step.Name,
+			},
+			Annotations: map[string]string{
+				migrationv1alpha1.MigrationReasonAnnotation:    migrationv1alpha1.MigrationReasonMigrationPlan,
+				migrationv1alpha1.MigrationMessageAnnotation:   fmt.Sprintf("step %s of the migration plan %s", step.Name, plan.Name),
+				migrationv1alpha1.MigrationCreatedByAnnotation: createdBy,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(plan, migrationv1alpha1.SchemeGroupVersion.WithKind("MigrationPlan")),
+			},
+		},
+		Spec: migrationv1alpha1.StorageVersionMigrationSpec{
+			Resource: step.Resource,
+			Priority: plan.Spec.Priority,
+		},
+	}
+	// The owner reference does not block the deletion of the plan, which
+	// would require the permission to update its finalizers.
+	m.OwnerReferences[0].BlockOwnerDeletion = nil
+	return pc.client.MigrationV1alpha1().StorageVersionMigrations().Create(ctx, m, metav1.CreateOptions{})
+}
+
+// updateStatus updates the status of the plan if it changed.
+func (pc *Controller) updateStatus(ctx context.Context, plan *migrationv1alpha1.MigrationPlan, status migrationv1alpha1.MigrationPlanStatus) error {
+	if equality.Semantic.DeepEqual(plan.Status, status) {
+		return nil
+	}
+	plan = plan.DeepCopy()
+	plan.Status = status
+	_, err := pc.client.MigrationV1alpha1().MigrationPlans().UpdateStatus(ctx, plan, metav1.UpdateOptions{})
+	return err
+}
+
+// setCondition sets the condition of the type in the conditions, keeping
+// its LastUpdateTime if its status, reason and message do not change.
+func setCondition(conditions []migrationv1alpha1.MigrationPlanCondition, conditionType migrationv1alpha1.MigrationPlanConditionType, status corev1.ConditionStatus, reason, message string) []migrationv1alpha1.MigrationPlanCondition {
+	condition := migrationv1alpha1.MigrationPlanCondition{
+		Type:           conditionType,
+		Status:         status,
+		LastUpdateTime: metav1.Now(),
+		Reason:         reason,
+		Message:        message,
+	}
+	updated := make([]migrationv1alpha1.MigrationPlanCondition, 0, len(conditions)+1)
+	found := false
+	for _, c := range conditions {
+		if c.Type != conditionType {
+			updated = append(updated, c)
+			continue
+		}
+		found = true
+		if c.Status == status && c.Reason == reason && c.Message == message {
+			updated = append(updated, c)
+		} else {
+			updated = append(updated, condition)
+		}
+	}
+	if !found {
+		updated = append(updated, condition)
+	}
+	return updated
+}
@@ -0,0 +1,356 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	core "k8s.io/client-go/testing"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+func newPlan(steps ...migrationv1alpha1.MigrationPlanStep) *migrationv1alpha1.MigrationPlan {
+	return &migrationv1alpha1.MigrationPlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade", UID: "upgrade-uid", Generation: 2},
+		Spec: migrationv1alpha1.MigrationPlanSpec{
+			Steps:    steps,
+			Priority: 10,
+		},
+	}
+}
+
+// newTestController returns a controller whose caches contain the plan and
+// the migrations.
+func newTestController(t *testing.T, plan *migrationv1alpha1.MigrationPlan, migrations ...*migrationv1alpha1.StorageVersionMigration) (*Controller, *fake.Clientset) {
+	objects := []runtime.Object{plan}
+	for _, m := range migrations {
+		objects = append(objects, m)
+	}
+	client := fake.NewSimpleClientset(objects...)
+	// The fake client does not generate names.
+	generated := 0
+	client.PrependReactor("create", "storageversionmigrations", func(action core.Action) (bool, runtime.Object, error) {
+		m := action.(core.CreateAction).GetObject().(*migrationv1alpha1.StorageVersionMigration)
+		if m.Name == "" {
+			generated++
+			m.Name = fmt.Sprintf("%s%d", m.GenerateName, generated)
+		}
+		return false, nil, nil
+	})
+	pc, err := NewController(client, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pc.planInformer.GetIndexer().Add(plan); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range migrations {
+		if err := pc.migrationInformer.GetIndexer().Add(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return pc, client
+}
+
+// stepMigration returns the migration of the step of the plan, with the
+// condition if it is not empty.
+func stepMigration(plan *migrationv1alpha1.MigrationPlan, step migrationv1alpha1.MigrationPlanStep, condition migrationv1alpha1.MigrationConditionType) *migrationv1alpha1.StorageVersionMigration {
+	m := &migrationv1alpha1.StorageVersionMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: plan.Name + "-" + step.Name,
+			Labels: map[string]string{
+				migrationv1alpha1.MigrationPlanLabel:     plan.Name,
+				migrationv1alpha1.MigrationPlanStepLabel: step.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(plan, migrationv1alpha1.SchemeGroupVersion.WithKind("MigrationPlan")),
+			},
+		},
+		Spec: migrationv1alpha1.StorageVersionMigrationSpec{Resource: step.Resource},
+	}
+	if condition != "" {
+		m.Status.Conditions = []migrationv1alpha1.MigrationCondition{{Type: condition, Status: corev1.ConditionTrue, Message: "message"}}
+	}
+	return m
+}
+
+// createdMigrations returns the migrations created by the controller.
+func createdMigrations(client *fake.Clientset) []*migrationv1alpha1.StorageVersionMigration {
+	var created []*migrationv1alpha1.StorageVersionMigration
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "create" && action.GetResource().Resource == "storageversionmigrations" {
+			created = append(created, action.(core.CreateAction).GetObject().(*migrationv1alpha1.StorageVersionMigration))
+		}
+	}
+	return created
+}
+
+func getPlan(t *testing.T, client *fake.Clientset) *migrationv1alpha1.MigrationPlan {
+	plan, err := client.MigrationV1alpha1().MigrationPlans().Get(context.TODO(), "upgrade", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plan
+}
+
+func getPlanCondition(plan *migrationv1alpha1.MigrationPlan, conditionType migrationv1alpha1.MigrationPlanConditionType) *migrationv1alpha1.MigrationPlanCondition {
+	for i := range plan.Status.Conditions {
+		if plan.Status.Conditions[i].Type == conditionType {
+			return &plan.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+type stepPhase struct {
+	name  string
+	phase migrationv1alpha1.MigrationPlanStepPhase
+}
+
+func stepPhases(plan *migrationv1alpha1.MigrationPlan) []stepPhase {
+	var phases []stepPhase
+	for _, s := range plan.Status.Steps {
+		phases = append(phases, stepPhase{s.Name, s.Phase})
+	}
+	return phases
+}
+
+func TestSyncCreatesMigrationsInOrder(t *testing.T) {
+	plan := newPlan(step("widgets", "crds"), step("crds"), step("pods"))
+	pc, client := newTestController(t, plan)
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+
+	created := createdMigrations(client)
+	if len(created) != 2 {
+		t.Fatalf("expected 2 migrations to be created, got %d", len(created))
+	}
+	for i, name := range []string{"crds", "pods"} {
+		m := created[i]
+		if m.Spec.Resource.Resource != name {
+			t.Errorf("expected the migration of %s, got %v", name, m.Spec.Resource)
+		}
+		if m.Labels[migrationv1alpha1.MigrationPlanLabel] != plan.Name || m.Labels[migrationv1alpha1.MigrationPlanStepLabel] != name {
+			t.Errorf("unexpected labels %v", m.Labels)
+		}
+		if m.Annotations[migrationv1alpha1.MigrationReasonAnnotation] != migrationv1alpha1.MigrationReasonMigrationPlan {
+			t.Errorf("unexpected annotations %v", m.Annotations)
+		}
+		if !metav1.IsControlledBy(m, plan) {
+			t.Errorf("expected the migration to be controlled by the plan, got %v", m.OwnerReferences)
+		}
+		if m.Spec.Priority != plan.Spec.Priority {
+			t.Errorf("expected priority %d, got %d", plan.Spec.Priority, m.Spec.Priority)
+		}
+	}
+
+	updated := getPlan(t, client)
+	expected := []stepPhase{
+		{"widgets", migrationv1alpha1.MigrationPlanStepBlocked},
+		{"crds", migrationv1alpha1.MigrationPlanStepPending},
+		{"pods", migrationv1alpha1.MigrationPlanStepPending},
+	}
+	if phases := stepPhases(updated); !reflect.DeepEqual(phases, expected) {
+		t.Errorf("expected steps %v, got %v", expected, phases)
+	}
+	if e, a := "waiting for the steps crds", updated.Status.Steps[0].Message; e != a {
+		t.Errorf("expected message %q, got %q", e, a)
+	}
+	if updated.Status.ObservedGeneration != plan.Generation {
+		t.Errorf("expected observed generation %d, got %d", plan.Generation, updated.Status.ObservedGeneration)
+	}
+	if c := getPlanCondition(updated, migrationv1alpha1.MigrationPlanSucceeded); c == nil || c.Status != corev1.ConditionFalse || c.Message != "0 of 3 steps succeeded" {
+		t.Errorf("unexpected Succeeded condition %v", c)
+	}
+}
+
+func TestSyncStartsDependentSteps(t *testing.T) {
+	crds, widgets := step("crds"), step("widgets", "crds")
+	plan := newPlan(crds, widgets)
+	pc, client := newTestController(t, plan, stepMigration(plan, crds, migrationv1alpha1.MigrationSucceeded))
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+	created := createdMigrations(client)
+	if len(created) != 1 || created[0].Spec.Resource.Resource != "widgets" {
+		t.Fatalf("expected the migration of widgets to be created, got %v", created)
+	}
+	updated := getPlan(t, client)
+	expected := []stepPhase{
+		{"crds", migrationv1alpha1.MigrationPlanStepSucceeded},
+		{"widgets", migrationv1alpha1.MigrationPlanStepPending},
+	}
+	if phases := stepPhases(updated); !reflect.DeepEqual(phases, expected) {
+		t.Errorf("expected steps %v, got %v", expected, phases)
+	}
+}
+
+func TestSyncSupersedesOlderMigrations(t *testing.T) {
+	crds, widgets := step("crds"), step("widgets", "crds")
+	plan := newPlan(crds, widgets)
+	// Migrations of crds and widgets created by the trigger, and the
+	// migration of crds of another plan.
+	trigger := func(name string, s migrationv1alpha1.MigrationPlanStep) *migrationv1alpha1.StorageVersionMigration {
+		return &migrationv1alpha1.StorageVersionMigration{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       migrationv1alpha1.StorageVersionMigrationSpec{Resource: s.Resource},
+		}
+	}
+	other := stepMigration(newPlan(crds), crds, "")
+	other.Name, other.Labels[migrationv1alpha1.MigrationPlanLabel] = "other-crds", "other"
+	pc, client := newTestController(t, plan, trigger("trigger-crds", crds), trigger("trigger-widgets", widgets), other)
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+	superseded := map[string]string{}
+	for _, action := range client.Actions() {
+		if u, ok := action.(core.UpdateAction); ok && u.GetSubresource() == "status" && u.GetResource().Resource == "storageversionmigrations" {
+			m := u.GetObject().(*migrationv1alpha1.StorageVersionMigration)
+			if c := controller.GetCondition(m, migrationv1alpha1.MigrationSuperseded); c != nil {
+				superseded[m.Name] = c.Message
+			}
+		}
+	}
+	// The migration of widgets is superseded too, so that widgets are not
+	// migrated before crds.
+	expected := map[string]string{
+		"trigger-crds":    "superseded by migration plan upgrade",
+		"trigger-widgets": "superseded by migration plan upgrade",
+	}
+	if !reflect.DeepEqual(superseded, expected) {
+		t.Errorf("expected %v to be superseded, got %v", expected, superseded)
+	}
+}
+
+func TestSyncSucceeded(t *testing.T) {
+	crds, widgets := step("crds"), step("widgets", "crds")
+	plan := newPlan(crds, widgets)
+	pc, client := newTestController(t, plan,
+		stepMigration(plan, crds, migrationv1alpha1.MigrationSucceeded),
+		stepMigration(plan, widgets, migrationv1alpha1.MigrationSucceeded))
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+	if created := createdMigrations(client); len(created) != 0 {
+		t.Errorf("expected no migration to be created, got %v", created)
+	}
+	updated := getPlan(t, client)
+	if c := getPlanCondition(updated, migrationv1alpha1.MigrationPlanSucceeded); c == nil || c.Status != corev1.ConditionTrue {
+		t.Errorf("unexpected Succeeded condition %v", c)
+	}
+
+	// The status does not change when nothing happened.
+	client.ClearActions()
+	if err := pc.planInformer.GetIndexer().Update(updated); err != nil {
+		t.Fatal(err)
+	}
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Errorf("expected no action, got %v", actions)
+	}
+}
+
+func TestSyncFailedStepBlocksDependents(t *testing.T) {
+	crds, widgets, pods := step("crds"), step("widgets", "crds"), step("pods")
+	plan := newPlan(crds, widgets, pods)
+	pc, client := newTestController(t, plan,
+		stepMigration(plan, crds, migrationv1alpha1.MigrationFailed),
+		stepMigration(plan, pods, migrationv1alpha1.MigrationRunning))
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+	if created := createdMigrations(client); len(created) != 0 {
+		t.Errorf("expected no migration to be created, got %v", created)
+	}
+	updated := getPlan(t, client)
+	expected := []stepPhase{
+		{"crds", migrationv1alpha1.MigrationPlanStepFailed},
+		{"widgets", migrationv1alpha1.MigrationPlanStepBlocked},
+		{"pods", migrationv1alpha1.MigrationPlanStepRunning},
+	}
+	if phases := stepPhases(updated); !reflect.DeepEqual(phases, expected) {
+		t.Errorf("expected steps %v, got %v", expected, phases)
+	}
+	if e, a := "the steps crds failed", updated.Status.Steps[1].Message; e != a {
+		t.Errorf("expected message %q, got %q", e, a)
+	}
+	if c := getPlanCondition(updated, migrationv1alpha1.MigrationPlanFailed); c == nil || c.Status != corev1.ConditionTrue {
+		t.Errorf("unexpected Failed condition %v", c)
+	}
+}
+
+func TestSyncIgnoresStaleMigrations(t *testing.T) {
+	crds := step("crds")
+	plan := newPlan(crds)
+	superseded := stepMigration(plan, crds, migrationv1alpha1.MigrationSuperseded)
+	// A migration of a plan deleted and recreated with the same name.
+	orphan := stepMigration(plan, crds, migrationv1alpha1.MigrationSucceeded)
+	orphan.Name = "orphan"
+	orphan.OwnerReferences[0].UID = "previous-uid"
+	pc, client := newTestController(t, plan, superseded, orphan)
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+	if created := createdMigrations(client); len(created) != 1 {
+		t.Errorf("expected the migration of crds to be created again, got %v", created)
+	}
+}
+
+func TestSyncInvalidPlan(t *testing.T) {
+	plan := newPlan(step("a", "b"), step("b", "a"))
+	pc, client := newTestController(t, plan)
+	if err := pc.sync(context.TODO(), plan.Name); err != nil {
+		t.Fatal(err)
+	}
+	if created := createdMigrations(client); len(created) != 0 {
+		t.Errorf("expected no migration to be created, got %v", created)
+	}
+	updated := getPlan(t, client)
+	c := getPlanCondition(updated, migrationv1alpha1.MigrationPlanInvalid)
+	if c == nil || c.Status != corev1.ConditionTrue || c.Message != "the dependencies of the steps a, b have a cycle" {
+		t.Errorf("unexpected Invalid condition %v", c)
+	}
+}
+
+func TestMigrationPhase(t *testing.T) {
+	m := &migrationv1alpha1.StorageVersionMigration{}
+	if phase, _ := migrationPhase(m); phase != migrationv1alpha1.MigrationPlanStepPending {
+		t.Errorf("expected a new migration to be pending, got %v", phase)
+	}
+	m.Status.Conditions = []migrationv1alpha1.MigrationCondition{
+		{Type: migrationv1alpha1.MigrationRunning, Status: corev1.ConditionTrue},
+		{Type: migrationv1alpha1.MigrationWaiting, Status: corev1.ConditionTrue, Message: "waiting"},
+	}
+	if !controller.HasCondition(m, migrationv1alpha1.MigrationRunning) {
+		t.Fatalf("expected the migration to be running")
+	}
+	if phase, message := migrationPhase(m); phase != migrationv1alpha1.MigrationPlanStepPending || message != "waiting" {
+		t.Errorf("expected a waiting migration to be pending, got %v %q", phase, message)
+	}
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+// OrderSteps returns the steps sorted so that every step comes after the
+// steps it depends on. The steps without dependencies between them keep
+// their order. It returns an error if the names of the steps are empty or
+// duplicated, if a step depends on an unknown step, or if the dependencies
+// have a cycle.
+func OrderSteps(steps []migrationv1alpha1.MigrationPlanStep) ([]migrationv1alpha1.MigrationPlanStep, error) {
+	index := map[string]int{}
+	for i, step := range steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("step %d has no name", i)
+		}
+		if _, ok := index[step.Name]; ok {
+			return nil, fmt.Errorf("step %q is defined more than once", step.Name)
+		}
+		index[step.Name] = i
+	}
+	// dependents maps the steps to the steps depending on them.
+	dependents := make([][]int, len(steps))
+	remaining := make([]int, len(steps))
+	for i, step := range steps {
+		for _, dep := range step.DependsOn {
+			j, ok := index[dep]
+			if !ok {
+				return nil, fmt.Errorf("step %q depends on the unknown step %q", step.Name, dep)
+			}
+			if j == i {
+				return nil, fmt.Errorf("step %q depends on itself", step.Name)
+			}
+			dependents[j] = append(dependents[j], i)
+			remaining[i]++
+		}
+	}
+	ordered := make([]migrationv1alpha1.MigrationPlanStep, 0, len(steps))
+	done := make([]bool, len(steps))
+	for len(ordered) < len(steps) {
+		// The first step, in the order of the spec, whose dependencies
+		// are all ordered.
+		next := -1
+		for i := range steps {
+			if !done[i] && remaining[i] == 0 {
+				next = i
+				break
+			}
+		}
+		if next == -1 {
+			var cycle []string
+			for i, step := range steps {
+				if !done[i] {
+					cycle = append(cycle, step.Name)
+				}
+			}
+			return nil, fmt.Errorf("the dependencies of the steps %s have a cycle", strings.Join(cycle, ", "))
+		}
+		done[next] = true
+		ordered = append(ordered, steps[next])
+		for _, i := range dependents[next] {
+			remaining[i]--
+		}
+	}
+	return ordered, nil
+}
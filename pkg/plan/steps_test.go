@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+)
+
+func step(name string, dependsOn ...string) migrationv1alpha1.MigrationPlanStep {
+	return migrationv1alpha1.MigrationPlanStep{
+		Name:      name,
+		Resource:  migrationv1alpha1.GroupVersionResource{Version: "v1", Resource: name},
+		DependsOn: dependsOn,
+	}
+}
+
+func stepNames(steps []migrationv1alpha1.MigrationPlanStep) []string {
+	var names []string
+	for _, s := range steps {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func TestOrderSteps(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		steps    []migrationv1alpha1.MigrationPlanStep
+		expected []string
+		err      string
+	}{
+		{
+			name:     "no dependencies",
+			steps:    []migrationv1alpha1.MigrationPlanStep{step("c"), step("a"), step("b")},
+			expected: []string{"c", "a", "b"},
+		},
+		{
+			name:     "dependencies",
+			steps:    []migrationv1alpha1.MigrationPlanStep{step("c", "b"), step("a"), step("b", "a"), step("d")},
+			expected: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "diamond",
+			steps:    []migrationv1alpha1.MigrationPlanStep{step("d", "b", "c"), step("c", "a"), step("b", "a"), step("a")},
+			expected: []string{"a", "c", "b", "d"},
+		},
+		{
+			name:  "empty name",
+			steps: []migrationv1alpha1.MigrationPlanStep{step("a"), step("")},
+			err:   "step 1 has no name",
+		},
+		{
+			name:  "duplicate",
+			steps: []migrationv1alpha1.MigrationPlanStep{step("a"), step("a")},
+			err:   `step "a" is defined more than once`,
+		},
+		{
+			name:  "unknown dependency",
+			steps: []migrationv1alpha1.MigrationPlanStep{step("a", "b")},
+			err:   `step "a" depends on the unknown step "b"`,
+		},
+		{
+			name:  "self dependency",
+			steps: []migrationv1alpha1.MigrationPlanStep{step("a", "a")},
+			err:   `step "a" depends on itself`,
+		},
+		{
+			name:  "cycle",
+			steps: []migrationv1alpha1.MigrationPlanStep{step("a"), step("b", "c"), step("c", "b")},
+			err:   "the dependencies of the steps b, c have a cycle",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ordered, err := OrderSteps(tc.steps)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := stepNames(ordered); !reflect.DeepEqual(names, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package rbac generates the least-privilege ClusterRole of the migrator,
+// for the clusters forbidding the wildcard roles such as cluster-admin.
+package rbac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/yaml"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// DefaultRoleName is the default name of the generated ClusterRole.
+const DefaultRoleName = "storage-version-migration-migrator"
+
+// migratorRules are the permissions the migrator needs whatever the migrated
+// resources: to watch the migrations and update their status, to read the
+// policies, to review its own access and to elect a leader. The ConfigMap
+// audit sink is optional, the permissions it needs are not included.
+var migratorRules = []rbacv1.PolicyRule{
+	{
+		APIGroups: []string{migrationv1alpha1.GroupName},
+		Resources: []string{"storageversionmigrations"},
+		Verbs:     []string{"get", "list", "watch"},
+	},
+	{
+		APIGroups: []string{migrationv1alpha1.GroupName},
+		Resources: []string{"storageversionmigrations/status"},
+		Verbs:     []string{"update", "patch"},
+	},
+	{
+		APIGroups: []string{migrationv1alpha1.GroupName},
+		Resources: []string{"migrationpolicies"},
+		Verbs:     []string{"get", "list", "watch"},
+	},
+	{
+		APIGroups: []string{"authorization.k8s.io"},
+		Resources: []string{"selfsubjectaccessreviews"},
+		Verbs:     []string{"create"},
+	},
+	{
+		APIGroups: []string{"coordination.k8s.io"},
+		Resources: []string{"leases"},
+		Verbs:     []string{"get", "create", "update"},
+	},
+}
+
+// MigratableResources returns the resources of the discovery document that
+// the migrator can migrate, and that filter allows, sorted by group and
+// resource. A resource is migratable if it reports a storage version hash
+// and supports controller.MigrationVerbs. The subresources are ignored.
+//
+// An error is returned if the discovery of any group failed, since a role
+// generated from a partial discovery document would be missing resources.
+func MigratableResources(client discovery.DiscoveryInterface, filter *controller.ResourceFilter) ([]schema.GroupResource, error) {
+	lists, err := discovery.ServerPreferredResources(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover the resources: %v", err)
+	}
+	found := map[schema.GroupResource]bool{}
+	for _, l := range lists {
+		gv, err := schema.ParseGroupVersion(l.GroupVersion)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected group version %q: %v", l.GroupVersion, err)
+		}
+		for _, r := range l.APIResources {
+			if strings.Contains(r.Name, "/") || r.StorageVersionHash == "" {
+				continue
+			}
+			if !sets.NewString(r.Verbs...).HasAll(controller.MigrationVerbs...) {
+				continue
+			}
+			group := r.Group
+			if group == "" {
+				group = gv.Group
+			}
+			if filter != nil {
+				if ok, _ := filter.Allowed(group, r.Name); !ok {
+					continue
+				}
+			}
+			found[schema.GroupResource{Group: group, Resource: r.Name}] = true
+		}
+	}
+	resources := make([]schema.GroupResource, 0, len(found))
+	for gr := range found {
+		resources = append(resources, gr)
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		if resources[i].Group != resources[j].Group {
+			return resources[i].Group < resources[j].Group
+		}
+		return resources[i].Resource < resources[j].Resource
+	})
+	return resources, nil
+}
+
+// MigratorClusterRole returns the ClusterRole named name granting the
+// migrator controller.MigrationVerbs on the resources, with one rule per
+// group, and the permissions it needs to process the migrations.
+func MigratorClusterRole(name string, resources []schema.GroupResource) *rbacv1.ClusterRole {
+	byGroup := map[string][]string{}
+	var groups []string
+	for _, gr := range resources {
+		if _, ok := byGroup[gr.Group]; !ok {
+			groups = append(groups, gr.Group)
+		}
+		byGroup[gr.Group] = append(byGroup[gr.Group], gr.Resource)
+	}
+	sort.Strings(groups)
+	var rules []rbacv1.PolicyRule
+	for _, rule := range migratorRules {
+		rules = append(rules, *rule.DeepCopy())
+	}
+	for _, group := range groups {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{group},
+			Resources: sets.NewString(byGroup[group]...).List(),
+			Verbs:     append([]string(nil), controller.MigrationVerbs...),
+		})
+	}
+	return &rbacv1.ClusterRole{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: rbacv1.SchemeGroupVersion.String(),
+			Kind:       "ClusterRole",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rules:      rules,
+	}
+}
+
+// MarshalClusterRole returns the YAML manifest of role.
+func MarshalClusterRole(role *rbacv1.ClusterRole) ([]byte, error) {
+	return yaml.Marshal(role)
+}
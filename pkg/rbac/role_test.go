@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rbac
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+var allVerbs = []string{"create", "delete", "get", "list", "patch", "update", "watch"}
+
+func newDiscovery() *kubefake.Clientset {
+	client := kubefake.NewSimpleClientset()
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Verbs: allVerbs, StorageVersionHash: "a"},
+				{Name: "pods/status", Verbs: []string{"get", "update"}, StorageVersionHash: "a"},
+				{Name: "secrets", Verbs: allVerbs, StorageVersionHash: "b"},
+				{Name: "events", Verbs: allVerbs, StorageVersionHash: "c"},
+				{Name: "bindings", Verbs: []string{"create"}, StorageVersionHash: "d"},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "deployments", Verbs: allVerbs, StorageVersionHash: "e"},
+				{Name: "daemonsets", Verbs: allVerbs, StorageVersionHash: "f"},
+			},
+		},
+		{
+			GroupVersion: "metrics.k8s.io/v1beta1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Verbs: []string{"get", "list"}},
+			},
+		},
+	}
+	return client
+}
+
+func TestMigratableResources(t *testing.T) {
+	filter := controller.NewResourceFilter(controller.ResourceRules{ExcludeResources: []string{"events"}})
+	resources, err := MigratableResources(newDiscovery().Discovery(), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []schema.GroupResource{
+		{Resource: "pods"},
+		{Resource: "secrets"},
+		{Group: "apps", Resource: "daemonsets"},
+		{Group: "apps", Resource: "deployments"},
+	}
+	if !reflect.DeepEqual(resources, expected) {
+		t.Errorf("expected %v, got %v", expected, resources)
+	}
+}
+
+func TestMigratorClusterRole(t *testing.T) {
+	role := MigratorClusterRole(DefaultRoleName, []schema.GroupResource{
+		{Group: "apps", Resource: "deployments"},
+		{Resource: "secrets"},
+		{Group: "apps", Resource: "daemonsets"},
+		{Resource: "pods"},
+	})
+	if role.Name != DefaultRoleName || role.Kind != "ClusterRole" {
+		t.Errorf("unexpected role %s %s", role.Kind, role.Name)
+	}
+	if len(role.Rules) != len(migratorRules)+2 {
+		t.Fatalf("expected %d rules, got %+v", len(migratorRules)+2, role.Rules)
+	}
+	expected := []rbacv1.PolicyRule{
+		{APIGroups: []string{""}, Resources: []string{"pods", "secrets"}, Verbs: controller.MigrationVerbs},
+		{APIGroups: []string{"apps"}, Resources: []string{"daemonsets", "deployments"}, Verbs: controller.MigrationVerbs},
+	}
+	if got := role.Rules[len(migratorRules):]; !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+	for _, rule := range role.Rules {
+		for _, s := range append(append(rule.APIGroups, rule.Resources...), rule.Verbs...) {
+			if strings.Contains(s, "*") {
+				t.Errorf("unexpected wildcard in %+v", rule)
+			}
+		}
+	}
+}
+
+func TestMarshalClusterRole(t *testing.T) {
+	role := MigratorClusterRole(DefaultRoleName, []schema.GroupResource{{Resource: "pods"}})
+	data, err := MarshalClusterRole(role)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &rbacv1.ClusterRole{}
+	if err := yaml.UnmarshalStrict(data, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, role) {
+		t.Errorf("expected the manifest to decode to %+v, got %+v", role, decoded)
+	}
+}
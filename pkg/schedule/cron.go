@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedule parses cron schedules, and computes when the maintenance
+// windows they start are open.
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule is a cron schedule of five fields: minute, hour, day of month,
+// month and day of week. A field is "*", a value, a range "a-b", or a list
+// of them separated by commas, each optionally followed by a step "/n".
+// Sunday is 0 or 7. As in cron, if both the day of month and the day of
+// week are restricted, a day matches if either matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domStar and dowStar are true if the field is "*".
+	domStar, dowStar bool
+}
+
+type bounds struct {
+	name     string
+	min, max int
+}
+
+var (
+	minutes = bounds{"minute", 0, 59}
+	hours   = bounds{"hour", 0, 23}
+	doms    = bounds{"day of month", 1, 31}
+	months  = bounds{"month", 1, 12}
+	dows    = bounds{"day of week", 0, 7}
+)
+
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Parse parses a cron schedule, or one of the descriptors @yearly,
+// @annually, @monthly, @weekly, @daily, @midnight and @hourly.
+func Parse(spec string) (*Schedule, error) {
+	if d, ok := descriptors[strings.TrimSpace(spec)]; ok {
+		spec = d
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+	s := &Schedule{
+		domStar: fields[2] == "*",
+		dowStar: fields[4] == "*",
+	}
+	var err error
+	for _, f := range []struct {
+		field string
+		b     bounds
+		bits  *uint64
+	}{
+		{fields[0], minutes, &s.minute},
+		{fields[1], hours, &s.hour},
+		{fields[2], doms, &s.dom},
+		{fields[3], months, &s.month},
+		{fields[4], dows, &s.dow},
+	} {
+		if *f.bits, err = parseField(f.field, f.b); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", spec, err)
+		}
+	}
+	// Sunday is both 0 and 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// parseField returns the values of the field as a bit set.
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if i := strings.Index(part, "/"); i != -1 {
+			var err error
+			rng = part[:i]
+			if step, err = strconv.Atoi(part[i+1:]); err != nil || step < 1 {
+				return 0, fmt.Errorf("invalid step in the %s field %q", b.name, part)
+			}
+		}
+		first, last := b.min, b.max
+		switch {
+		case rng == "*":
+		case strings.Contains(rng, "-"):
+			i := strings.Index(rng, "-")
+			var err error
+			if first, err = parseValue(rng[:i], b); err != nil {
+				return 0, err
+			}
+			if last, err = parseValue(rng[i+1:], b); err != nil {
+				return 0, err
+			}
+			if first > last {
+				return 0, fmt.Errorf("invalid range in the %s field %q", b.name, part)
+			}
+		default:
+			var err error
+			if first, err = parseValue(rng, b); err != nil {
+				return 0, err
+			}
+			// "n/step" means from n to the end.
+			if step == 1 {
+				last = first
+			}
+		}
+		for v := first; v <= last; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, b bounds) (int, error) {
+	v, err := strconv.Atoi(s)
+	if err != nil || v < b.min || v > b.max {
+		return 0, fmt.Errorf("invalid %s %q: must be between %d and %d", b.name, s, b.min, b.max)
+	}
+	return v, nil
+}
+
+func has(bits uint64, v int) bool {
+	return bits&(1<<uint(v)) != 0
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+	if s.domStar || s.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+// Next returns the first time strictly after t matching the schedule, in
+// the location of t, or the zero time if there is none within five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	// Start at the next whole minute.
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case !has(s.month, int(t.Month())):
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case !has(s.hour, t.Hour()):
+			// Adding the minutes rather than building the next hour
+			// with time.Date always moves forward, even when the clocks
+			// go back.
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+		case !has(s.minute, t.Minute()):
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustParseTime(t *testing.T, s string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@often",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	for _, tc := range []struct {
+		spec     string
+		from     string
+		expected string
+	}{
+		{"* * * * *", "2026-03-10T10:00:30Z", "2026-03-10T10:01:00Z"},
+		{"30 2 * * *", "2026-03-10T10:00:00Z", "2026-03-11T02:30:00Z"},
+		{"30 2 * * *", "2026-03-10T02:29:00Z", "2026-03-10T02:30:00Z"},
+		// Strictly after.
+		{"30 2 * * *", "2026-03-10T02:30:00Z", "2026-03-11T02:30:00Z"},
+		{"@hourly", "2026-03-10T10:15:00Z", "2026-03-10T11:00:00Z"},
+		{"*/15 9-17 * * *", "2026-03-10T17:50:00Z", "2026-03-11T09:00:00Z"},
+		{"0 22-23,0-4/2 * * *", "2026-03-10T01:00:00Z", "2026-03-10T02:00:00Z"},
+		{"10/20 * * * *", "2026-03-10T10:31:00Z", "2026-03-10T10:50:00Z"},
+		// 2026-03-10 is a Tuesday. Sunday is 0 and 7.
+		{"0 1 * * 6,7", "2026-03-10T00:00:00Z", "2026-03-14T01:00:00Z"},
+		{"0 1 * * 0", "2026-03-14T02:00:00Z", "2026-03-15T01:00:00Z"},
+		{"0 0 1 * *", "2026-12-15T00:00:00Z", "2027-01-01T00:00:00Z"},
+		{"0 0 29 2 *", "2026-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		// Either the day of month or the day of week.
+		{"0 0 20 * 5", "2026-03-10T00:00:00Z", "2026-03-13T00:00:00Z"},
+		{"0 0 30 2 *", "2026-01-01T00:00:00Z", ""},
+	} {
+		next := mustSchedule(t, tc.spec).Next(mustParseTime(t, tc.from))
+		if tc.expected == "" {
+			if !next.IsZero() {
+				t.Errorf("%q from %s: expected no time, got %v", tc.spec, tc.from, next)
+			}
+			continue
+		}
+		if e := mustParseTime(t, tc.expected); !next.Equal(e) {
+			t.Errorf("%q from %s: expected %v, got %v", tc.spec, tc.from, e, next)
+		}
+	}
+}
+
+func TestNextDaylightSaving(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	s := mustSchedule(t, "30 * * * *")
+	// The clocks go forward from 02:00 to 03:00 on 2026-03-29.
+	next := s.Next(time.Date(2026, 3, 29, 1, 45, 0, 0, loc))
+	if e := time.Date(2026, 3, 29, 3, 30, 0, 0, loc); !next.Equal(e) {
+		t.Errorf("expected %v, got %v", e, next)
+	}
+	// The clocks go back from 03:00 to 02:00 on 2026-10-25.
+	from := time.Date(2026, 10, 25, 2, 45, 0, 0, loc)
+	next = s.Next(from)
+	if !next.After(from) || next.Sub(from) > time.Hour {
+		t.Errorf("expected the next half hour within an hour of %v, got %v", from, next)
+	}
+}
+
+func mustSchedule(t *testing.T, spec string) *Schedule {
+	t.Helper()
+	s, err := Parse(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxExtensions bounds the number of overlapping occurrences Close follows.
+const maxExtensions = 1000
+
+// Window is a maintenance window, opened by a cron schedule in a time zone
+// and closed after a duration.
+type Window struct {
+	schedule *Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// NewWindow returns the window opened by the cron schedule spec in the time
+// zone, for the duration. The time zone is a name of the IANA time zone
+// database, and defaults to UTC.
+func NewWindow(spec string, duration time.Duration, timeZone string) (*Window, error) {
+	s, err := Parse(spec)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("invalid duration %v: must be greater than 0", duration)
+	}
+	location := time.UTC
+	if timeZone != "" {
+		if location, err = time.LoadLocation(timeZone); err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", timeZone, err)
+		}
+	}
+	return &Window{schedule: s, duration: duration, location: location}, nil
+}
+
+// end returns the end of the latest occurrence of the window open at t, and
+// false if the window is closed at t.
+func (w *Window) end(t time.Time) (time.Time, bool) {
+	var end time.Time
+	start := w.schedule.Next(t.In(w.location).Add(-w.duration))
+	for !start.IsZero() && !start.After(t) {
+		end = start.Add(w.duration)
+		start = w.schedule.Next(start)
+	}
+	return end, !end.IsZero()
+}
+
+// Windows are the maintenance windows of a resource. They are open if any
+// of them is open. No windows are always open.
+type Windows []*Window
+
+// Open returns true if the windows are open at t.
+func (ws Windows) Open(t time.Time) bool {
+	if len(ws) == 0 {
+		return true
+	}
+	for _, w := range ws {
+		if _, ok := w.end(t); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns the first time after t at which one of the windows
+// opens, or the zero time if none opens within five years.
+func (ws Windows) NextOpen(t time.Time) time.Time {
+	var next time.Time
+	for _, w := range ws {
+		start := w.schedule.Next(t.In(w.location))
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+	return next
+}
+
+// Close returns the time at which the windows open at t all close,
+// following the windows that open before the others close. It returns the
+// zero time if no windows are configured, because they never close, and t
+// if the windows are closed at t.
+func (ws Windows) Close(t time.Time) time.Time {
+	if len(ws) == 0 {
+		return time.Time{}
+	}
+	closing := t
+	for i := 0; i < maxExtensions; i++ {
+		extended := false
+		for _, w := range ws {
+			if end, ok := w.end(closing); ok && end.After(closing) {
+				closing = end
+				extended = true
+			}
+		}
+		if !extended {
+			break
+		}
+	}
+	return closing
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func mustWindow(t *testing.T, spec string, duration time.Duration, timeZone string) *Window {
+	t.Helper()
+	w, err := NewWindow(spec, duration, timeZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestNewWindowErrors(t *testing.T) {
+	if _, err := NewWindow("0 1 * * *", 0, ""); err == nil {
+		t.Errorf("expected an error for a zero duration")
+	}
+	if _, err := NewWindow("0 1 * * *", time.Hour, "Nowhere/Special"); err == nil {
+		t.Errorf("expected an error for an unknown time zone")
+	}
+	if _, err := NewWindow("0 1 * *", time.Hour, ""); err == nil {
+		t.Errorf("expected an error for an invalid schedule")
+	}
+}
+
+func TestWindows(t *testing.T) {
+	// From 01:00 to 03:00 UTC every day.
+	nightly := Windows{mustWindow(t, "0 1 * * *", 2*time.Hour, "")}
+	for _, tc := range []struct {
+		at       string
+		open     bool
+		nextOpen string
+		close    string
+	}{
+		{"2026-03-10T00:59:00Z", false, "2026-03-10T01:00:00Z", "2026-03-10T00:59:00Z"},
+		{"2026-03-10T01:00:00Z", true, "2026-03-11T01:00:00Z", "2026-03-10T03:00:00Z"},
+		{"2026-03-10T02:59:00Z", true, "2026-03-11T01:00:00Z", "2026-03-10T03:00:00Z"},
+		{"2026-03-10T03:00:00Z", false, "2026-03-11T01:00:00Z", "2026-03-10T03:00:00Z"},
+	} {
+		at := mustParseTime(t, tc.at)
+		if a := nightly.Open(at); a != tc.open {
+			t.Errorf("%s: expected open %v, got %v", tc.at, tc.open, a)
+		}
+		if e, a := mustParseTime(t, tc.nextOpen), nightly.NextOpen(at); !a.Equal(e) {
+			t.Errorf("%s: expected to open next at %v, got %v", tc.at, e, a)
+		}
+		if e, a := mustParseTime(t, tc.close), nightly.Close(at); !a.Equal(e) {
+			t.Errorf("%s: expected to close at %v, got %v", tc.at, e, a)
+		}
+	}
+}
+
+func TestWindowsOverlap(t *testing.T) {
+	// Every hour from 01:00 to 04:00 for 90 minutes, then a window at
+	// 05:00 opening before the 04:00 one closes at 05:30.
+	ws := Windows{
+		mustWindow(t, "0 1-4 * * *", 90*time.Minute, ""),
+		mustWindow(t, "0 5 * * *", time.Hour, ""),
+	}
+	at := mustParseTime(t, "2026-03-10T01:10:00Z")
+	if e, a := mustParseTime(t, "2026-03-10T06:00:00Z"), ws.Close(at); !a.Equal(e) {
+		t.Errorf("expected to close at %v, got %v", e, a)
+	}
+}
+
+func TestWindowsTimeZone(t *testing.T) {
+	w, err := NewWindow("0 22 * * *", time.Hour, "America/New_York")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+	ws := Windows{w}
+	// 22:00 in New York is 03:00 UTC in winter.
+	if !ws.Open(mustParseTime(t, "2026-01-15T03:30:00Z")) {
+		t.Errorf("expected the window to be open")
+	}
+	if ws.Open(mustParseTime(t, "2026-01-15T22:30:00Z")) {
+		t.Errorf("expected the window to be closed at 22:30 UTC")
+	}
+}
+
+func TestNoWindows(t *testing.T) {
+	var ws Windows
+	now := time.Now()
+	if !ws.Open(now) {
+		t.Errorf("expected no windows to be open")
+	}
+	if !ws.Close(now).IsZero() {
+		t.Errorf("expected no windows never to close")
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
 	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
 	migrationclient "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
@@ -42,25 +43,113 @@ var (
 )
 
 const (
-	// The migration trigger controller redo the discovery every discoveryPeriod.
-	discoveryPeriod = 10 * time.Minute
+	// createdBy is recorded in the migrations created by the trigger.
+	createdBy = "storage-version-migration-trigger"
+	// The migration trigger controller redo the discovery every
+	// defaultDiscoveryPeriod by default.
+	defaultDiscoveryPeriod = 10 * time.Minute
+	// A storageState whose heartbeat is older than
+	// defaultStaleHeartbeatMultiplier discovery periods is stale.
+	defaultStaleHeartbeatMultiplier = 2
+	// A storageState whose resource has been missing from the discovery
+	// document for defaultStorageStateGracePeriod is garbage collected.
+	defaultStorageStateGracePeriod = 6 * defaultDiscoveryPeriod
 )
 
+// Options configures the MigrationTrigger.
+type Options struct {
+	// DiscoveryPeriod is how often the trigger checks the storage version
+	// hashes in the discovery document.
+	DiscoveryPeriod time.Duration
+	// StaleHeartbeatMultiplier is the number of discovery periods after
+	// which the heartbeat of a storageState is stale. The trigger then
+	// cannot tell whether the storage version changed unobserved, and
+	// migrates the resource again.
+	StaleHeartbeatMultiplier int32
+	// StorageStateGracePeriod is how long the resource of a storageState
+	// can be absent from the discovery document before the storageState
+	// and the storageVersionMigrations of the resource are deleted. Zero
+	// disables the garbage collection.
+	StorageStateGracePeriod time.Duration
+	// MigrationTTLSecondsAfterFinished is set as the
+	// .spec.ttlSecondsAfterFinished of the storageVersionMigrations
+	// created by the trigger. If nil, the created migrations are kept
+	// after they finish.
+	MigrationTTLSecondsAfterFinished *int32
+	// RetryPolicy controls how failed migrations are retried.
+	RetryPolicy RetryPolicy
+	// ResourceRetryPolicies overrides RetryPolicy for specific resources.
+	// The keys are "<resource>.<group>", or "<resource>" for the core
+	// group.
+	ResourceRetryPolicies map[string]RetryPolicy
+	// ObserveOnly makes the trigger only keep the storageStates up to
+	// date, and report the resources that need to be migrated, for
+	// clusters where another process migrates the storage. The trigger
+	// then never creates, supersedes or deletes storageVersionMigrations.
+	ObserveOnly bool
+	// ResourceRules select the resources the trigger launches migrations
+	// for. The storageStates of the other resources are kept up to date,
+	// as if the trigger was observe only for them.
+	ResourceRules controller.ResourceRules
+	// MigrationInformer is the informer of the storageVersionMigrations,
+	// with the indexers of controller.AddMigrationIndexers, shared with
+	// other controllers. Its owner runs it. If nil, the trigger creates and
+	// runs its own informer.
+	MigrationInformer cache.SharedIndexInformer
+	// EventRecorder records the events about the storageStates. If nil,
+	// no events are recorded.
+	EventRecorder EventRecorder
+	// StatusReporter reports the migration summary, e.g. as the status of
+	// an OpenShift ClusterOperator. If nil, the summary is only written
+	// to the MigrationSummary.
+	StatusReporter StatusReporter
+}
+
+// DefaultOptions returns the default Options of the MigrationTrigger.
+func DefaultOptions() Options {
+	return Options{
+		DiscoveryPeriod:          defaultDiscoveryPeriod,
+		StaleHeartbeatMultiplier: defaultStaleHeartbeatMultiplier,
+		StorageStateGracePeriod:  defaultStorageStateGracePeriod,
+		RetryPolicy:              DefaultRetryPolicy(),
+	}
+}
+
 type MigrationTrigger struct {
 	client            migrationclient.Interface
 	migrationInformer cache.SharedIndexInformer
 	queue             workqueue.RateLimitingInterface
+	options           Options
+	resourceFilter    *controller.ResourceFilter
+	// optionsUpdates passes the options set by UpdateOptions to Run.
+	optionsUpdates chan Options
+	// runInformer is true if the trigger owns migrationInformer.
+	runInformer bool
+	// policies are the migration policies listed by the last discovery.
+	policies *controller.MigrationPolicies
+	// plannedResources maps the resources migrated by the active migration
+	// plans listed by the last discovery to the names of the plans.
+	plannedResources map[string]string
 	// The timestamp of last time discovery is performed.
 	heartbeat metav1.Time
+	// discoveryFailure explains why the last discovery failed, empty if it
+	// succeeded.
+	discoveryFailure string
 }
 
-func NewMigrationTrigger(c migrationclient.Interface) *MigrationTrigger {
+func NewMigrationTrigger(c migrationclient.Interface, options Options) *MigrationTrigger {
 	mt := &MigrationTrigger{
-		client: c,
-		// TODO: share one with the kubemigrator.go.
-		migrationInformer: controller.NewStatusAndResourceIndexedInformer(c),
+		client:            c,
+		options:           options,
+		resourceFilter:    controller.NewResourceFilter(options.ResourceRules),
+		optionsUpdates:    make(chan Options, 1),
+		migrationInformer: options.MigrationInformer,
 		queue:             workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "migration_triggering_controller"),
 	}
+	if mt.migrationInformer == nil {
+		mt.migrationInformer = controller.NewStatusAndResourceIndexedInformer(c)
+		mt.runInformer = true
+	}
 	mt.migrationInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc:    mt.addResource,
 		UpdateFunc: mt.updateResource,
@@ -70,6 +159,31 @@ func NewMigrationTrigger(c migrationclient.Interface) *MigrationTrigger {
 	return mt
 }
 
+// UpdateOptions replaces the options of the running trigger. The new
+// options apply from the next discovery or migration the trigger processes.
+// If several updates are pending, only the last one is applied.
+func (mt *MigrationTrigger) UpdateOptions(options Options) {
+	for {
+		select {
+		case mt.optionsUpdates <- options:
+			return
+		default:
+		}
+		// Drop the pending update, it is replaced by this one.
+		select {
+		case <-mt.optionsUpdates:
+		default:
+		}
+	}
+}
+
+// setOptions applies the options. It must be called from the goroutine
+// processing the discoveries and the migrations.
+func (mt *MigrationTrigger) setOptions(options Options) {
+	mt.options = options
+	mt.resourceFilter = controller.NewResourceFilter(options.ResourceRules)
+}
+
 func (mt *MigrationTrigger) dequeue() <-chan interface{} {
 	work := make(chan interface{})
 	go func() {
@@ -84,7 +198,8 @@ func (mt *MigrationTrigger) dequeue() <-chan interface{} {
 	return work
 }
 
-// queueItem is the object in the workqueue.
+// queueItem is the object in the workqueue. It is queued by value, so that
+// the workqueue dedupes the items of a migration.
 type queueItem struct {
 	// the namespace of the storageVersionMigration object.
 	namespace string
@@ -124,18 +239,23 @@ func (mt *MigrationTrigger) updateResource(oldObj interface{}, obj interface{})
 	mt.addResource(obj)
 }
 
-func (mt *MigrationTrigger) enqueueResource(migration *migrationv1alpha1.StorageVersionMigration) {
-	it := &queueItem{
+func newQueueItem(migration *migrationv1alpha1.StorageVersionMigration) queueItem {
+	return queueItem{
 		namespace: migration.Namespace,
 		name:      migration.Name,
 		resource:  migration.Spec.Resource,
 	}
-	mt.queue.Add(it)
+}
+
+func (mt *MigrationTrigger) enqueueResource(migration *migrationv1alpha1.StorageVersionMigration) {
+	mt.queue.Add(newQueueItem(migration))
 }
 
 func (mt *MigrationTrigger) Run(ctx context.Context) {
 	defer utilruntime.HandleCrash()
-	go mt.migrationInformer.Run(ctx.Done())
+	if mt.runInformer {
+		go mt.migrationInformer.Run(ctx.Done())
+	}
 	if !cache.WaitForCacheSync(ctx.Done(), mt.migrationInformer.HasSynced) {
 		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
 		return
@@ -148,8 +268,8 @@ func (mt *MigrationTrigger) Run(ctx context.Context) {
 	//
 	// The discovery routine does the following for each resource:
 	// a. checks if storageState.status.currentStorageVersion == discovered.storageVersion
-	// b. if not, cleans existing migrations
-	// c. launches a new migration
+	// b. if not, launches a new migration
+	// c. supersedes existing migrations
 	// d. updates the storageState.status.currentStorageVersion and .persistedStorageVersions.
 	//
 	// The migration management routine does the following:
@@ -164,13 +284,20 @@ func (mt *MigrationTrigger) Run(ctx context.Context) {
 	//
 	// TODO: if we let the migration note down the currentStorageVersion,
 	// we can avoid the race.
-	ticker := time.NewTicker(discoveryPeriod)
+	ticker := time.NewTicker(mt.options.DiscoveryPeriod)
+	defer ticker.Stop()
 	// Do a discovery once started.
 	mt.processDiscovery(ctx)
 	for {
 		select {
 		case <-ticker.C:
 			mt.processDiscovery(ctx)
+		case options := <-mt.optionsUpdates:
+			if options.DiscoveryPeriod != mt.options.DiscoveryPeriod {
+				ticker.Reset(options.DiscoveryPeriod)
+			}
+			mt.setOptions(options)
+			klog.V(2).Infof("options updated")
 		case w := <-work:
 			defer mt.queue.Done(w)
 			err := mt.processQueue(ctx, w)
@@ -0,0 +1,116 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+func TestUpdateOptions(t *testing.T) {
+	trigger := NewMigrationTrigger(fake.NewSimpleClientset(), DefaultOptions())
+	first, second := DefaultOptions(), DefaultOptions()
+	first.DiscoveryPeriod = time.Minute
+	second.DiscoveryPeriod = 2 * time.Minute
+	second.ResourceRules.ExcludeResources = []string{"pods"}
+	trigger.UpdateOptions(first)
+	// The pending update is replaced.
+	trigger.UpdateOptions(second)
+	trigger.setOptions(<-trigger.optionsUpdates)
+	select {
+	case o := <-trigger.optionsUpdates:
+		t.Fatalf("expected a single pending update, got %v", o)
+	default:
+	}
+	if e, a := 2*time.Minute, trigger.options.DiscoveryPeriod; e != a {
+		t.Errorf("expected discovery period %v, got %v", e, a)
+	}
+	if ok, _ := trigger.resourceFilter.Allowed("", "pods"); ok {
+		t.Errorf("expected pods to be excluded by the updated options")
+	}
+}
+
+func TestProcessDiscoveryResourceExcluded(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	options := DefaultOptions()
+	options.ResourceRules = controller.ResourceRules{ExcludeResources: []string{"pods"}}
+	trigger := NewMigrationTrigger(client, options)
+	trigger.heartbeat = metav1.Now()
+	trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+	if n := countCreatedMigrations(client.Actions()); n != 0 {
+		t.Fatalf("expected no migration to be created, got %d", n)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationRequired, v1.ConditionTrue, v1alpha1.MigrationReasonNewResource)
+}
+
+func TestStaleHeartbeatMultiplier(t *testing.T) {
+	options := DefaultOptions()
+	options.StaleHeartbeatMultiplier = 4
+	trigger := NewMigrationTrigger(fake.NewSimpleClientset(), options)
+	trigger.heartbeat = metav1.Now()
+	ss := &v1alpha1.StorageState{}
+	ss.Status.LastHeartbeatTime = metav1.NewTime(trigger.heartbeat.Add(-3 * defaultDiscoveryPeriod))
+	if trigger.staleStorageState(ss) {
+		t.Errorf("expected a heartbeat 3 periods old not to be stale")
+	}
+	ss.Status.LastHeartbeatTime = metav1.NewTime(trigger.heartbeat.Add(-5 * defaultDiscoveryPeriod))
+	if !trigger.staleStorageState(ss) {
+		t.Errorf("expected a heartbeat 5 periods old to be stale")
+	}
+}
+
+func TestSharedMigrationInformer(t *testing.T) {
+	migration := &v1alpha1.StorageVersionMigration{
+		ObjectMeta: metav1.ObjectMeta{Name: "pods"},
+		Spec: v1alpha1.StorageVersionMigrationSpec{
+			Resource: v1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"},
+		},
+	}
+	client := fake.NewSimpleClientset(migration)
+	factory := informer.NewSharedInformerFactory(client, 0)
+	migrationInformer := factory.Migration().V1alpha1().StorageVersionMigrations().Informer()
+	if err := controller.AddMigrationIndexers(migrationInformer); err != nil {
+		t.Fatal(err)
+	}
+	options := DefaultOptions()
+	options.MigrationInformer = migrationInformer
+	trigger := NewMigrationTrigger(client, options)
+	if trigger.runInformer {
+		t.Errorf("expected the trigger not to run a shared informer")
+	}
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	factory.Start(stopCh)
+	cache.WaitForCacheSync(stopCh, migrationInformer.HasSynced)
+	// The migrations seen by the shared informer are queued by the trigger.
+	item, _ := trigger.queue.Get()
+	if e, a := newQueueItem(migration), item.(queueItem); e != a {
+		t.Errorf("expected queued item %v, got %v", e, a)
+	}
+}
@@ -20,17 +20,20 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	"k8s.io/klog/v2"
 
 	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/trigger/metrics"
 )
 
 func (mt *MigrationTrigger) processDiscovery(ctx context.Context) {
@@ -44,6 +47,7 @@ func (mt *MigrationTrigger) processDiscovery(ctx context.Context) {
 		}
 		return true, nil
 	})
+	mt.discoveryFailure = ""
 	if err != nil {
 		if discovery.IsGroupDiscoveryFailedError(err2) {
 			// process the partial discovery result, and update the heartbeat for
@@ -52,8 +56,25 @@ func (mt *MigrationTrigger) processDiscovery(ctx context.Context) {
 		} else {
 			klog.Warningf("failed to discover preferred resources: %v", err2)
 		}
+		mt.discoveryFailure = fmt.Sprintf("the last discovery failed: %v", err2)
+	}
+	// Marking the absent resources stale, and garbage collecting them,
+	// needs to know every resource served by the apiserver, so it is
+	// skipped unless the discovery is complete, or at least tells which
+	// groups are missing.
+	conclusive := err == nil
+	failedGroups := sets.NewString()
+	if err != nil && discovery.IsGroupDiscoveryFailedError(err2) {
+		conclusive = true
+		for gv := range err2.(*discovery.ErrGroupDiscoveryFailed).Groups {
+			failedGroups.Insert(gv.Group)
+		}
 	}
 	mt.heartbeat = metav1.Now()
+	mt.refreshPolicies(ctx)
+	mt.refreshPlans(ctx)
+	discovered := sets.NewString()
+	var discoveredResources []migrationv1alpha1.GroupVersionResource
 	for _, l := range resources {
 		gv, err := schema.ParseGroupVersion(l.GroupVersion)
 		if err != nil {
@@ -67,9 +88,17 @@ func (mt *MigrationTrigger) processDiscovery(ctx context.Context) {
 			if r.Version == "" {
 				r.Version = gv.Version
 			}
+			discovered.Insert(controller.StorageStateName(toGroupResource(r)))
+			discoveredResources = append(discoveredResources, toGroupResource(r))
 			mt.processDiscoveryResource(ctx, r)
 		}
 	}
+	if conclusive {
+		stale := mt.markStaleStorageStates(ctx, discovered, failedGroups)
+		mt.garbageCollectStorageStates(ctx, stale)
+	}
+	mt.updatePolicyStatuses(ctx, discoveredResources)
+	mt.updateSummary(ctx)
 }
 
 func toGroupResource(r metav1.APIResource) migrationv1alpha1.GroupVersionResource {
@@ -80,14 +109,16 @@ func toGroupResource(r metav1.APIResource) migrationv1alpha1.GroupVersionResourc
 	}
 }
 
-// cleanMigrations removes all storageVersionMigrations whose .spec.resource == r.
-func (mt *MigrationTrigger) cleanMigrations(ctx context.Context, r metav1.APIResource) error {
+// deleteMigrations removes all storageVersionMigrations whose
+// .spec.resource has the same group and resource as r, regardless of the
+// version.
+func (mt *MigrationTrigger) deleteMigrations(ctx context.Context, r migrationv1alpha1.GroupVersionResource) error {
 	// Using the cache to find all matching migrations.
 	// The delay of the cache shouldn't matter in practice, because
 	// existing migrations are created by previous discovery cycles, they
-	// have at least discoveryPeriod to enter the informer's cache.
+	// have at least a discovery period to enter the informer's cache.
 	idx := mt.migrationInformer.GetIndexer()
-	l, err := idx.ByIndex(controller.ResourceIndex, controller.ToIndex(toGroupResource(r)))
+	l, err := idx.ByIndex(controller.ResourceIndex, controller.ToIndex(r))
 	if err != nil {
 		return err
 	}
@@ -104,57 +135,100 @@ func (mt *MigrationTrigger) cleanMigrations(ctx context.Context, r metav1.APIRes
 	return nil
 }
 
-func (mt *MigrationTrigger) launchMigration(ctx context.Context, resource migrationv1alpha1.GroupVersionResource) error {
+func (mt *MigrationTrigger) launchMigration(ctx context.Context, resource migrationv1alpha1.GroupVersionResource, hash, reason, message string) (*migrationv1alpha1.StorageVersionMigration, error) {
 	m := &migrationv1alpha1.StorageVersionMigration{
 		ObjectMeta: metav1.ObjectMeta{
-			GenerateName: storageStateName(resource) + "-",
+			GenerateName: controller.StorageStateName(resource) + "-",
+			Annotations: map[string]string{
+				migrationv1alpha1.MigrationReasonAnnotation:             reason,
+				migrationv1alpha1.MigrationMessageAnnotation:            message,
+				migrationv1alpha1.MigrationCreatedByAnnotation:          createdBy,
+				migrationv1alpha1.MigrationStorageVersionHashAnnotation: hash,
+			},
 		},
 		Spec: migrationv1alpha1.StorageVersionMigrationSpec{
-			Resource: resource,
+			Resource:                resource,
+			TTLSecondsAfterFinished: mt.options.MigrationTTLSecondsAfterFinished,
 		},
 	}
-	_, err := mt.client.MigrationV1alpha1().StorageVersionMigrations().Create(ctx, m, metav1.CreateOptions{})
-	return err
+	if priority := mt.policies.Resolve(resource.Group, resource.Resource).MigrationPriority; priority != nil {
+		m.Spec.Priority = *priority
+	}
+	return mt.client.MigrationV1alpha1().StorageVersionMigrations().Create(ctx, m, metav1.CreateOptions{})
 }
 
-// relaunchMigration cleans existing migrations for the resource, and launch a new one.
-func (mt *MigrationTrigger) relaunchMigration(ctx context.Context, r metav1.APIResource) error {
-	if err := mt.cleanMigrations(ctx, r); err != nil {
-		return err
+// relaunchMigration launches a new migration for the resource, whose current
+// storage version hash is hash, and marks the existing pending or running
+// migrations of the resource as superseded by the new one. It does nothing if
+// the trigger is observe only, if an active migration plan migrates the
+// resource, or if the migration policies or the resource rules do not allow
+// migrating the resource.
+func (mt *MigrationTrigger) relaunchMigration(ctx context.Context, resource migrationv1alpha1.GroupVersionResource, hash, reason, message string) (*migrationv1alpha1.StorageVersionMigration, error) {
+	if mt.options.ObserveOnly {
+		klog.V(2).Infof("migration required for %s: %s", controller.StorageStateName(resource), message)
+		return nil, nil
 	}
-	return mt.launchMigration(ctx, toGroupResource(r))
-
+	if plan := mt.plannedBy(resource); plan != "" {
+		klog.V(2).Infof("migration required for %s, not launched because migration plan %s migrates it: %s", controller.StorageStateName(resource), plan, message)
+		return nil, nil
+	}
+	if ok, why := mt.migrationAllowed(resource); !ok {
+		klog.V(2).Infof("migration required for %s, not launched because %s: %s", controller.StorageStateName(resource), why, message)
+		return nil, nil
+	}
+	klog.V(2).Infof("launching migration for %s: %s", controller.StorageStateName(resource), message)
+	m, err := mt.launchMigration(ctx, resource, hash, reason, message)
+	if err != nil {
+		return nil, err
+	}
+	metrics.Metrics.ObserveLaunchedMigration(controller.StorageStateName(resource), reason)
+	return m, mt.supersedeMigrations(ctx, resource, m)
 }
 
-func (mt *MigrationTrigger) newStorageState(r metav1.APIResource) *migrationv1alpha1.StorageState {
-	return &migrationv1alpha1.StorageState{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: storageStateName(toGroupResource(r)),
-		},
-		Spec: migrationv1alpha1.StorageStateSpec{
-			Resource: migrationv1alpha1.GroupResource{
-				Group:    r.Group,
-				Resource: r.Name,
-			},
-		},
+// supersedeMigrations marks the unfinished storageVersionMigrations whose
+// .spec.resource has the same group and resource as r as superseded by the
+// migration "by".
+func (mt *MigrationTrigger) supersedeMigrations(ctx context.Context, r migrationv1alpha1.GroupVersionResource, by *migrationv1alpha1.StorageVersionMigration) error {
+	// Using the cache to find all matching migrations, see deleteMigrations.
+	migrations, err := controller.UnfinishedMigrations(mt.migrationInformer.GetIndexer(), r)
+	if err != nil {
+		return err
 	}
+	for _, m := range migrations {
+		if m.Name == by.Name {
+			continue
+		}
+		reason, message := by.Annotations[migrationv1alpha1.MigrationReasonAnnotation], fmt.Sprintf("superseded by migration %s", by.Name)
+		if err := controller.SupersedeMigration(ctx, mt.client, m, reason, message); err != nil {
+			return fmt.Errorf("unexpected error superseding migration %s, %v", m.Name, err)
+		}
+	}
+	return nil
 }
 
-func (mt *MigrationTrigger) updateStorageState(ctx context.Context, currentHash string, r metav1.APIResource) error {
+// updateStorageState updates the heartbeat, the storage version hashes and
+// the conditions of the storageState of the discovered resource r.
+// inProgress is the pending or running migration of r, or nil if there is
+// none. reason and message explain why r needs a migration, if it does.
+func (mt *MigrationTrigger) updateStorageState(ctx context.Context, currentHash string, r metav1.APIResource, inProgress *migrationv1alpha1.StorageVersionMigration, reason, message string) error {
 	// We will retry on any error, because failing to update the
 	// heartbeat of the storageState can lead to redo migration, which is
 	// costly.
 	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		ss, err := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, storageStateName(toGroupResource(r)), metav1.GetOptions{})
+		ss, err := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, controller.StorageStateName(toGroupResource(r)), metav1.GetOptions{})
 		if err != nil && !errors.IsNotFound(err) {
 			utilruntime.HandleError(err)
 			return false, nil
 		}
+		var old *migrationv1alpha1.StorageState
+		if err == nil {
+			old = ss.DeepCopy()
+		}
 		if err != nil && errors.IsNotFound(err) {
 			// Note that the apiserver resets the status field for
 			// the POST request. We need to update via the status
 			// endpoint.
-			ss, err = mt.client.MigrationV1alpha1().StorageStates().Create(ctx, mt.newStorageState(r), metav1.CreateOptions{})
+			ss, err = mt.client.MigrationV1alpha1().StorageStates().Create(ctx, controller.NewStorageState(toGroupResource(r)), metav1.CreateOptions{})
 			if err != nil {
 				utilruntime.HandleError(err)
 				return false, nil
@@ -162,6 +236,9 @@ func (mt *MigrationTrigger) updateStorageState(ctx context.Context, currentHash
 		}
 		if ss.Status.CurrentStorageVersionHash != currentHash {
 			ss.Status.CurrentStorageVersionHash = currentHash
+			// The failed migrations were about the previous
+			// storage version.
+			resetFailedMigrationAttempts(ss)
 			if len(ss.Status.PersistedStorageVersionHashes) == 0 {
 				ss.Status.PersistedStorageVersionHashes = []string{migrationv1alpha1.Unknown}
 			} else {
@@ -169,17 +246,24 @@ func (mt *MigrationTrigger) updateStorageState(ctx context.Context, currentHash
 			}
 		}
 		ss.Status.LastHeartbeatTime = mt.heartbeat
-		_, err = mt.client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, ss, metav1.UpdateOptions{})
+		recordStorageVersionHashHistory(ss, mt.heartbeat)
+		mt.setMigratedCondition(ss, mt.heartbeat)
+		setMigrationInProgressCondition(ss, inProgress, mt.heartbeat)
+		mt.setStaleCondition(ss, false, mt.heartbeat)
+		mt.setMigrationRequiredCondition(ss, inProgress, reason, message, mt.heartbeat)
+		updated, err := mt.client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, ss, metav1.UpdateOptions{})
 		if err != nil {
 			utilruntime.HandleError(err)
 			return false, nil
 		}
+		mt.reportStorageState(old, updated)
 		return true, nil
 	})
 }
 
 func (mt *MigrationTrigger) staleStorageState(ss *migrationv1alpha1.StorageState) bool {
-	return ss.Status.LastHeartbeatTime.Add(2 * discoveryPeriod).Before(mt.heartbeat.Time)
+	staleness := time.Duration(mt.options.StaleHeartbeatMultiplier) * mt.options.DiscoveryPeriod
+	return ss.Status.LastHeartbeatTime.Add(staleness).Before(mt.heartbeat.Time)
 }
 
 func (mt *MigrationTrigger) processDiscoveryResource(ctx context.Context, r metav1.APIResource) {
@@ -188,7 +272,7 @@ func (mt *MigrationTrigger) processDiscoveryResource(ctx context.Context, r meta
 		klog.V(2).Infof("ignored resource %s/%s because its storageVersionHash is empty", r.Group, r.Name)
 		return
 	}
-	ss, getErr := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, storageStateName(toGroupResource(r)), metav1.GetOptions{})
+	ss, getErr := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, controller.StorageStateName(toGroupResource(r)), metav1.GetOptions{})
 	if getErr != nil && !errors.IsNotFound(getErr) {
 		utilruntime.HandleError(getErr)
 		return
@@ -196,47 +280,111 @@ func (mt *MigrationTrigger) processDiscoveryResource(ctx context.Context, r meta
 	found := getErr == nil
 	stale := found && mt.staleStorageState(ss)
 	storageVersionChanged := found && ss.Status.CurrentStorageVersionHash != r.StorageVersionHash
-	needsMigration := found && !mt.isMigrated(ss) && !mt.hasPendingOrRunningMigration(r)
+	needsMigration := found && !mt.isMigrated(ss) && !mt.hasPendingOrRunningMigration(toGroupResource(r)) && mt.retryAllowed(ss)
 	relaunchMigration := stale || !found || storageVersionChanged || needsMigration
 
+	var reason, message string
+	switch {
+	case stale:
+		reason = migrationv1alpha1.MigrationReasonStaleHeartbeat
+		message = fmt.Sprintf("the heartbeat of the storageState was last updated at %v", ss.Status.LastHeartbeatTime)
+	case !found:
+		reason = migrationv1alpha1.MigrationReasonNewResource
+		message = "the resource has no storageState"
+	case storageVersionChanged:
+		reason = migrationv1alpha1.MigrationReasonStorageVersionHashChanged
+		message = fmt.Sprintf("the storage version hash changed from %q to %q", ss.Status.CurrentStorageVersionHash, r.StorageVersionHash)
+	case found && !mt.isMigrated(ss):
+		// Explains why the migration is required, even if the
+		// retry policy does not allow to launch it now.
+		reason, message = mt.missingMigrationReason(r)
+	}
+
 	if stale {
-		if err := mt.client.MigrationV1alpha1().StorageStates().Delete(ctx, storageStateName(toGroupResource(r)), metav1.DeleteOptions{}); err != nil {
+		if err := mt.client.MigrationV1alpha1().StorageStates().Delete(ctx, controller.StorageStateName(toGroupResource(r)), metav1.DeleteOptions{}); err != nil {
 			utilruntime.HandleError(err)
 			return
 		}
 	}
 
+	var inProgress *migrationv1alpha1.StorageVersionMigration
 	if relaunchMigration {
-		// Note that this means historical migration objects are deleted.
-		if err := mt.relaunchMigration(ctx, r); err != nil {
+		// Note that this means unfinished migration objects are
+		// superseded.
+		m, err := mt.relaunchMigration(ctx, toGroupResource(r), r.StorageVersionHash, reason, message)
+		if err != nil {
 			utilruntime.HandleError(err)
 		}
+		inProgress = m
+	}
+	if inProgress == nil {
+		inProgress = mt.unfinishedMigration(toGroupResource(r))
 	}
 
 	// always update status.heartbeat, sometimes update the version hashes.
-	mt.updateStorageState(ctx, r.StorageVersionHash, r)
+	mt.updateStorageState(ctx, r.StorageVersionHash, r, inProgress, reason, message)
 }
 func (mt *MigrationTrigger) isMigrated(ss *migrationv1alpha1.StorageState) bool {
-	if len(ss.Status.PersistedStorageVersionHashes) != 1 {
-		return false
-	}
-	return ss.Status.CurrentStorageVersionHash == ss.Status.PersistedStorageVersionHashes[0]
+	return controller.IsStorageStateMigrated(ss)
+}
+
+func (mt *MigrationTrigger) hasPendingOrRunningMigration(r migrationv1alpha1.GroupVersionResource) bool {
+	return mt.unfinishedMigration(r) != nil
 }
 
-func (mt *MigrationTrigger) hasPendingOrRunningMigration(r metav1.APIResource) bool {
+// unfinishedMigration returns the latest pending or running migration of the
+// resource, or nil if there is none.
+func (mt *MigrationTrigger) unfinishedMigration(r migrationv1alpha1.GroupVersionResource) *migrationv1alpha1.StorageVersionMigration {
 	// get the corresponding StorageVersionMigration resource
-	migrations, err := mt.migrationInformer.GetIndexer().ByIndex(controller.ResourceIndex, controller.ToIndex(toGroupResource(r)))
+	migrations, err := mt.migrationInformer.GetIndexer().ByIndex(controller.ResourceIndex, controller.ToIndex(r))
 	if err != nil {
 		utilruntime.HandleError(err)
-		return false
+		return nil
 	}
+	var latest *migrationv1alpha1.StorageVersionMigration
 	for _, migration := range migrations {
 		m := migration.(*migrationv1alpha1.StorageVersionMigration)
-		if controller.HasCondition(m, migrationv1alpha1.MigrationSucceeded) || controller.HasCondition(m, migrationv1alpha1.MigrationFailed) {
+		if controller.IsFinished(m) {
 			continue
 		}
 		// migration is running or pending
-		return true
+		if latest == nil || latest.CreationTimestamp.Before(&m.CreationTimestamp) {
+			latest = m
+		}
+	}
+	return latest
+}
+
+// missingMigrationReason explains why a resource that is not migrated has no
+// pending or running migration.
+func (mt *MigrationTrigger) missingMigrationReason(r metav1.APIResource) (string, string) {
+	reason := migrationv1alpha1.MigrationReasonMigrationMissing
+	message := "the resource is not migrated, and has no pending or running migration"
+	migrations, err := mt.migrationInformer.GetIndexer().ByIndex(controller.ResourceIndex, controller.ToIndex(toGroupResource(r)))
+	if err != nil {
+		utilruntime.HandleError(err)
+		return reason, message
+	}
+	// Find the migration that finished last, superseded migrations
+	// excluded.
+	var last *migrationv1alpha1.StorageVersionMigration
+	var lastCondition *migrationv1alpha1.MigrationCondition
+	for _, migration := range migrations {
+		m := migration.(*migrationv1alpha1.StorageVersionMigration)
+		c := controller.GetCondition(m, migrationv1alpha1.MigrationSucceeded)
+		if c == nil {
+			c = controller.GetCondition(m, migrationv1alpha1.MigrationFailed)
+		}
+		if c == nil || controller.HasCondition(m, migrationv1alpha1.MigrationSuperseded) {
+			continue
+		}
+		if last == nil || lastCondition.LastUpdateTime.Before(&c.LastUpdateTime) {
+			last, lastCondition = m, c
+		}
+	}
+	if last != nil && lastCondition.Type == migrationv1alpha1.MigrationFailed {
+		reason = migrationv1alpha1.MigrationReasonPreviousMigrationFailed
+		message = fmt.Sprintf("the previous migration %s failed: %s", last.Name, lastCondition.Message)
 	}
-	return false
+	return reason, message
 }
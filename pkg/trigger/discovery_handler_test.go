@@ -35,12 +35,13 @@ import (
 
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
 )
 
 func TestProcessDiscoveryResource(t *testing.T) {
 	// TODO: we probably don't need a list
 	client := fake.NewSimpleClientset(newMigrationList())
-	trigger := NewMigrationTrigger(client)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go trigger.migrationInformer.Run(stopCh)
@@ -52,7 +53,7 @@ func TestProcessDiscoveryResource(t *testing.T) {
 	discoveredResource := newAPIResource()
 	trigger.processDiscoveryResource(context.TODO(), discoveredResource)
 	actions := client.Actions()
-	verifyCleanupAndLaunch(t, actions[3:7])
+	verifyLaunchAndSupersede(t, actions[3:7], v1alpha1.MigrationReasonNewResource)
 
 	c, ok := actions[8].(core.CreateAction)
 	if !ok {
@@ -68,7 +69,7 @@ func TestProcessDiscoveryResource(t *testing.T) {
 
 func TestProcessDiscoveryResourceStaleState(t *testing.T) {
 	client := fake.NewSimpleClientset(newMigrationList(), storageState(withStaleHeartbeat()))
-	trigger := NewMigrationTrigger(client)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go trigger.migrationInformer.Run(stopCh)
@@ -93,7 +94,7 @@ func TestProcessDiscoveryResourceStaleState(t *testing.T) {
 		t.Fatalf("unexpected name %s", d.GetName())
 	}
 
-	verifyCleanupAndLaunch(t, actions[4:8])
+	verifyLaunchAndSupersede(t, actions[4:8], v1alpha1.MigrationReasonStaleHeartbeat)
 
 	c, ok := actions[9].(core.CreateAction)
 	if !ok {
@@ -116,7 +117,7 @@ func TestProcessDiscoveryResourceStorageVersionChanged(t *testing.T) {
 			withPersistedVersions("oldhash"),
 		),
 	)
-	trigger := NewMigrationTrigger(client)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go trigger.migrationInformer.Run(stopCh)
@@ -129,7 +130,7 @@ func TestProcessDiscoveryResourceStorageVersionChanged(t *testing.T) {
 	trigger.processDiscoveryResource(context.TODO(), discoveredResource)
 
 	actions := client.Actions()
-	verifyCleanupAndLaunch(t, actions[3:7])
+	verifyLaunchAndSupersede(t, actions[3:7], v1alpha1.MigrationReasonStorageVersionHashChanged)
 	verifyStorageStateUpdate(t, actions[8], trigger.heartbeat, discoveredResource.StorageVersionHash, []string{"oldhash", "newhash"})
 }
 
@@ -142,7 +143,7 @@ func TestProcessDiscoveryResourceNoChange(t *testing.T) {
 			withPersistedVersions("newhash"),
 		),
 	)
-	trigger := NewMigrationTrigger(client)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go trigger.migrationInformer.Run(stopCh)
@@ -166,7 +167,7 @@ func TestProcessDiscoveryResourceStorageMigrationMissing(t *testing.T) {
 			withPersistedVersions(v1alpha1.Unknown),
 		),
 	)
-	trigger := NewMigrationTrigger(client)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go trigger.migrationInformer.Run(stopCh)
@@ -179,7 +180,10 @@ func TestProcessDiscoveryResourceStorageMigrationMissing(t *testing.T) {
 	trigger.processDiscoveryResource(context.Background(), discoveredResource)
 
 	actions := client.Actions()
-	expectCreateStorageVersionMigrationAction(t, actions[3])
+	m := expectCreateStorageVersionMigrationAction(t, actions[3])
+	if a, e := m.Annotations[v1alpha1.MigrationReasonAnnotation], v1alpha1.MigrationReasonMigrationMissing; a != e {
+		t.Fatalf("expected reason %q, got %q", e, a)
+	}
 	verifyStorageStateUpdate(t, actions[len(actions)-1], trigger.heartbeat, discoveredResource.StorageVersionHash, []string{v1alpha1.Unknown})
 }
 
@@ -192,7 +196,7 @@ func TestProcessDiscoveryResourceStorageMigrationFailed(t *testing.T) {
 			withPersistedVersions(v1alpha1.Unknown),
 		),
 	)
-	trigger := NewMigrationTrigger(client)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go trigger.migrationInformer.Run(stopCh)
@@ -204,14 +208,18 @@ func TestProcessDiscoveryResourceStorageMigrationFailed(t *testing.T) {
 	discoveredResource := newAPIResource()
 	trigger.processDiscoveryResource(context.Background(), discoveredResource)
 	actions := client.Actions()
-	expectCreateStorageVersionMigrationAction(t, actions[4])
+	// The failed migration is kept.
+	m := expectCreateStorageVersionMigrationAction(t, actions[3])
+	if a, e := m.Annotations[v1alpha1.MigrationReasonAnnotation], v1alpha1.MigrationReasonPreviousMigrationFailed; a != e {
+		t.Fatalf("expected reason %q, got %q", e, a)
+	}
 	verifyStorageStateUpdate(t, actions[len(actions)-1], trigger.heartbeat, discoveredResource.StorageVersionHash, []string{v1alpha1.Unknown})
 }
 
 func storageState(options ...func(*v1alpha1.StorageState)) *v1alpha1.StorageState {
 	ss := &v1alpha1.StorageState{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: storageStateName(v1alpha1.GroupVersionResource{Resource: "pods"}),
+			Name: controller.StorageStateName(v1alpha1.GroupVersionResource{Resource: "pods"}),
 		},
 		Spec: v1alpha1.StorageStateSpec{
 			Resource: v1alpha1.GroupResource{Resource: "pods"},
@@ -225,13 +233,13 @@ func storageState(options ...func(*v1alpha1.StorageState)) *v1alpha1.StorageStat
 
 func withFreshHeartbeat() func(*v1alpha1.StorageState) {
 	return func(ss *v1alpha1.StorageState) {
-		ss.Status.LastHeartbeatTime = metav1.NewTime(metav1.Now().Add(-1 * discoveryPeriod))
+		ss.Status.LastHeartbeatTime = metav1.NewTime(metav1.Now().Add(-1 * defaultDiscoveryPeriod))
 	}
 }
 
 func withStaleHeartbeat() func(*v1alpha1.StorageState) {
 	return func(ss *v1alpha1.StorageState) {
-		ss.Status.LastHeartbeatTime = metav1.NewTime(metav1.Now().Add(-3 * discoveryPeriod))
+		ss.Status.LastHeartbeatTime = metav1.NewTime(metav1.Now().Add(-3 * defaultDiscoveryPeriod))
 	}
 }
 
@@ -311,26 +319,37 @@ func newAPIResource() metav1.APIResource {
 	}
 }
 
-func verifyCleanupAndLaunch(t *testing.T, actions []core.Action) {
+func verifyLaunchAndSupersede(t *testing.T, actions []core.Action, expectedReason string) {
 	if len(actions) != 4 {
 		t.Fatalf("expected 4 actions")
 
 	}
-	for i := 0; i < 3; i++ {
+	m := expectCreateStorageVersionMigrationAction(t, actions[0])
+	if a, e := m.Annotations[v1alpha1.MigrationReasonAnnotation], expectedReason; a != e {
+		t.Fatalf("expected reason %q, got %q", e, a)
+	}
+	if a, e := m.Annotations[v1alpha1.MigrationCreatedByAnnotation], createdBy; a != e {
+		t.Fatalf("expected created by %q, got %q", e, a)
+	}
+	for i := 1; i < 4; i++ {
 		a := actions[i]
-		d, ok := a.(core.DeleteAction)
+		u, ok := a.(core.UpdateAction)
 		if !ok {
-			t.Fatalf("expected delete action")
+			t.Fatalf("expected update action")
 		}
 		r := schema.GroupVersionResource{Group: "migration.k8s.io", Version: "v1alpha1", Resource: "storageversionmigrations"}
-		if d.GetResource() != r {
-			t.Fatalf("unexpected resource %v", d.GetResource())
+		if u.GetResource() != r || u.GetSubresource() != "status" {
+			t.Fatalf("unexpected resource %v, subresource %v", u.GetResource(), u.GetSubresource())
+		}
+		superseded := u.GetObject().(*v1alpha1.StorageVersionMigration)
+		if !strings.Contains(superseded.Name, "migration") {
+			t.Fatalf("unexpected name %s", superseded.Name)
 		}
-		if !strings.Contains(d.GetName(), "migration") {
-			t.Fatalf("unexpected name %s", d.GetName())
+		c := superseded.Status.Conditions[len(superseded.Status.Conditions)-1]
+		if c.Type != v1alpha1.MigrationSuperseded || c.Reason != expectedReason {
+			t.Fatalf("expected Superseded condition with reason %q, got %v", expectedReason, c)
 		}
 	}
-	expectCreateStorageVersionMigrationAction(t, actions[3])
 }
 
 func expectCreateStorageVersionMigrationAction(t *testing.T, action core.Action) *v1alpha1.StorageVersionMigration {
@@ -404,7 +423,7 @@ func (f *FakeDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, e
 func TestProcessDiscoveryPartialFailure(t *testing.T) {
 	client := fake.NewSimpleClientset(newMigrationList())
 	// overrides the ServerPreferredResources method of the simple clientset
-	trigger := NewMigrationTrigger(&FakeClientset{Clientset: client})
+	trigger := NewMigrationTrigger(&FakeClientset{Clientset: client}, DefaultOptions())
 	stopCh := make(chan struct{})
 	defer close(stopCh)
 	go trigger.migrationInformer.Run(stopCh)
@@ -417,9 +436,11 @@ func TestProcessDiscoveryPartialFailure(t *testing.T) {
 	// the API resources
 	trigger.processDiscovery(context.TODO())
 	actions := client.Actions()
-	verifyCleanupAndLaunch(t, actions[3:7])
+	// The first actions are the list and watch of the informer, the lists
+	// of the migration policies and plans, and the get of the storageState.
+	verifyLaunchAndSupersede(t, actions[5:9], v1alpha1.MigrationReasonNewResource)
 
-	c, ok := actions[8].(core.CreateAction)
+	c, ok := actions[10].(core.CreateAction)
 	if !ok {
 		t.Fatalf("expected create action")
 	}
@@ -428,5 +449,5 @@ func TestProcessDiscoveryPartialFailure(t *testing.T) {
 		t.Fatalf("unexpected resource %v", c.GetResource())
 	}
 
-	verifyStorageStateUpdate(t, actions[9], trigger.heartbeat, newAPIResource().StorageVersionHash, []string{v1alpha1.Unknown})
+	verifyStorageStateUpdate(t, actions[11], trigger.heartbeat, newAPIResource().StorageVersionHash, []string{v1alpha1.Unknown})
 }
@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/reference"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/scheme"
+)
+
+// EventRecorder records events about the storageStates. It is satisfied by
+// the EventRecorder of k8s.io/client-go/tools/record.
+type EventRecorder interface {
+	Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{})
+}
+
+type eventRecorder struct {
+	client corev1client.EventsGetter
+	source corev1.EventSource
+}
+
+// NewEventRecorder returns an EventRecorder that creates events with the
+// client, on behalf of the component. The events about cluster scoped
+// objects are created in the default namespace.
+func NewEventRecorder(client corev1client.EventsGetter, component string) EventRecorder {
+	return &eventRecorder{
+		client: client,
+		source: corev1.EventSource{Component: component},
+	}
+}
+
+func (r *eventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	ref, err := reference.GetReference(scheme.Scheme, object)
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("could not construct reference to %#v: %v", object, err))
+		return
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = metav1.NamespaceDefault
+	}
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%v.%x", ref.Name, now.UnixNano()),
+			Namespace: namespace,
+		},
+		InvolvedObject: *ref,
+		Reason:         reason,
+		Message:        fmt.Sprintf(messageFmt, args...),
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+		Type:           eventtype,
+		Source:         r.source,
+	}
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+	if _, err := r.client.Events(namespace).Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to record event %s about %s: %v", reason, ref.Name, err))
+	}
+}
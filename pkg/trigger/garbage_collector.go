@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/trigger/metrics"
+)
+
+// markStaleStorageStates sets the Stale condition of the storageStates of
+// the resources absent from the discovery document, and returns them.
+// discovered contains the names of the storageStates of the resources in the
+// discovery document, whose Stale condition is cleared by
+// processDiscoveryResource. The storageStates of resources in failedGroups
+// are left alone, because the absence of such resources is not conclusive.
+func (mt *MigrationTrigger) markStaleStorageStates(ctx context.Context, discovered, failedGroups sets.String) []*migrationv1alpha1.StorageState {
+	l, err := mt.client.MigrationV1alpha1().StorageStates().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		utilruntime.HandleError(err)
+		return nil
+	}
+	var stale []*migrationv1alpha1.StorageState
+	for i := range l.Items {
+		ss := &l.Items[i]
+		if discovered.Has(ss.Name) || failedGroups.Has(ss.Spec.Resource.Group) {
+			continue
+		}
+		mt.markStorageStateStale(ctx, ss)
+		stale = append(stale, ss)
+	}
+	return stale
+}
+
+// garbageCollectStorageStates deletes the stale storageStates, together with
+// the storageVersionMigrations, of the resources that have been absent from
+// the discovery document for longer than the grace period.
+func (mt *MigrationTrigger) garbageCollectStorageStates(ctx context.Context, stale []*migrationv1alpha1.StorageState) {
+	if mt.options.StorageStateGracePeriod == 0 {
+		return
+	}
+	for _, ss := range stale {
+		// The heartbeat stops being updated once the resource
+		// disappears from the discovery document.
+		if !ss.Status.LastHeartbeatTime.Add(mt.options.StorageStateGracePeriod).Before(mt.heartbeat.Time) {
+			continue
+		}
+		klog.V(2).Infof("garbage collecting storageState %s, its resource has been absent from discovery since %v", ss.Name, ss.Status.LastHeartbeatTime)
+		r := migrationv1alpha1.GroupVersionResource{
+			Group:    ss.Spec.Resource.Group,
+			Resource: ss.Spec.Resource.Resource,
+		}
+		// The migrations belong to another process if the trigger
+		// is observe only.
+		if !mt.options.ObserveOnly {
+			if err := mt.deleteMigrations(ctx, r); err != nil {
+				utilruntime.HandleError(err)
+				continue
+			}
+		}
+		err := mt.client.MigrationV1alpha1().StorageStates().Delete(ctx, ss.Name, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			utilruntime.HandleError(err)
+			continue
+		}
+		metrics.Metrics.Forget(ss.Name)
+	}
+}
+
+// expireMigration deletes a finished migration whose
+// .spec.ttlSecondsAfterFinished has passed. If the migration has not expired
+// yet, it is queued again to be checked when it does. Migrations are not
+// deleted if the trigger is observe only.
+func (mt *MigrationTrigger) expireMigration(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration) error {
+	if m.Spec.TTLSecondsAfterFinished == nil || mt.options.ObserveOnly {
+		return nil
+	}
+	c := controller.GetCondition(m, migrationv1alpha1.MigrationSucceeded)
+	if c == nil {
+		c = controller.GetCondition(m, migrationv1alpha1.MigrationFailed)
+	}
+	if c == nil {
+		return nil
+	}
+	finished := c.LastUpdateTime
+	if finished.IsZero() {
+		finished = m.CreationTimestamp
+	}
+	expiresIn := time.Until(finished.Add(time.Duration(*m.Spec.TTLSecondsAfterFinished) * time.Second))
+	if expiresIn > 0 {
+		mt.queue.AddAfter(newQueueItem(m), expiresIn)
+		return nil
+	}
+	klog.V(2).Infof("deleting migration %s, it finished more than %d seconds ago", m.Name, *m.Spec.TTLSecondsAfterFinished)
+	err := mt.client.MigrationV1alpha1().StorageVersionMigrations().Delete(ctx, m.Name, metav1.DeleteOptions{
+		Preconditions: &metav1.Preconditions{UID: &m.UID},
+	})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// markStorageStateStale sets the Stale condition of the storageState whose
+// resource is absent from the discovery document.
+func (mt *MigrationTrigger) markStorageStateStale(ctx context.Context, ss *migrationv1alpha1.StorageState) {
+	if controller.HasStorageStateCondition(ss, migrationv1alpha1.StorageStateStale) {
+		return
+	}
+	updated := ss.DeepCopy()
+	mt.setStaleCondition(updated, true, mt.heartbeat)
+	// A conflict is retried by the next discovery routine.
+	_, err := mt.client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		utilruntime.HandleError(err)
+	}
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+func withStorageStateResource(group, resource string) func(*v1alpha1.StorageState) {
+	return func(ss *v1alpha1.StorageState) {
+		r := v1alpha1.GroupVersionResource{Group: group, Resource: resource}
+		ss.Name = controller.StorageStateName(r)
+		ss.Spec.Resource = v1alpha1.GroupResource{Group: group, Resource: resource}
+	}
+}
+
+func withHeartbeat(t metav1.Time) func(*v1alpha1.StorageState) {
+	return func(ss *v1alpha1.StorageState) {
+		ss.Status.LastHeartbeatTime = t
+	}
+}
+
+func withSucceededCondition(lastUpdateTime metav1.Time) func(*v1alpha1.StorageVersionMigration) {
+	return func(migration *v1alpha1.StorageVersionMigration) {
+		migration.Status.Conditions = append(migration.Status.Conditions, v1alpha1.MigrationCondition{
+			Type:           v1alpha1.MigrationSucceeded,
+			Status:         v1.ConditionTrue,
+			LastUpdateTime: lastUpdateTime,
+		})
+	}
+}
+
+func withTTLSecondsAfterFinished(ttl int32) func(*v1alpha1.StorageVersionMigration) {
+	return func(migration *v1alpha1.StorageVersionMigration) {
+		migration.Spec.TTLSecondsAfterFinished = &ttl
+	}
+}
+
+func deletedNames(actions []core.Action, resource string) sets.String {
+	ret := sets.NewString()
+	for _, a := range actions {
+		d, ok := a.(core.DeleteAction)
+		if !ok || d.GetResource().Resource != resource {
+			continue
+		}
+		ret.Insert(d.GetName())
+	}
+	return ret
+}
+
+func TestGarbageCollectStorageStates(t *testing.T) {
+	now := metav1.Now()
+	expired := metav1.NewTime(now.Add(-2 * defaultStorageStateGracePeriod))
+	widgets := v1alpha1.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	client := fake.NewSimpleClientset(
+		// still in discovery
+		storageState(withHeartbeat(expired)),
+		// absent for longer than the grace period
+		storageState(withStorageStateResource("example.com", "widgets"), withHeartbeat(expired)),
+		// absent for shorter than the grace period
+		storageState(withStorageStateResource("example.com", "gadgets"), withHeartbeat(now)),
+		// in a group that failed discovery
+		storageState(withStorageStateResource("failed.example.com", "things"), withHeartbeat(expired)),
+		storageMigration(withName("pods-migration")),
+		storageMigration(withName("widgets-migration0"), withResource(widgets)),
+		storageMigration(withName("widgets-migration1"), withResource(widgets)),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go trigger.migrationInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+		return
+	}
+	trigger.heartbeat = now
+	stale := trigger.markStaleStorageStates(context.TODO(), sets.NewString("pods"), sets.NewString("failed.example.com"))
+	trigger.garbageCollectStorageStates(context.TODO(), stale)
+
+	actions := client.Actions()
+	if e, a := sets.NewString("widgets.example.com"), deletedNames(actions, "storagestates"); !e.Equal(a) {
+		t.Errorf("expected deleted storageStates %v, got %v", e.List(), a.List())
+	}
+	if e, a := sets.NewString("widgets-migration0", "widgets-migration1"), deletedNames(actions, "storageversionmigrations"); !e.Equal(a) {
+		t.Errorf("expected deleted migrations %v, got %v", e.List(), a.List())
+	}
+	// Both absent resources are marked stale, whether they are garbage
+	// collected or not.
+	updated := sets.NewString()
+	for _, a := range actions {
+		u, ok := a.(core.UpdateAction)
+		if !ok || u.GetResource().Resource != "storagestates" {
+			continue
+		}
+		ss := u.GetObject().(*v1alpha1.StorageState)
+		expectStorageStateCondition(t, ss, v1alpha1.StorageStateStale, v1.ConditionTrue, "ResourceNotDiscovered")
+		updated.Insert(ss.Name)
+	}
+	if e := sets.NewString("gadgets.example.com", "widgets.example.com"); !e.Equal(updated) {
+		t.Errorf("expected updated storageStates %v, got %v", e.List(), updated.List())
+	}
+}
+
+func TestGarbageCollectStorageStatesDisabled(t *testing.T) {
+	expired := metav1.NewTime(metav1.Now().Add(-2 * defaultStorageStateGracePeriod))
+	client := fake.NewSimpleClientset(
+		storageState(withStorageStateResource("example.com", "widgets"), withHeartbeat(expired)),
+	)
+	options := DefaultOptions()
+	options.StorageStateGracePeriod = 0
+	trigger := NewMigrationTrigger(client, options)
+	trigger.heartbeat = metav1.Now()
+	stale := trigger.markStaleStorageStates(context.TODO(), sets.NewString(), sets.NewString())
+	trigger.garbageCollectStorageStates(context.TODO(), stale)
+
+	actions := client.Actions()
+	if a := deletedNames(actions, "storagestates"); a.Len() != 0 {
+		t.Errorf("expected no deleted storageStates, got %v", a.List())
+	}
+	ss := lastStorageStateUpdate(t, actions)
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateStale, v1.ConditionTrue, "ResourceNotDiscovered")
+	if c := controller.GetStorageStateCondition(ss, v1alpha1.StorageStateStale); strings.Contains(c.Message, "garbage collected") {
+		t.Errorf("expected no garbage collection time, got %q", c.Message)
+	}
+}
+
+func TestExpireMigration(t *testing.T) {
+	finished := metav1.NewTime(metav1.Now().Add(-1 * defaultDiscoveryPeriod))
+	for _, tc := range []struct {
+		name          string
+		migration     *v1alpha1.StorageVersionMigration
+		expectDeleted bool
+	}{
+		{
+			name:      "no ttl",
+			migration: storageMigration(withSucceededCondition(finished)),
+		},
+		{
+			name:          "expired",
+			migration:     storageMigration(withSucceededCondition(finished), withTTLSecondsAfterFinished(60)),
+			expectDeleted: true,
+		},
+		{
+			name:      "not expired",
+			migration: storageMigration(withSucceededCondition(finished), withTTLSecondsAfterFinished(3600)),
+		},
+		{
+			name:      "not finished",
+			migration: storageMigration(withTTLSecondsAfterFinished(0)),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.migration)
+			trigger := NewMigrationTrigger(client, DefaultOptions())
+			defer trigger.queue.ShutDown()
+			if err := trigger.expireMigration(context.TODO(), tc.migration); err != nil {
+				t.Fatal(err)
+			}
+			deleted := deletedNames(client.Actions(), "storageversionmigrations").Has(tc.migration.Name)
+			if deleted != tc.expectDeleted {
+				t.Errorf("expected deleted to be %v, got %v", tc.expectDeleted, deleted)
+			}
+		})
+	}
+}
+
+func TestExpireMigrationDedupesItems(t *testing.T) {
+	m := storageMigration(withSucceededCondition(metav1.Now()), withTTLSecondsAfterFinished(1))
+	client := fake.NewSimpleClientset(m)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	defer trigger.queue.ShutDown()
+
+	// Every update of the finished migration checks its expiry again.
+	for i := 0; i < 3; i++ {
+		if err := trigger.expireMigration(context.TODO(), m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		return trigger.queue.Len() > 0, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// All the items are due by now.
+	time.Sleep(100 * time.Millisecond)
+	if n := trigger.queue.Len(); n != 1 {
+		t.Fatalf("expected the items of the migration to be deduped, got %d items", n)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "storage_migrator"
+	subsystem = "trigger"
+)
+
+var (
+	// Metrics provides access to all trigger metrics.
+	Metrics = newTriggerMetrics()
+)
+
+// TriggerMetrics instruments the trigger with prometheus metrics.
+type TriggerMetrics struct {
+	migrationRequired *prometheus.GaugeVec
+	migrated          *prometheus.GaugeVec
+	launched          *prometheus.CounterVec
+}
+
+// newTriggerMetrics create a new TriggerMetrics, configured with default metric names.
+func newTriggerMetrics() *TriggerMetrics {
+	migrationRequired := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "migration_required",
+			Help:      "Whether a resource needs a migration that is neither pending nor running (1) or not (0), labeled with the full resource name.",
+		}, []string{"resource"})
+	prometheus.MustRegister(migrationRequired)
+
+	migrated := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "migrated",
+			Help:      "Whether all objects of a resource are encoded in the current storage version (1) or not (0), labeled with the full resource name.",
+		}, []string{"resource"})
+	prometheus.MustRegister(migrated)
+
+	launched := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "launched_migrations",
+			Help:      "The number of migrations launched by the trigger, labeled with the full resource name, and the reason of the migration.",
+		}, []string{"resource", "reason"})
+	prometheus.MustRegister(launched)
+
+	return &TriggerMetrics{
+		migrationRequired: migrationRequired,
+		migrated:          migrated,
+		launched:          launched,
+	}
+}
+
+func (m *TriggerMetrics) Reset() {
+	m.migrationRequired.Reset()
+	m.migrated.Reset()
+	m.launched.Reset()
+}
+
+// ObserveMigrationRequired records whether a resource needs a migration.
+func (m *TriggerMetrics) ObserveMigrationRequired(resource string, required bool) {
+	m.migrationRequired.WithLabelValues(resource).Set(boolToFloat(required))
+}
+
+// ObserveMigrated records whether a resource is migrated.
+func (m *TriggerMetrics) ObserveMigrated(resource string, migrated bool) {
+	m.migrated.WithLabelValues(resource).Set(boolToFloat(migrated))
+}
+
+// ObserveLaunchedMigration increments the number of migrations launched for a resource type.
+func (m *TriggerMetrics) ObserveLaunchedMigration(resource, reason string) {
+	m.launched.WithLabelValues(resource, reason).Add(float64(1))
+}
+
+// Forget removes the gauges of a resource that no longer exists.
+func (m *TriggerMetrics) Forget(resource string) {
+	m.migrationRequired.DeleteLabelValues(resource)
+	m.migrated.DeleteLabelValues(resource)
+}
+
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
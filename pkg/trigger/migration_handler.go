@@ -30,20 +30,12 @@ import (
 	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
 )
 
-func storageStateName(resource migrationv1alpha1.GroupVersionResource) string {
-	// TODO: add this rule to the CRD validation
-	// TODO: we might use ResourceID as the name in the future.
-	if resource.Group == "" {
-		return resource.Resource
-	}
-	return resource.Resource + "." + resource.Group
-}
-
-func (mt *MigrationTrigger) markStorageStateSucceeded(ctx context.Context, resource migrationv1alpha1.GroupVersionResource) error {
+func (mt *MigrationTrigger) markStorageStateSucceeded(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration) error {
+	resource := m.Spec.Resource
 	// We will retry on any error. Migrating a resource takes a long time.
 	// It would be a pity to give up just because of an update error.
 	return wait.ExponentialBackoff(backoff, func() (bool, error) {
-		ss, err := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, storageStateName(resource), metav1.GetOptions{})
+		ss, err := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, controller.StorageStateName(resource), metav1.GetOptions{})
 		if err != nil && !errors.IsNotFound(err) {
 			utilruntime.HandleError(err)
 			return false, nil
@@ -55,37 +47,91 @@ func (mt *MigrationTrigger) markStorageStateSucceeded(ctx context.Context, resou
 			// discovery routine to create the storage state.
 			return true, nil
 		}
+		old := ss.DeepCopy()
+		now := metav1.Now()
 		ss.Status.PersistedStorageVersionHashes = []string{ss.Status.CurrentStorageVersionHash}
-		_, err = mt.client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, ss, metav1.UpdateOptions{})
+		ss.Status.LastSucceededMigration = m.Name
+		resetFailedMigrationAttempts(ss)
+		recordStorageVersionHashHistory(ss, now)
+		mt.setMigratedCondition(ss, now)
+		inProgress := mt.unfinishedMigration(resource)
+		setMigrationInProgressCondition(ss, inProgress, now)
+		mt.setMigrationRequiredCondition(ss, inProgress, "", "", now)
+		updated, err := mt.client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, ss, metav1.UpdateOptions{})
 		if err != nil {
 			utilruntime.HandleError(err)
 			return false, nil
 		}
+		mt.reportStorageState(old, updated)
 		return true, nil
 	})
 }
 
+// isLatestMigration returns true if no other migration of the same resource
+// was created after m.
+func (mt *MigrationTrigger) isLatestMigration(m *migrationv1alpha1.StorageVersionMigration) bool {
+	migrations, err := mt.migrationInformer.GetIndexer().ByIndex(controller.ResourceIndex, controller.ToIndex(m.Spec.Resource))
+	if err != nil {
+		utilruntime.HandleError(err)
+		return true
+	}
+	for _, obj := range migrations {
+		other := obj.(*migrationv1alpha1.StorageVersionMigration)
+		if other.Name == m.Name {
+			continue
+		}
+		if m.CreationTimestamp.Before(&other.CreationTimestamp) {
+			return false
+		}
+		if m.CreationTimestamp.Equal(&other.CreationTimestamp) && !controller.IsFinished(other) {
+			return false
+		}
+	}
+	return true
+}
+
 func (mt *MigrationTrigger) processMigration(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration) error {
 	klog.V(2).Infof("processing migration %#v", m)
 	switch {
+	case (controller.HasCondition(m, migrationv1alpha1.MigrationSucceeded) || controller.HasCondition(m, migrationv1alpha1.MigrationFailed)) && !mt.isLatestMigration(m):
+		// Migrations are kept after they finish. An older migration
+		// might have migrated the resource to an older storage
+		// version, or its failure might have been retried already,
+		// so only the latest migration of a resource updates its
+		// storageState.
+		return mt.expireMigration(ctx, m)
 	case controller.HasCondition(m, migrationv1alpha1.MigrationSucceeded):
-		return mt.markStorageStateSucceeded(ctx, m.Spec.Resource)
+		if err := mt.markStorageStateSucceeded(ctx, m); err != nil {
+			return err
+		}
+		mt.updateSummary(ctx)
+		return mt.expireMigration(ctx, m)
 	case controller.HasCondition(m, migrationv1alpha1.MigrationFailed):
 		// The migration controller should have already tried its best
 		// to complete the migration before marking the migration as
-		// failed. There is nothing the triggering controller can do.
-		return nil
+		// failed. The triggering controller retries the migration
+		// later, as allowed by the retry policy of the resource.
+		if err := mt.markStorageStateFailed(ctx, m); err != nil {
+			return err
+		}
+		mt.updateSummary(ctx)
+		return mt.expireMigration(ctx, m)
+	case !controller.IsFinished(m):
+		return mt.markStorageStateInProgress(ctx, m)
 	default:
 		return nil
 	}
 }
 
 func (mt *MigrationTrigger) processQueue(ctx context.Context, obj interface{}) error {
-	item, ok := obj.(*queueItem)
+	if item, ok := obj.(retryItem); ok {
+		return mt.processRetry(ctx, item)
+	}
+	item, ok := obj.(queueItem)
 	if !ok {
 		return fmt.Errorf("expected queueItem, got %#v", reflect.TypeOf(obj))
 	}
-	// historic migrations are cleaned up when the controller observes
+	// unfinished migrations are superseded when the controller observes
 	// storage version changes in the discovery doc.
 	m, err := mt.client.MigrationV1alpha1().StorageVersionMigrations().Get(ctx, item.name, metav1.GetOptions{})
 	if err == nil {
@@ -93,9 +139,9 @@ func (mt *MigrationTrigger) processQueue(ctx context.Context, obj interface{}) e
 	}
 
 	if err != nil && errors.IsNotFound(err) {
-		// Likely the migration is deleted because mt is cleaning up
-		// migration history, in which case there is nothing to be
-		// done. If the migration is mistakenly removed by other
+		// Likely the migration is deleted because it expired, or its
+		// resource no longer exists, in which case there is nothing to
+		// be done. If the migration is mistakenly removed by other
 		// clients, the periodic discovery routine will restart the
 		// migration.
 		return nil
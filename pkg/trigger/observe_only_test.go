@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+)
+
+type fakeEventRecorder struct {
+	reasons []string
+}
+
+func (r *fakeEventRecorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.reasons = append(r.reasons, reason)
+}
+
+func observeOnlyTrigger(t *testing.T, client *fake.Clientset, recorder EventRecorder) (*MigrationTrigger, func()) {
+	options := DefaultOptions()
+	options.ObserveOnly = true
+	options.EventRecorder = recorder
+	trigger := NewMigrationTrigger(client, options)
+	stopCh := make(chan struct{})
+	go trigger.migrationInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+		t.Fatal("Unable to sync caches")
+	}
+	return trigger, func() { close(stopCh) }
+}
+
+func TestProcessDiscoveryResourceObserveOnly(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	recorder := &fakeEventRecorder{}
+	trigger, stop := observeOnlyTrigger(t, client, recorder)
+	defer stop()
+	trigger.heartbeat = metav1.Now()
+	trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+	if n := countCreatedMigrations(client.Actions()); n != 0 {
+		t.Fatalf("expected no migration to be created, got %d", n)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationRequired, v1.ConditionTrue, v1alpha1.MigrationReasonNewResource)
+	if e, a := []string{v1alpha1.MigrationReasonNewResource}, recorder.reasons; len(a) != 1 || a[0] != e[0] {
+		t.Errorf("expected events %v, got %v", e, a)
+	}
+
+	// The next discovery keeps the reason, and records no event.
+	trigger.heartbeat = metav1.Now()
+	trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+	ss = lastStorageStateUpdate(t, client.Actions())
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationRequired, v1.ConditionTrue, v1alpha1.MigrationReasonNewResource)
+	if len(recorder.reasons) != 1 {
+		t.Errorf("expected no more events, got %v", recorder.reasons)
+	}
+}
+
+func TestProcessMigrationObserveOnly(t *testing.T) {
+	succeeded := storageMigration(withName("pods-1"), withSucceededCondition(metav1.Now()), withTTLSecondsAfterFinished(0))
+	client := fake.NewSimpleClientset(
+		succeeded,
+		storageState(withCurrentVersion("newhash"), withPersistedVersions("oldhash", "newhash")),
+	)
+	recorder := &fakeEventRecorder{}
+	trigger, stop := observeOnlyTrigger(t, client, recorder)
+	defer stop()
+	if err := trigger.processMigration(context.TODO(), succeeded); err != nil {
+		t.Fatal(err)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrated, v1.ConditionTrue, "StorageVersionMigrated")
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationRequired, v1.ConditionFalse, "StorageVersionMigrated")
+	if deletedNames(client.Actions(), "storageversionmigrations").Len() != 0 {
+		t.Errorf("expected the expired migration to be kept, got %v", client.Actions())
+	}
+	if len(recorder.reasons) != 1 || recorder.reasons[0] != "Migrated" {
+		t.Errorf("expected a Migrated event, got %v", recorder.reasons)
+	}
+}
+
+func TestGarbageCollectStorageStatesObserveOnly(t *testing.T) {
+	widgets := v1alpha1.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	client := fake.NewSimpleClientset(
+		storageState(withStorageStateResource("example.com", "widgets"), withHeartbeat(metav1.NewTime(metav1.Now().Add(-2*defaultStorageStateGracePeriod)))),
+		storageMigration(withName("widgets-migration"), withResource(widgets)),
+	)
+	trigger, stop := observeOnlyTrigger(t, client, nil)
+	defer stop()
+	trigger.heartbeat = metav1.Now()
+	trigger.garbageCollectStorageStates(context.TODO(), trigger.markStaleStorageStates(context.TODO(), sets.NewString(), sets.NewString()))
+	if e, a := sets.NewString("widgets.example.com"), deletedNames(client.Actions(), "storagestates"); !e.Equal(a) {
+		t.Errorf("expected deleted storageStates %v, got %v", e.List(), a.List())
+	}
+	if a := deletedNames(client.Actions(), "storageversionmigrations"); a.Len() != 0 {
+		t.Errorf("expected no deleted migrations, got %v", a.List())
+	}
+}
+
+func TestEventRecorder(t *testing.T) {
+	kube := kubefake.NewSimpleClientset()
+	recorder := NewEventRecorder(kube.CoreV1(), "test")
+	recorder.Eventf(storageState(), v1.EventTypeNormal, "Migrated", "migration %s succeeded", "pods-1")
+	events, err := kube.CoreV1().Events(metav1.NamespaceDefault).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.Items) != 1 {
+		t.Fatalf("expected one event, got %v", events.Items)
+	}
+	e := events.Items[0]
+	if e.InvolvedObject.Kind != "StorageState" || e.InvolvedObject.Name != "pods" || e.Reason != "Migrated" || e.Message != "migration pods-1 succeeded" || e.Source.Component != "test" {
+		t.Errorf("unexpected event %#v", e)
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// refreshPlans lists the migration plans, and records the resources migrated
+// by the active ones. The previous plans are kept if the list fails.
+func (mt *MigrationTrigger) refreshPlans(ctx context.Context) {
+	l, err := mt.client.MigrationV1alpha1().MigrationPlans().List(ctx, metav1.ListOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		// The CRD of the plans is not installed.
+		mt.plannedResources = nil
+		return
+	case err != nil:
+		utilruntime.HandleError(fmt.Errorf("failed to list the migration plans, keeping the previous ones: %v", err))
+		return
+	}
+	planned := map[string]string{}
+	for i := range l.Items {
+		plan := &l.Items[i]
+		if !planActive(plan) {
+			continue
+		}
+		for _, step := range plan.Spec.Steps {
+			planned[controller.ResourceName(step.Resource.Group, step.Resource.Resource)] = plan.Name
+		}
+	}
+	mt.plannedResources = planned
+}
+
+// planActive returns true if the plan is valid and has not succeeded yet, in
+// which case the plan controller, not the trigger, migrates its resources.
+func planActive(plan *migrationv1alpha1.MigrationPlan) bool {
+	for _, c := range plan.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		if c.Type == migrationv1alpha1.MigrationPlanSucceeded || c.Type == migrationv1alpha1.MigrationPlanInvalid {
+			return false
+		}
+	}
+	return true
+}
+
+// plannedBy returns the active migration plan migrating the resource, or ""
+// if there is none.
+func (mt *MigrationTrigger) plannedBy(resource migrationv1alpha1.GroupVersionResource) string {
+	return mt.plannedResources[controller.ResourceName(resource.Group, resource.Resource)]
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+)
+
+func newPodsPlan(conditions ...v1alpha1.MigrationPlanConditionType) *v1alpha1.MigrationPlan {
+	plan := &v1alpha1.MigrationPlan{
+		ObjectMeta: metav1.ObjectMeta{Name: "upgrade"},
+		Spec: v1alpha1.MigrationPlanSpec{
+			Steps: []v1alpha1.MigrationPlanStep{
+				{Name: "pods", Resource: v1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"}},
+			},
+		},
+	}
+	for _, c := range conditions {
+		plan.Status.Conditions = append(plan.Status.Conditions, v1alpha1.MigrationPlanCondition{Type: c, Status: v1.ConditionTrue})
+	}
+	return plan
+}
+
+func TestPlanOwnsMigration(t *testing.T) {
+	tests := []struct {
+		name           string
+		plan           *v1alpha1.MigrationPlan
+		expectLaunched int
+	}{
+		{"active plan", newPodsPlan(), 0},
+		{"succeeded plan", newPodsPlan(v1alpha1.MigrationPlanSucceeded), 1},
+		{"invalid plan", newPodsPlan(v1alpha1.MigrationPlanInvalid), 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(test.plan)
+			trigger := NewMigrationTrigger(client, DefaultOptions())
+			trigger.heartbeat = metav1.Now()
+			trigger.refreshPlans(context.TODO())
+			trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+			if n := countCreatedMigrations(client.Actions()); n != test.expectLaunched {
+				t.Fatalf("expected %d migrations to be created, got %d", test.expectLaunched, n)
+			}
+			if test.expectLaunched != 0 {
+				return
+			}
+			// The storageState still reports the resource needs a
+			// migration.
+			ss := lastStorageStateUpdate(t, client.Actions())
+			expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationRequired, v1.ConditionTrue, v1alpha1.MigrationReasonNewResource)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+// maxReportedConflicts limits the number of conflicts listed in the message
+// of the Conflicted condition of a policy.
+const maxReportedConflicts = 5
+
+// refreshPolicies lists the migration policies. The previous policies are
+// kept if the list fails.
+func (mt *MigrationTrigger) refreshPolicies(ctx context.Context) {
+	l, err := mt.client.MigrationV1alpha1().MigrationPolicies().List(ctx, metav1.ListOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		// The CRD of the policies is not installed.
+		mt.policies = nil
+	case err != nil:
+		utilruntime.HandleError(fmt.Errorf("failed to list the migration policies, keeping the previous ones: %v", err))
+	default:
+		mt.policies = controller.NewMigrationPolicies(l.Items)
+	}
+}
+
+// migrationAllowed returns true if the trigger launches migrations of the
+// resource. The action of the migration policies takes precedence over the
+// resource rules. If the migrations are not allowed, it also returns why.
+func (mt *MigrationTrigger) migrationAllowed(resource migrationv1alpha1.GroupVersionResource) (bool, string) {
+	policy := mt.policies.Resolve(resource.Group, resource.Resource)
+	switch policy.Action {
+	case migrationv1alpha1.MigrationPolicyActionSkip:
+		return false, fmt.Sprintf("migration policy %s skips it", policy.Sources["action"])
+	case migrationv1alpha1.MigrationPolicyActionMigrate:
+		return true, ""
+	}
+	return mt.resourceFilter.Allowed(resource.Group, resource.Resource)
+}
+
+// updatePolicyStatuses records in the status of every migration policy the
+// number of discovered resources it matches, and the settings that do not
+// apply because of other policies.
+func (mt *MigrationTrigger) updatePolicyStatuses(ctx context.Context, resources []migrationv1alpha1.GroupVersionResource) {
+	policies := mt.policies.Policies()
+	matched := map[string]int32{}
+	conflicts := map[string][]string{}
+	for _, r := range resources {
+		for i := range policies {
+			if controller.PolicyMatches(&policies[i], r.Group, r.Resource) {
+				matched[policies[i].Name]++
+			}
+		}
+		for _, c := range mt.policies.Resolve(r.Group, r.Resource).Conflicts {
+			conflicts[c.Policy] = append(conflicts[c.Policy], fmt.Sprintf("%s of %s is set by %s", c.Field, controller.ResourceName(r.Group, r.Resource), c.By))
+		}
+	}
+	for i := range policies {
+		policy := policies[i].DeepCopy()
+		status := migrationv1alpha1.MigrationPolicyStatus{
+			ObservedGeneration: policy.Generation,
+			MatchedResources:   matched[policy.Name],
+			Conditions:         policy.Status.Conditions,
+		}
+		condition := migrationv1alpha1.MigrationPolicyCondition{
+			Type:           migrationv1alpha1.MigrationPolicyConflicted,
+			Status:         corev1.ConditionFalse,
+			LastUpdateTime: metav1.Now(),
+			Reason:         "NoConflicts",
+		}
+		if c := conflicts[policy.Name]; len(c) > 0 {
+			condition.Status = corev1.ConditionTrue
+			condition.Reason = "OverriddenByPolicy"
+			condition.Message = conflictsMessage(c)
+		}
+		status.Conditions = controller.SetPolicyCondition(status.Conditions, condition)
+		if equality.Semantic.DeepEqual(policy.Status, status) {
+			continue
+		}
+		policy.Status = status
+		if _, err := mt.client.MigrationV1alpha1().MigrationPolicies().UpdateStatus(ctx, policy, metav1.UpdateOptions{}); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to update the status of the migration policy %s: %v", policy.Name, err))
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue {
+			klog.V(2).Infof("migration policy %s is overridden: %s", policy.Name, condition.Message)
+		}
+	}
+}
+
+func conflictsMessage(conflicts []string) string {
+	if len(conflicts) <= maxReportedConflicts {
+		return strings.Join(conflicts, "; ")
+	}
+	return fmt.Sprintf("%s; and %d more", strings.Join(conflicts[:maxReportedConflicts], "; "), len(conflicts)-maxReportedConflicts)
+}
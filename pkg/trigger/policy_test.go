@@ -0,0 +1,164 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	core "k8s.io/client-go/testing"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+func newPodsPolicy(name string, priority int32) *v1alpha1.MigrationPolicy {
+	return &v1alpha1.MigrationPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Generation: 1},
+		Spec: v1alpha1.MigrationPolicySpec{
+			Resources: []v1alpha1.ResourcePattern{{Resource: "pods"}},
+			Priority:  priority,
+		},
+	}
+}
+
+func policyStatusUpdates(actions []core.Action) map[string]*v1alpha1.MigrationPolicy {
+	updates := map[string]*v1alpha1.MigrationPolicy{}
+	for _, a := range actions {
+		u, ok := a.(core.UpdateAction)
+		if !ok || u.GetResource().Resource != "migrationpolicies" || u.GetSubresource() != "status" {
+			continue
+		}
+		policy := u.GetObject().(*v1alpha1.MigrationPolicy)
+		updates[policy.Name] = policy
+	}
+	return updates
+}
+
+func TestPolicySkipsMigration(t *testing.T) {
+	skip := newPodsPolicy("skip-pods", 0)
+	skip.Spec.Action = v1alpha1.MigrationPolicyActionSkip
+	client := fake.NewSimpleClientset(skip)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	trigger.heartbeat = metav1.Now()
+	trigger.refreshPolicies(context.TODO())
+	trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+	if n := countCreatedMigrations(client.Actions()); n != 0 {
+		t.Fatalf("expected no migration to be created, got %d", n)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationRequired, v1.ConditionTrue, v1alpha1.MigrationReasonNewResource)
+}
+
+func TestPolicyMigrateOverridesResourceRules(t *testing.T) {
+	migrate := newPodsPolicy("migrate-pods", 0)
+	migrate.Spec.Action = v1alpha1.MigrationPolicyActionMigrate
+	client := fake.NewSimpleClientset(migrate)
+	options := DefaultOptions()
+	options.ResourceRules = controller.ResourceRules{ExcludeResources: []string{"pods"}}
+	trigger := NewMigrationTrigger(client, options)
+	trigger.heartbeat = metav1.Now()
+	trigger.refreshPolicies(context.TODO())
+	trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+	if n := countCreatedMigrations(client.Actions()); n != 1 {
+		t.Fatalf("expected a migration to be created, got %d", n)
+	}
+}
+
+func TestPolicyMigrationPriority(t *testing.T) {
+	critical := newPodsPolicy("critical-pods", 0)
+	priority := int32(100)
+	critical.Spec.MigrationPriority = &priority
+	client := fake.NewSimpleClientset(critical)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	trigger.heartbeat = metav1.Now()
+	trigger.refreshPolicies(context.TODO())
+	trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+	var created []*v1alpha1.StorageVersionMigration
+	for _, a := range client.Actions() {
+		if c, ok := a.(core.CreateAction); ok && c.GetResource().Resource == "storageversionmigrations" {
+			created = append(created, c.GetObject().(*v1alpha1.StorageVersionMigration))
+		}
+	}
+	if len(created) != 1 || created[0].Spec.Priority != 100 {
+		t.Fatalf("expected a migration of priority 100, got %v", created)
+	}
+}
+
+func TestPolicyRetry(t *testing.T) {
+	retry := newPodsPolicy("retry-pods", 0)
+	maxAttempts := int32(10)
+	retry.Spec.Retry = &v1alpha1.MigrationPolicyRetry{
+		MaxAttempts:    &maxAttempts,
+		InitialBackoff: &metav1.Duration{Duration: time.Second},
+	}
+	trigger := NewMigrationTrigger(fake.NewSimpleClientset(retry), DefaultOptions())
+	trigger.refreshPolicies(context.TODO())
+	p := trigger.retryPolicy(v1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"})
+	if p.MaxAttempts != 10 || p.InitialBackoff != time.Second || p.MaxBackoff != defaultRetryMaxBackoff {
+		t.Errorf("expected the retry settings of the policy over the defaults, got %+v", p)
+	}
+	p = trigger.retryPolicy(v1alpha1.GroupVersionResource{Version: "v1", Resource: "nodes"})
+	if p != DefaultRetryPolicy() {
+		t.Errorf("expected the default retry policy for nodes, got %+v", p)
+	}
+}
+
+func TestUpdatePolicyStatuses(t *testing.T) {
+	high := newPodsPolicy("high", 10)
+	high.Spec.Strategy = v1alpha1.MigrationStrategyDryRunFirst
+	low := newPodsPolicy("low", 0)
+	low.Spec.Strategy = v1alpha1.MigrationStrategyDirect
+	client := fake.NewSimpleClientset(high, low)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	trigger.refreshPolicies(context.TODO())
+	resources := []v1alpha1.GroupVersionResource{
+		{Version: "v1", Resource: "pods"},
+		{Version: "v1", Resource: "nodes"},
+	}
+	trigger.updatePolicyStatuses(context.TODO(), resources)
+
+	updates := policyStatusUpdates(client.Actions())
+	if len(updates) != 2 {
+		t.Fatalf("expected the statuses of the two policies to be updated, got %v", updates)
+	}
+	for name, conflicted := range map[string]v1.ConditionStatus{"high": v1.ConditionFalse, "low": v1.ConditionTrue} {
+		status := updates[name].Status
+		if status.ObservedGeneration != 1 || status.MatchedResources != 1 {
+			t.Errorf("expected policy %s to observe generation 1 and match 1 resource, got %+v", name, status)
+		}
+		if len(status.Conditions) != 1 || status.Conditions[0].Type != v1alpha1.MigrationPolicyConflicted || status.Conditions[0].Status != conflicted {
+			t.Errorf("expected policy %s to have condition Conflicted=%s, got %+v", name, conflicted, status.Conditions)
+		}
+	}
+	if e, a := "strategy of pods is set by high", updates["low"].Status.Conditions[0].Message; !strings.Contains(a, e) {
+		t.Errorf("expected message to contain %q, got %q", e, a)
+	}
+
+	// Unchanged statuses are not updated again.
+	client.ClearActions()
+	trigger.refreshPolicies(context.TODO())
+	trigger.updatePolicyStatuses(context.TODO(), resources)
+	if updates := policyStatusUpdates(client.Actions()); len(updates) != 0 {
+		t.Errorf("expected no status update, got %v", updates)
+	}
+}
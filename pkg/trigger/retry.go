@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+const (
+	defaultRetryMaxAttempts    = 5
+	defaultRetryInitialBackoff = time.Minute
+	defaultRetryMaxBackoff     = time.Hour
+)
+
+// RetryPolicy controls how the trigger retries migrating a resource after
+// its migration failed.
+type RetryPolicy struct {
+	// MaxAttempts is the number of consecutive failed migrations after
+	// which the trigger gives up migrating the resource. Zero means the
+	// trigger never gives up.
+	MaxAttempts int32
+	// InitialBackoff is the delay before retrying after the first failed
+	// migration. The delay doubles after every following failure.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between retries. Zero means one hour.
+	MaxBackoff time.Duration
+}
+
+// DefaultRetryPolicy returns the default RetryPolicy.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    defaultRetryMaxAttempts,
+		InitialBackoff: defaultRetryInitialBackoff,
+		MaxBackoff:     defaultRetryMaxBackoff,
+	}
+}
+
+// backoff returns the delay before retrying after the given number of
+// consecutive failed migrations. The doubling stops at the cap, so that it
+// does not overflow.
+func (p RetryPolicy) backoff(failedAttempts int32) time.Duration {
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = defaultRetryMaxBackoff
+	}
+	d := p.InitialBackoff
+	for i := int32(1); i < failedAttempts && d > 0 && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// retryPolicy returns the RetryPolicy of the resource. The retry settings of
+// the migration policies take precedence over the options of the trigger.
+func (mt *MigrationTrigger) retryPolicy(resource migrationv1alpha1.GroupVersionResource) RetryPolicy {
+	p, ok := mt.options.ResourceRetryPolicies[controller.StorageStateName(resource)]
+	if !ok {
+		p = mt.options.RetryPolicy
+	}
+	policy := mt.policies.Resolve(resource.Group, resource.Resource)
+	if policy.RetryMaxAttempts != nil {
+		p.MaxAttempts = *policy.RetryMaxAttempts
+	}
+	if policy.RetryInitialBackoff != nil {
+		p.InitialBackoff = *policy.RetryInitialBackoff
+	}
+	if policy.RetryMaxBackoff != nil {
+		p.MaxBackoff = *policy.RetryMaxBackoff
+	}
+	return p
+}
+
+// retryItem is the object in the workqueue that requests retrying the
+// migration of a resource. It is queued by value, so that the workqueue
+// dedupes the retries of a resource.
+type retryItem struct {
+	resource migrationv1alpha1.GroupVersionResource
+}
+
+// retryAllowed returns false if the trigger has given up migrating the
+// resource of the storageState, or if it's too early to retry.
+func (mt *MigrationTrigger) retryAllowed(ss *migrationv1alpha1.StorageState) bool {
+	if controller.HasStorageStateCondition(ss, migrationv1alpha1.StorageStateGaveUp) {
+		return false
+	}
+	return ss.Status.NextMigrationRetryTime == nil || !time.Now().Before(ss.Status.NextMigrationRetryTime.Time)
+}
+
+// resetFailedMigrationAttempts forgets the failed migrations recorded in the
+// storageState.
+func resetFailedMigrationAttempts(ss *migrationv1alpha1.StorageState) {
+	ss.Status.FailedMigrationAttempts = 0
+	ss.Status.LastFailedMigration = ""
+	ss.Status.NextMigrationRetryTime = nil
+	controller.RemoveStorageStateCondition(ss, migrationv1alpha1.StorageStateGaveUp)
+}
+
+// markStorageStateFailed records the failed migration m in the storageState
+// of its resource. It schedules a retry, unless the retry policy of the
+// resource allows no more attempts, in which case the storageState is marked
+// with the GaveUp condition. Nothing is retried if the trigger is observe
+// only.
+func (mt *MigrationTrigger) markStorageStateFailed(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration) error {
+	policy := mt.retryPolicy(m.Spec.Resource)
+	var retryAfter time.Duration
+	// We will retry on any error, see markStorageStateSucceeded.
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		retryAfter = 0
+		ss, err := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, controller.StorageStateName(m.Spec.Resource), metav1.GetOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			utilruntime.HandleError(err)
+			return false, nil
+		}
+		if err != nil && errors.IsNotFound(err) {
+			// The next discovery routine creates the storage
+			// state and launches a new migration.
+			utilruntime.HandleError(err)
+			return true, nil
+		}
+		if ss.Status.LastFailedMigration == m.Name {
+			// The failure has been counted.
+			return true, nil
+		}
+		message := ""
+		if c := controller.GetCondition(m, migrationv1alpha1.MigrationFailed); c != nil {
+			message = c.Message
+		}
+		ss.Status.FailedMigrationAttempts++
+		ss.Status.LastFailedMigration = m.Name
+		ss.Status.LastMigrationFailureMessage = message
+		setMigrationInProgressCondition(ss, mt.unfinishedMigration(m.Spec.Resource), metav1.Now())
+		switch {
+		case mt.options.ObserveOnly:
+			// Another process is responsible for retrying.
+		case policy.MaxAttempts > 0 && ss.Status.FailedMigrationAttempts >= policy.MaxAttempts:
+			ss.Status.NextMigrationRetryTime = nil
+			controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+				Type:           migrationv1alpha1.StorageStateGaveUp,
+				Status:         corev1.ConditionTrue,
+				LastUpdateTime: metav1.Now(),
+				Reason:         "MaxAttemptsReached",
+				Message:        fmt.Sprintf("gave up after %d failed migrations, the last migration %s failed: %s", ss.Status.FailedMigrationAttempts, m.Name, message),
+			})
+		default:
+			retryAfter = policy.backoff(ss.Status.FailedMigrationAttempts)
+			next := metav1.NewTime(time.Now().Add(retryAfter))
+			ss.Status.NextMigrationRetryTime = &next
+		}
+		_, err = mt.client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, ss, metav1.UpdateOptions{})
+		if err != nil {
+			utilruntime.HandleError(err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	if retryAfter > 0 {
+		klog.V(2).Infof("migration %s failed, retrying in %v", m.Name, retryAfter)
+		mt.queue.AddAfter(retryItem{resource: m.Spec.Resource}, retryAfter)
+	}
+	return nil
+}
+
+// processRetry launches a new migration for the resource of the item, if the
+// resource is still not migrated and its retry policy allows it.
+func (mt *MigrationTrigger) processRetry(ctx context.Context, item retryItem) error {
+	ss, err := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, controller.StorageStateName(item.resource), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// The next discovery routine handles the resource.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if mt.isMigrated(ss) || controller.HasStorageStateCondition(ss, migrationv1alpha1.StorageStateGaveUp) {
+		return nil
+	}
+	if !mt.retryAllowed(ss) {
+		mt.queue.AddAfter(item, time.Until(ss.Status.NextMigrationRetryTime.Time))
+		return nil
+	}
+	if mt.hasPendingOrRunningMigration(item.resource) {
+		return nil
+	}
+	message := fmt.Sprintf("retrying after %d failed migrations, the last migration %s failed", ss.Status.FailedMigrationAttempts, ss.Status.LastFailedMigration)
+	_, err = mt.relaunchMigration(ctx, item.resource, ss.Status.CurrentStorageVersionHash, migrationv1alpha1.MigrationReasonPreviousMigrationFailed, message)
+	return err
+}
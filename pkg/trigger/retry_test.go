@@ -0,0 +1,296 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Minute, MaxBackoff: 5 * time.Minute}
+	for attempts, expected := range map[int32]time.Duration{
+		1: time.Minute,
+		2: 2 * time.Minute,
+		3: 4 * time.Minute,
+		4: 5 * time.Minute,
+		9: 5 * time.Minute,
+	} {
+		if a := policy.backoff(attempts); a != expected {
+			t.Errorf("expected backoff %v after %d attempts, got %v", expected, attempts, a)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffDefaultCap(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Minute}
+	for _, attempts := range []int32{7, 64, math.MaxInt32} {
+		if a := policy.backoff(attempts); a != defaultRetryMaxBackoff {
+			t.Errorf("expected backoff %v after %d attempts, got %v", defaultRetryMaxBackoff, attempts, a)
+		}
+	}
+}
+
+func withFailedAttempts(attempts int32, lastFailed string, nextRetry time.Time) func(*v1alpha1.StorageState) {
+	return func(ss *v1alpha1.StorageState) {
+		ss.Status.FailedMigrationAttempts = attempts
+		ss.Status.LastFailedMigration = lastFailed
+		next := metav1.NewTime(nextRetry)
+		ss.Status.NextMigrationRetryTime = &next
+	}
+}
+
+func withGaveUp() func(*v1alpha1.StorageState) {
+	return func(ss *v1alpha1.StorageState) {
+		ss.Status.Conditions = append(ss.Status.Conditions, v1alpha1.StorageStateCondition{
+			Type:   v1alpha1.StorageStateGaveUp,
+			Status: v1.ConditionTrue,
+		})
+	}
+}
+
+func lastStorageStateUpdate(t *testing.T, actions []core.Action) *v1alpha1.StorageState {
+	for i := len(actions) - 1; i >= 0; i-- {
+		u, ok := actions[i].(core.UpdateAction)
+		if !ok || u.GetResource().Resource != "storagestates" {
+			continue
+		}
+		return u.GetObject().(*v1alpha1.StorageState)
+	}
+	t.Fatalf("expected storageState update, got %v", actions)
+	return nil
+}
+
+func countCreatedMigrations(actions []core.Action) int {
+	count := 0
+	for _, a := range actions {
+		if c, ok := a.(core.CreateAction); ok && c.GetResource().Resource == "storageversionmigrations" {
+			count++
+		}
+	}
+	return count
+}
+
+func TestMarkStorageStateFailed(t *testing.T) {
+	failed := storageMigration(withName("pods-1"), withFailedCondition())
+	client := fake.NewSimpleClientset(
+		failed,
+		storageState(withCurrentVersion("newhash"), withPersistedVersions(v1alpha1.Unknown)),
+	)
+	options := DefaultOptions()
+	options.RetryPolicy = RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Hour}
+	trigger := NewMigrationTrigger(client, options)
+	defer trigger.queue.ShutDown()
+
+	if err := trigger.markStorageStateFailed(context.TODO(), failed); err != nil {
+		t.Fatal(err)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	if ss.Status.FailedMigrationAttempts != 1 || ss.Status.LastFailedMigration != "pods-1" {
+		t.Fatalf("expected one failed attempt by pods-1, got %d by %q", ss.Status.FailedMigrationAttempts, ss.Status.LastFailedMigration)
+	}
+	if ss.Status.NextMigrationRetryTime == nil || time.Until(ss.Status.NextMigrationRetryTime.Time) < 59*time.Minute {
+		t.Fatalf("expected a retry in an hour, got %v", ss.Status.NextMigrationRetryTime)
+	}
+
+	// The same failure is counted once.
+	client.ClearActions()
+	if err := trigger.markStorageStateFailed(context.TODO(), failed); err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range client.Actions() {
+		if _, ok := a.(core.UpdateAction); ok {
+			t.Fatalf("unexpected update %v", a)
+		}
+	}
+
+	// The second failure reaches the maximum attempts.
+	if err := trigger.markStorageStateFailed(context.TODO(), storageMigration(withName("pods-2"), withFailedCondition())); err != nil {
+		t.Fatal(err)
+	}
+	ss = lastStorageStateUpdate(t, client.Actions())
+	if ss.Status.FailedMigrationAttempts != 2 || ss.Status.NextMigrationRetryTime != nil {
+		t.Fatalf("expected two failed attempts and no retry, got %d and %v", ss.Status.FailedMigrationAttempts, ss.Status.NextMigrationRetryTime)
+	}
+	if !controller.HasStorageStateCondition(ss, v1alpha1.StorageStateGaveUp) {
+		t.Fatalf("expected the GaveUp condition, got %v", ss.Status.Conditions)
+	}
+}
+
+func TestMarkStorageStateFailedDedupesRetries(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		storageState(withCurrentVersion("newhash"), withPersistedVersions(v1alpha1.Unknown)),
+	)
+	options := DefaultOptions()
+	options.RetryPolicy = RetryPolicy{InitialBackoff: time.Millisecond}
+	trigger := NewMigrationTrigger(client, options)
+	defer trigger.queue.ShutDown()
+
+	for _, name := range []string{"pods-1", "pods-2"} {
+		if err := trigger.markStorageStateFailed(context.TODO(), storageMigration(withName(name), withFailedCondition())); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := wait.PollImmediate(time.Millisecond, 5*time.Second, func() (bool, error) {
+		return trigger.queue.Len() > 0, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Both retries are due by now.
+	time.Sleep(100 * time.Millisecond)
+	if n := trigger.queue.Len(); n != 1 {
+		t.Fatalf("expected the retries of the resource to be deduped, got %d items", n)
+	}
+}
+
+func TestProcessDiscoveryResourceRetry(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		storageState *v1alpha1.StorageState
+		expectLaunch bool
+	}{
+		{
+			name: "backing off",
+			storageState: storageState(
+				withFreshHeartbeat(),
+				withCurrentVersion("newhash"),
+				withPersistedVersions(v1alpha1.Unknown),
+				withFailedAttempts(1, "pods", time.Now().Add(time.Hour)),
+			),
+		},
+		{
+			name: "backoff expired",
+			storageState: storageState(
+				withFreshHeartbeat(),
+				withCurrentVersion("newhash"),
+				withPersistedVersions(v1alpha1.Unknown),
+				withFailedAttempts(1, "pods", time.Now().Add(-time.Minute)),
+			),
+			expectLaunch: true,
+		},
+		{
+			name: "gave up",
+			storageState: storageState(
+				withFreshHeartbeat(),
+				withCurrentVersion("newhash"),
+				withPersistedVersions(v1alpha1.Unknown),
+				withGaveUp(),
+			),
+		},
+		{
+			name: "gave up on a previous storage version",
+			storageState: storageState(
+				withFreshHeartbeat(),
+				withCurrentVersion("oldhash"),
+				withPersistedVersions(v1alpha1.Unknown),
+				withGaveUp(),
+			),
+			expectLaunch: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(storageMigration(withFailedCondition()), tc.storageState)
+			trigger := NewMigrationTrigger(client, DefaultOptions())
+			stopCh := make(chan struct{})
+			defer close(stopCh)
+			go trigger.migrationInformer.Run(stopCh)
+			if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+				utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+				return
+			}
+			trigger.heartbeat = metav1.Now()
+			trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+			if a, e := countCreatedMigrations(client.Actions()) == 1, tc.expectLaunch; a != e {
+				t.Fatalf("expected launch %v, got actions %v", e, client.Actions())
+			}
+			ss := lastStorageStateUpdate(t, client.Actions())
+			if tc.storageState.Status.CurrentStorageVersionHash != "newhash" && controller.HasStorageStateCondition(ss, v1alpha1.StorageStateGaveUp) {
+				t.Fatalf("expected the GaveUp condition to be cleared, got %v", ss.Status.Conditions)
+			}
+		})
+	}
+}
+
+func TestProcessRetry(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		storageMigration(withFailedCondition()),
+		storageState(
+			withFreshHeartbeat(),
+			withCurrentVersion("newhash"),
+			withPersistedVersions(v1alpha1.Unknown),
+			withFailedAttempts(1, "pods", time.Now().Add(-time.Minute)),
+		),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go trigger.migrationInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+		return
+	}
+	item := retryItem{resource: v1alpha1.GroupVersionResource{Version: "v1", Resource: "pods"}}
+	if err := trigger.processQueue(context.TODO(), item); err != nil {
+		t.Fatal(err)
+	}
+	actions := client.Actions()
+	m := expectCreateStorageVersionMigrationAction(t, actions[len(actions)-1])
+	if a, e := m.Annotations[v1alpha1.MigrationReasonAnnotation], v1alpha1.MigrationReasonPreviousMigrationFailed; a != e {
+		t.Fatalf("expected reason %q, got %q", e, a)
+	}
+}
+
+func TestProcessMigrationIgnoresOlderMigrations(t *testing.T) {
+	older := storageMigration(withName("pods-1"), withSucceededCondition(metav1.Now()))
+	older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	newer := storageMigration(withName("pods-2"))
+	newer.CreationTimestamp = metav1.Now()
+	client := fake.NewSimpleClientset(
+		older,
+		newer,
+		storageState(withCurrentVersion("newhash"), withPersistedVersions("oldhash", "newhash")),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go trigger.migrationInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+		return
+	}
+	client.ClearActions()
+	if err := trigger.processMigration(context.TODO(), older); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Actions()) != 0 {
+		t.Fatalf("expected the older migration to be ignored, got %v", client.Actions())
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/trigger/metrics"
+)
+
+// maxStorageVersionHashHistory is the number of records in
+// .status.storageVersionHashHistory above which the oldest removed hashes
+// are dropped.
+const maxStorageVersionHashHistory = 10
+
+// recordStorageVersionHashHistory updates the storageVersionHashHistory of
+// the storageState to match its persistedStorageVersionHashes, marking the
+// hashes that are no longer persisted as removed, and adding the newly
+// persisted ones.
+func recordStorageVersionHashHistory(ss *migrationv1alpha1.StorageState, now metav1.Time) {
+	persisted := sets.NewString(ss.Status.PersistedStorageVersionHashes...)
+	recorded := sets.NewString()
+	for i := range ss.Status.StorageVersionHashHistory {
+		r := &ss.Status.StorageVersionHashHistory[i]
+		if r.RemovedTime != nil {
+			continue
+		}
+		if !persisted.Has(r.Hash) {
+			removed := now
+			r.RemovedTime = &removed
+			continue
+		}
+		recorded.Insert(r.Hash)
+	}
+	for _, hash := range ss.Status.PersistedStorageVersionHashes {
+		if recorded.Has(hash) {
+			continue
+		}
+		ss.Status.StorageVersionHashHistory = append(ss.Status.StorageVersionHashHistory, migrationv1alpha1.StorageVersionHashRecord{
+			Hash:              hash,
+			FirstObservedTime: now,
+		})
+		recorded.Insert(hash)
+	}
+	for len(ss.Status.StorageVersionHashHistory) > maxStorageVersionHashHistory {
+		oldest := -1
+		for i, r := range ss.Status.StorageVersionHashHistory {
+			if r.RemovedTime != nil {
+				oldest = i
+				break
+			}
+		}
+		if oldest < 0 {
+			break
+		}
+		history := ss.Status.StorageVersionHashHistory
+		ss.Status.StorageVersionHashHistory = append(history[:oldest:oldest], history[oldest+1:]...)
+	}
+}
+
+// setMigratedCondition sets the Migrated condition of the storageState.
+func (mt *MigrationTrigger) setMigratedCondition(ss *migrationv1alpha1.StorageState, now metav1.Time) {
+	if mt.isMigrated(ss) {
+		message := "all objects are encoded in the current storage version"
+		if ss.Status.LastSucceededMigration != "" {
+			message = fmt.Sprintf("migration %s succeeded", ss.Status.LastSucceededMigration)
+		}
+		controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+			Type:           migrationv1alpha1.StorageStateMigrated,
+			Status:         corev1.ConditionTrue,
+			LastUpdateTime: now,
+			Reason:         "StorageVersionMigrated",
+			Message:        message,
+		})
+		return
+	}
+	controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+		Type:           migrationv1alpha1.StorageStateMigrated,
+		Status:         corev1.ConditionFalse,
+		LastUpdateTime: now,
+		Reason:         "StorageVersionNotMigrated",
+		Message:        fmt.Sprintf("objects might be encoded in storage versions %v", ss.Status.PersistedStorageVersionHashes),
+	})
+}
+
+// setMigrationInProgressCondition sets the MigrationInProgress condition of
+// the storageState. m is the pending or running migration of the resource,
+// or nil if there is none.
+func setMigrationInProgressCondition(ss *migrationv1alpha1.StorageState, m *migrationv1alpha1.StorageVersionMigration, now metav1.Time) {
+	if m == nil {
+		controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+			Type:           migrationv1alpha1.StorageStateMigrationInProgress,
+			Status:         corev1.ConditionFalse,
+			LastUpdateTime: now,
+			Reason:         "NoMigration",
+			Message:        "no migration is pending or running",
+		})
+		return
+	}
+	reason, message := "MigrationPending", fmt.Sprintf("migration %s is pending", m.Name)
+	if controller.HasCondition(m, migrationv1alpha1.MigrationRunning) {
+		reason, message = "MigrationRunning", fmt.Sprintf("migration %s is running", m.Name)
+	}
+	controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+		Type:           migrationv1alpha1.StorageStateMigrationInProgress,
+		Status:         corev1.ConditionTrue,
+		LastUpdateTime: now,
+		Reason:         reason,
+		Message:        message,
+	})
+}
+
+// setMigrationRequiredCondition sets the MigrationRequired condition of the
+// storageState. A migration is required if the resource is not migrated,
+// and no migration of it is pending or running. inProgress is the pending
+// or running migration of the resource, or nil if there is none. reason and
+// message explain why the migration is required, they are kept if the
+// condition is already true.
+func (mt *MigrationTrigger) setMigrationRequiredCondition(ss *migrationv1alpha1.StorageState, inProgress *migrationv1alpha1.StorageVersionMigration, reason, message string, now metav1.Time) {
+	switch {
+	case mt.isMigrated(ss):
+		controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+			Type:           migrationv1alpha1.StorageStateMigrationRequired,
+			Status:         corev1.ConditionFalse,
+			LastUpdateTime: now,
+			Reason:         "StorageVersionMigrated",
+			Message:        "all objects are encoded in the current storage version",
+		})
+	case inProgress != nil:
+		controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+			Type:           migrationv1alpha1.StorageStateMigrationRequired,
+			Status:         corev1.ConditionFalse,
+			LastUpdateTime: now,
+			Reason:         "MigrationInProgress",
+			Message:        fmt.Sprintf("migration %s is pending or running", inProgress.Name),
+		})
+	case controller.HasStorageStateCondition(ss, migrationv1alpha1.StorageStateMigrationRequired):
+	default:
+		if reason == "" {
+			reason = migrationv1alpha1.MigrationReasonMigrationMissing
+			message = "the resource is not migrated, and has no pending or running migration"
+		}
+		controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+			Type:           migrationv1alpha1.StorageStateMigrationRequired,
+			Status:         corev1.ConditionTrue,
+			LastUpdateTime: now,
+			Reason:         reason,
+			Message:        message,
+		})
+	}
+}
+
+// reportStorageState updates the metrics of the resource of the storageState,
+// and records events when the storageState becomes migrated or starts
+// requiring a migration. old is the storageState before the update, or nil
+// if it was just created.
+func (mt *MigrationTrigger) reportStorageState(old, ss *migrationv1alpha1.StorageState) {
+	required := controller.HasStorageStateCondition(ss, migrationv1alpha1.StorageStateMigrationRequired)
+	migrated := controller.HasStorageStateCondition(ss, migrationv1alpha1.StorageStateMigrated)
+	metrics.Metrics.ObserveMigrationRequired(ss.Name, required)
+	metrics.Metrics.ObserveMigrated(ss.Name, migrated)
+	if mt.options.EventRecorder == nil {
+		return
+	}
+	if required && (old == nil || !controller.HasStorageStateCondition(old, migrationv1alpha1.StorageStateMigrationRequired)) {
+		c := controller.GetStorageStateCondition(ss, migrationv1alpha1.StorageStateMigrationRequired)
+		mt.options.EventRecorder.Eventf(ss, corev1.EventTypeNormal, c.Reason, "Migration required: %s", c.Message)
+	}
+	if migrated && (old == nil || !controller.HasStorageStateCondition(old, migrationv1alpha1.StorageStateMigrated)) {
+		c := controller.GetStorageStateCondition(ss, migrationv1alpha1.StorageStateMigrated)
+		mt.options.EventRecorder.Eventf(ss, corev1.EventTypeNormal, "Migrated", "Storage version migrated: %s", c.Message)
+	}
+}
+
+// setStaleCondition sets the Stale condition of the storageState. The
+// resource is absent from the discovery document if stale is true.
+func (mt *MigrationTrigger) setStaleCondition(ss *migrationv1alpha1.StorageState, stale bool, now metav1.Time) {
+	if !stale {
+		controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+			Type:           migrationv1alpha1.StorageStateStale,
+			Status:         corev1.ConditionFalse,
+			LastUpdateTime: now,
+			Reason:         "ResourceDiscovered",
+			Message:        "the resource is in the discovery document",
+		})
+		return
+	}
+	message := fmt.Sprintf("the resource has been absent from the discovery document since %s", ss.Status.LastHeartbeatTime.Format(time.RFC3339))
+	if mt.options.StorageStateGracePeriod > 0 {
+		message += fmt.Sprintf(", the storageState is garbage collected at %s", ss.Status.LastHeartbeatTime.Add(mt.options.StorageStateGracePeriod).Format(time.RFC3339))
+	}
+	controller.SetStorageStateCondition(ss, migrationv1alpha1.StorageStateCondition{
+		Type:           migrationv1alpha1.StorageStateStale,
+		Status:         corev1.ConditionTrue,
+		LastUpdateTime: now,
+		Reason:         "ResourceNotDiscovered",
+		Message:        message,
+	})
+}
+
+// markStorageStateInProgress sets the MigrationInProgress condition of the
+// storageState of the resource of the unfinished migration m.
+func (mt *MigrationTrigger) markStorageStateInProgress(ctx context.Context, m *migrationv1alpha1.StorageVersionMigration) error {
+	ss, err := mt.client.MigrationV1alpha1().StorageStates().Get(ctx, controller.StorageStateName(m.Spec.Resource), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// The next discovery routine creates the storage state.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	now := metav1.Now()
+	updated := ss.DeepCopy()
+	setMigrationInProgressCondition(updated, m, now)
+	mt.setMigrationRequiredCondition(updated, m, "", "", now)
+	if reflect.DeepEqual(ss.Status.Conditions, updated.Status.Conditions) {
+		return nil
+	}
+	updated, err = mt.client.MigrationV1alpha1().StorageStates().UpdateStatus(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	mt.reportStorageState(ss, updated)
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+)
+
+func expectStorageStateCondition(t *testing.T, ss *v1alpha1.StorageState, conditionType v1alpha1.StorageStateConditionType, status v1.ConditionStatus, reason string) {
+	t.Helper()
+	for _, c := range ss.Status.Conditions {
+		if c.Type != conditionType {
+			continue
+		}
+		if c.Status != status || c.Reason != reason {
+			t.Errorf("expected condition %s to be %s with reason %s, got %s with reason %s", conditionType, status, reason, c.Status, c.Reason)
+		}
+		return
+	}
+	t.Errorf("expected condition %s, got %v", conditionType, ss.Status.Conditions)
+}
+
+func TestRecordStorageVersionHashHistory(t *testing.T) {
+	t0 := metav1.NewTime(time.Now().Add(-time.Hour))
+	t1 := metav1.NewTime(t0.Add(time.Minute))
+	t2 := metav1.NewTime(t1.Add(time.Minute))
+	ss := storageState(withPersistedVersions(v1alpha1.Unknown))
+	recordStorageVersionHashHistory(ss, t0)
+	ss.Status.PersistedStorageVersionHashes = []string{v1alpha1.Unknown, "newhash"}
+	recordStorageVersionHashHistory(ss, t1)
+	ss.Status.PersistedStorageVersionHashes = []string{"newhash"}
+	recordStorageVersionHashHistory(ss, t2)
+	// Recording again changes nothing.
+	recordStorageVersionHashHistory(ss, metav1.Now())
+
+	history := ss.Status.StorageVersionHashHistory
+	if len(history) != 2 {
+		t.Fatalf("expected 2 records, got %v", history)
+	}
+	if r := history[0]; r.Hash != v1alpha1.Unknown || !r.FirstObservedTime.Equal(&t0) || r.RemovedTime == nil || !r.RemovedTime.Equal(&t2) {
+		t.Errorf("unexpected record %v", r)
+	}
+	if r := history[1]; r.Hash != "newhash" || !r.FirstObservedTime.Equal(&t1) || r.RemovedTime != nil {
+		t.Errorf("unexpected record %v", r)
+	}
+
+	// The oldest removed hashes are dropped.
+	for i := 0; i < maxStorageVersionHashHistory; i++ {
+		ss.Status.PersistedStorageVersionHashes = []string{fmt.Sprintf("hash%d", i)}
+		recordStorageVersionHashHistory(ss, metav1.Now())
+	}
+	history = ss.Status.StorageVersionHashHistory
+	if len(history) != maxStorageVersionHashHistory {
+		t.Fatalf("expected %d records, got %d", maxStorageVersionHashHistory, len(history))
+	}
+	if history[0].Hash != "hash0" || history[len(history)-1].Hash != fmt.Sprintf("hash%d", maxStorageVersionHashHistory-1) {
+		t.Errorf("unexpected history %v", history)
+	}
+}
+
+func TestProcessDiscoveryResourceConditions(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go trigger.migrationInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+		return
+	}
+	trigger.heartbeat = metav1.Now()
+	trigger.processDiscoveryResource(context.TODO(), newAPIResource())
+	ss := lastStorageStateUpdate(t, client.Actions())
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrated, v1.ConditionFalse, "StorageVersionNotMigrated")
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationInProgress, v1.ConditionTrue, "MigrationPending")
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateStale, v1.ConditionFalse, "ResourceDiscovered")
+	if len(ss.Status.StorageVersionHashHistory) != 1 || ss.Status.StorageVersionHashHistory[0].Hash != v1alpha1.Unknown {
+		t.Errorf("unexpected history %v", ss.Status.StorageVersionHashHistory)
+	}
+}
+
+func TestProcessMigrationSucceededStatus(t *testing.T) {
+	succeeded := storageMigration(withName("pods-1"), withSucceededCondition(metav1.Now()))
+	client := fake.NewSimpleClientset(
+		succeeded,
+		storageState(withCurrentVersion("newhash"), withPersistedVersions("oldhash", "newhash")),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go trigger.migrationInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+		return
+	}
+	if err := trigger.processMigration(context.TODO(), succeeded); err != nil {
+		t.Fatal(err)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	if ss.Status.LastSucceededMigration != "pods-1" {
+		t.Errorf("expected last succeeded migration pods-1, got %q", ss.Status.LastSucceededMigration)
+	}
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrated, v1.ConditionTrue, "StorageVersionMigrated")
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationInProgress, v1.ConditionFalse, "NoMigration")
+}
+
+func TestProcessMigrationFailedStatus(t *testing.T) {
+	failed := storageMigration(withName("pods-1"), withFailedCondition())
+	failed.Status.Conditions[0].Message = "boom"
+	client := fake.NewSimpleClientset(
+		failed,
+		storageState(withCurrentVersion("newhash"), withPersistedVersions("oldhash", "newhash")),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	defer trigger.queue.ShutDown()
+	if err := trigger.processMigration(context.TODO(), failed); err != nil {
+		t.Fatal(err)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	if ss.Status.LastMigrationFailureMessage != "boom" {
+		t.Errorf("expected failure message boom, got %q", ss.Status.LastMigrationFailureMessage)
+	}
+}
+
+func TestProcessMigrationInProgressStatus(t *testing.T) {
+	running := storageMigration(withName("pods-1"))
+	running.Status.Conditions = []v1alpha1.MigrationCondition{{Type: v1alpha1.MigrationRunning, Status: v1.ConditionTrue}}
+	client := fake.NewSimpleClientset(
+		running,
+		storageState(withCurrentVersion("newhash"), withPersistedVersions("oldhash", "newhash")),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	if err := trigger.processMigration(context.TODO(), running); err != nil {
+		t.Fatal(err)
+	}
+	ss := lastStorageStateUpdate(t, client.Actions())
+	expectStorageStateCondition(t, ss, v1alpha1.StorageStateMigrationInProgress, v1.ConditionTrue, "MigrationRunning")
+
+	// The condition is not updated again.
+	client.ClearActions()
+	if err := trigger.processMigration(context.TODO(), running); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.Actions()) != 1 {
+		t.Errorf("expected only a get, got %v", client.Actions())
+	}
+}
@@ -0,0 +1,206 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	migrationv1alpha1 "sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/controller"
+)
+
+const (
+	// maxBlockingResources limits the number of resources listed in the
+	// status of the migration summary.
+	maxBlockingResources = 100
+	// maxReportedResources limits the number of resources listed in the
+	// messages of the conditions of the migration summary.
+	maxReportedResources = 5
+)
+
+// StatusReporter reports the migration summary.
+type StatusReporter interface {
+	Report(ctx context.Context, summary migrationv1alpha1.MigrationSummaryStatus) error
+}
+
+// updateSummary recomputes the migration summary from the storageStates and
+// the storageVersionMigrations, reports it to the StatusReporter, and
+// creates or updates the MigrationSummary, unless its CRD is not installed.
+func (mt *MigrationTrigger) updateSummary(ctx context.Context) {
+	l, err := mt.client.MigrationV1alpha1().StorageStates().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to list the storageStates for the migration summary: %v", err))
+		return
+	}
+	summary, err := mt.client.MigrationV1alpha1().MigrationSummaries().Get(ctx, migrationv1alpha1.MigrationSummaryName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		summary, err = mt.client.MigrationV1alpha1().MigrationSummaries().Create(ctx, &migrationv1alpha1.MigrationSummary{
+			ObjectMeta: metav1.ObjectMeta{Name: migrationv1alpha1.MigrationSummaryName},
+		}, metav1.CreateOptions{})
+		if errors.IsNotFound(err) {
+			klog.V(4).Infof("the MigrationSummary CRD is not installed, the migration summary is not updated")
+			summary, err = nil, nil
+		}
+	}
+	if err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to get the migration summary: %v", err))
+		return
+	}
+	var conditions []migrationv1alpha1.MigrationSummaryCondition
+	if summary != nil {
+		conditions = summary.Status.Conditions
+	}
+	status := mt.summaryStatus(l.Items, conditions)
+	if mt.options.StatusReporter != nil {
+		if err := mt.options.StatusReporter.Report(ctx, status); err != nil {
+			utilruntime.HandleError(fmt.Errorf("failed to report the migration summary: %v", err))
+		}
+	}
+	if summary == nil {
+		return
+	}
+	// The update time only changes with the rest of the status.
+	status.LastUpdateTime = summary.Status.LastUpdateTime
+	if equality.Semantic.DeepEqual(summary.Status, status) {
+		return
+	}
+	status.LastUpdateTime = metav1.Now()
+	summary = summary.DeepCopy()
+	summary.Status = status
+	if _, err := mt.client.MigrationV1alpha1().MigrationSummaries().UpdateStatus(ctx, summary, metav1.UpdateOptions{}); err != nil {
+		utilruntime.HandleError(fmt.Errorf("failed to update the migration summary: %v", err))
+	}
+}
+
+// summaryStatus returns the status of the migration summary of the
+// storageStates. The conditions are updated in place of the existing ones.
+// The resources the trigger does not migrate, because they are skipped by
+// the policies, excluded by the resource rules or migrated by an active
+// plan, are only counted as excluded, so that they never block the summary.
+func (mt *MigrationTrigger) summaryStatus(states []migrationv1alpha1.StorageState, conditions []migrationv1alpha1.MigrationSummaryCondition) migrationv1alpha1.MigrationSummaryStatus {
+	sort.Slice(states, func(i, j int) bool {
+		a, b := states[i].Spec.Resource, states[j].Spec.Resource
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		return a.Resource < b.Resource
+	})
+	status := migrationv1alpha1.MigrationSummaryStatus{
+		Conditions: conditions,
+	}
+	var blocking []migrationv1alpha1.BlockingResource
+	var failing, stale []string
+	for i := range states {
+		ss := &states[i]
+		name := controller.ResourceName(ss.Spec.Resource.Group, ss.Spec.Resource.Resource)
+		if mt.staleStorageState(ss) {
+			status.StaleResources++
+			stale = append(stale, name)
+		}
+		r := migrationv1alpha1.GroupVersionResource{Group: ss.Spec.Resource.Group, Resource: ss.Spec.Resource.Resource}
+		if allowed, _ := mt.migrationAllowed(r); !allowed || mt.plannedBy(r) != "" {
+			status.ExcludedResources++
+			continue
+		}
+		status.Resources++
+		b := migrationv1alpha1.BlockingResource{Resource: ss.Spec.Resource}
+		switch {
+		case mt.hasPendingOrRunningMigration(r):
+			status.InProgressResources++
+			b.Reason = migrationv1alpha1.BlockingReasonMigrationInProgress
+			b.Message = fmt.Sprintf("the migration %s is pending or running", mt.unfinishedMigration(r).Name)
+		case mt.isMigrated(ss):
+			status.MigratedResources++
+			continue
+		case ss.Status.FailedMigrationAttempts > 0:
+			status.FailingResources++
+			failing = append(failing, name)
+			b.Reason = migrationv1alpha1.BlockingReasonMigrationFailing
+			b.Message = fmt.Sprintf("the migration %s failed: %s", ss.Status.LastFailedMigration, ss.Status.LastMigrationFailureMessage)
+			if c := controller.GetStorageStateCondition(ss, migrationv1alpha1.StorageStateGaveUp); c != nil {
+				b.Message = c.Message
+			}
+		default:
+			b.Reason = migrationv1alpha1.BlockingReasonMigrationRequired
+			if c := controller.GetStorageStateCondition(ss, migrationv1alpha1.StorageStateMigrationRequired); c != nil {
+				b.Message = c.Message
+			}
+		}
+		blocking = append(blocking, b)
+	}
+	if len(blocking) > maxBlockingResources {
+		blocking = blocking[:maxBlockingResources]
+	}
+	status.BlockingResources = blocking
+
+	migrated := migrationv1alpha1.MigrationSummaryCondition{
+		Type:    migrationv1alpha1.MigrationSummaryAllResourcesMigrated,
+		Status:  corev1.ConditionTrue,
+		Reason:  "AllResourcesMigrated",
+		Message: fmt.Sprintf("%d of %d resources are migrated", status.MigratedResources, status.Resources),
+	}
+	if status.MigratedResources != status.Resources {
+		migrated.Status = corev1.ConditionFalse
+		migrated.Reason = "ResourcesNotMigrated"
+	}
+	failingCondition := migrationv1alpha1.MigrationSummaryCondition{
+		Type:   migrationv1alpha1.MigrationSummaryMigrationsFailing,
+		Status: corev1.ConditionFalse,
+		Reason: "NoFailingMigrations",
+	}
+	if len(failing) > 0 {
+		failingCondition.Status = corev1.ConditionTrue
+		failingCondition.Reason = "MigrationsFailed"
+		failingCondition.Message = "the last migrations of " + resourcesMessage(failing) + " failed"
+	}
+	staleCondition := migrationv1alpha1.MigrationSummaryCondition{
+		Type:   migrationv1alpha1.MigrationSummaryDiscoveryStale,
+		Status: corev1.ConditionFalse,
+		Reason: "DiscoveryUpToDate",
+	}
+	switch {
+	case mt.discoveryFailure != "":
+		staleCondition.Status = corev1.ConditionTrue
+		staleCondition.Reason = "DiscoveryFailed"
+		staleCondition.Message = mt.discoveryFailure
+	case len(stale) > 0:
+		staleCondition.Status = corev1.ConditionTrue
+		staleCondition.Reason = "StaleHeartbeat"
+		staleCondition.Message = "the storageStates of " + resourcesMessage(stale) + " have stale heartbeats"
+	}
+	for _, c := range []migrationv1alpha1.MigrationSummaryCondition{migrated, failingCondition, staleCondition} {
+		c.LastUpdateTime = metav1.Now()
+		status.Conditions = controller.SetSummaryCondition(status.Conditions, c)
+	}
+	return status
+}
+
+func resourcesMessage(resources []string) string {
+	if len(resources) <= maxReportedResources {
+		return strings.Join(resources, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(resources[:maxReportedResources], ", "), len(resources)-maxReportedResources)
+}
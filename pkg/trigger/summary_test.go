@@ -0,0 +1,200 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trigger
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/apis/migration/v1alpha1"
+	"sigs.k8s.io/kube-storage-version-migrator/pkg/clients/clientset/fake"
+)
+
+func getSummary(t *testing.T, client *fake.Clientset) *v1alpha1.MigrationSummary {
+	summary, err := client.MigrationV1alpha1().MigrationSummaries().Get(context.TODO(), v1alpha1.MigrationSummaryName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return summary
+}
+
+func expectSummaryCondition(t *testing.T, summary *v1alpha1.MigrationSummary, conditionType v1alpha1.MigrationSummaryConditionType, status v1.ConditionStatus, message string) {
+	t.Helper()
+	for _, c := range summary.Status.Conditions {
+		if c.Type != conditionType {
+			continue
+		}
+		if c.Status != status || c.Message != message {
+			t.Errorf("expected %s condition %s %q, got %s %q", conditionType, status, message, c.Status, c.Message)
+		}
+		return
+	}
+	t.Errorf("expected %s condition, got %v", conditionType, summary.Status.Conditions)
+}
+
+func TestUpdateSummary(t *testing.T) {
+	now := metav1.Now()
+	widgets := v1alpha1.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"}
+	client := fake.NewSimpleClientset(
+		// migrated
+		storageState(withHeartbeat(now), withCurrentVersion("v1"), withPersistedVersions("v1")),
+		// migrating
+		storageState(withStorageStateResource("example.com", "widgets"), withHeartbeat(now), withCurrentVersion("v2"), withPersistedVersions("v1", "v2")),
+		// failed
+		storageState(withStorageStateResource("example.com", "gadgets"), withHeartbeat(now), withCurrentVersion("v2"), withPersistedVersions("v1", "v2"),
+			withFailedAttempts(3, "gadgets-migration", now.Time), func(ss *v1alpha1.StorageState) {
+				ss.Status.LastMigrationFailureMessage = "conflict"
+			}),
+		// migrated, with a stale heartbeat
+		storageState(withStorageStateResource("apps", "deployments"), withStaleHeartbeat(), withCurrentVersion("v1"), withPersistedVersions("v1")),
+		storageMigration(withName("widgets-migration"), withResource(widgets)),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go trigger.migrationInformer.Run(stopCh)
+	if !cache.WaitForCacheSync(stopCh, trigger.migrationInformer.HasSynced) {
+		utilruntime.HandleError(fmt.Errorf("Unable to sync caches"))
+		return
+	}
+	trigger.heartbeat = now
+	trigger.updateSummary(context.TODO())
+
+	summary := getSummary(t, client)
+	status := summary.Status
+	if status.Resources != 4 || status.MigratedResources != 2 || status.InProgressResources != 1 || status.FailingResources != 1 || status.StaleResources != 1 {
+		t.Errorf("unexpected counts %+v", status)
+	}
+	expected := []v1alpha1.BlockingResource{
+		{
+			Resource: v1alpha1.GroupResource{Group: "example.com", Resource: "gadgets"},
+			Reason:   v1alpha1.BlockingReasonMigrationFailing,
+			Message:  "the migration gadgets-migration failed: conflict",
+		},
+		{
+			Resource: v1alpha1.GroupResource{Group: "example.com", Resource: "widgets"},
+			Reason:   v1alpha1.BlockingReasonMigrationInProgress,
+			Message:  "the migration widgets-migration is pending or running",
+		},
+	}
+	if !reflect.DeepEqual(status.BlockingResources, expected) {
+		t.Errorf("expected blocking resources %+v, got %+v", expected, status.BlockingResources)
+	}
+	expectSummaryCondition(t, summary, v1alpha1.MigrationSummaryAllResourcesMigrated, v1.ConditionFalse, "2 of 4 resources are migrated")
+	expectSummaryCondition(t, summary, v1alpha1.MigrationSummaryMigrationsFailing, v1.ConditionTrue, "the last migrations of gadgets.example.com failed")
+	expectSummaryCondition(t, summary, v1alpha1.MigrationSummaryDiscoveryStale, v1.ConditionTrue, "the storageStates of deployments.apps have stale heartbeats")
+
+	// The summary is not updated if nothing changed.
+	client.ClearActions()
+	trigger.updateSummary(context.TODO())
+	for _, a := range client.Actions() {
+		if a.GetVerb() == "update" {
+			t.Errorf("expected no update, got %v", a)
+		}
+	}
+
+	trigger.discoveryFailure = "the last discovery failed: boom"
+	trigger.updateSummary(context.TODO())
+	expectSummaryCondition(t, getSummary(t, client), v1alpha1.MigrationSummaryDiscoveryStale, v1.ConditionTrue, "the last discovery failed: boom")
+}
+
+func TestUpdateSummaryAllMigrated(t *testing.T) {
+	now := metav1.Now()
+	client := fake.NewSimpleClientset(
+		storageState(withHeartbeat(now), withCurrentVersion("v1"), withPersistedVersions("v1")),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	trigger.heartbeat = now
+	trigger.updateSummary(context.TODO())
+
+	summary := getSummary(t, client)
+	if len(summary.Status.BlockingResources) != 0 {
+		t.Errorf("expected no blocking resources, got %v", summary.Status.BlockingResources)
+	}
+	expectSummaryCondition(t, summary, v1alpha1.MigrationSummaryAllResourcesMigrated, v1.ConditionTrue, "1 of 1 resources are migrated")
+	expectSummaryCondition(t, summary, v1alpha1.MigrationSummaryMigrationsFailing, v1.ConditionFalse, "")
+	expectSummaryCondition(t, summary, v1alpha1.MigrationSummaryDiscoveryStale, v1.ConditionFalse, "")
+}
+
+func TestUpdateSummaryExcludesSkippedResources(t *testing.T) {
+	now := metav1.Now()
+	skip := newPodsPolicy("skip-pods", 0)
+	skip.Spec.Action = v1alpha1.MigrationPolicyActionSkip
+	client := fake.NewSimpleClientset(
+		skip,
+		// not migrated, but skipped by the policy
+		storageState(withHeartbeat(now), withCurrentVersion("v2"), withPersistedVersions("v1", "v2")),
+		storageState(withStorageStateResource("apps", "deployments"), withHeartbeat(now), withCurrentVersion("v1"), withPersistedVersions("v1")),
+	)
+	trigger := NewMigrationTrigger(client, DefaultOptions())
+	trigger.heartbeat = now
+	trigger.refreshPolicies(context.TODO())
+	trigger.updateSummary(context.TODO())
+
+	summary := getSummary(t, client)
+	status := summary.Status
+	if status.Resources != 1 || status.ExcludedResources != 1 || status.MigratedResources != 1 {
+		t.Errorf("unexpected counts %+v", status)
+	}
+	if len(status.BlockingResources) != 0 {
+		t.Errorf("expected no blocking resources, got %v", status.BlockingResources)
+	}
+	expectSummaryCondition(t, summary, v1alpha1.MigrationSummaryAllResourcesMigrated, v1.ConditionTrue, "1 of 1 resources are migrated")
+}
+
+type fakeStatusReporter struct {
+	reported []v1alpha1.MigrationSummaryStatus
+}
+
+func (r *fakeStatusReporter) Report(ctx context.Context, summary v1alpha1.MigrationSummaryStatus) error {
+	r.reported = append(r.reported, summary)
+	return nil
+}
+
+func TestUpdateSummaryStatusReporter(t *testing.T) {
+	now := metav1.Now()
+	client := fake.NewSimpleClientset(
+		storageState(withHeartbeat(now), withCurrentVersion("v1"), withPersistedVersions("v1")),
+	)
+	// The MigrationSummary CRD is not installed.
+	client.PrependReactor("*", "migrationsummaries", func(action core.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.NewNotFound(v1alpha1.Resource("migrationsummaries"), v1alpha1.MigrationSummaryName)
+	})
+	reporter := &fakeStatusReporter{}
+	options := DefaultOptions()
+	options.StatusReporter = reporter
+	trigger := NewMigrationTrigger(client, options)
+	trigger.heartbeat = now
+	trigger.updateSummary(context.TODO())
+
+	if len(reporter.reported) != 1 {
+		t.Fatalf("expected the summary to be reported once, got %d", len(reporter.reported))
+	}
+	if s := reporter.reported[0]; s.Resources != 1 || s.MigratedResources != 1 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+}
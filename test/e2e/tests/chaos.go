@@ -236,7 +236,7 @@ func (t *StorageMigratorChaosTest) Test(done <-chan struct{}) {
 		util.Failf("%v", err)
 	}
 	for _, m := range l.Items {
-		if !succeeded(m.Status.Conditions) {
+		if !succeeded(m.Status.Conditions) && !superseded(m.Status.Conditions) {
 			util.Failf("unexpected in progress migration for resource %v", m.Spec.Resource)
 		}
 	}
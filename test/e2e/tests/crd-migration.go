@@ -24,8 +24,16 @@ const (
 )
 
 func succeeded(conditions []migrationv1alpha1.MigrationCondition) bool {
+	return hasCondition(conditions, migrationv1alpha1.MigrationSucceeded)
+}
+
+func superseded(conditions []migrationv1alpha1.MigrationCondition) bool {
+	return hasCondition(conditions, migrationv1alpha1.MigrationSuperseded)
+}
+
+func hasCondition(conditions []migrationv1alpha1.MigrationCondition, conditionType migrationv1alpha1.MigrationConditionType) bool {
 	for _, c := range conditions {
-		if c.Type == migrationv1alpha1.MigrationSucceeded && c.Status == corev1.ConditionTrue {
+		if c.Type == conditionType && c.Status == corev1.ConditionTrue {
 			return true
 		}
 	}
@@ -179,7 +187,7 @@ var _ = Describe("storage version migrator", func() {
 			util.Failf("%v", err)
 		}
 		for _, m := range l.Items {
-			if !succeeded(m.Status.Conditions) {
+			if !succeeded(m.Status.Conditions) && !superseded(m.Status.Conditions) {
 				util.Failf("unexpected in progress migration for resource %v", m.Spec.Resource)
 			}
 		}
@@ -12,7 +12,7 @@ func setupMigrator() {
 	// CRD in the first round of discovery.
 	testCRD := "../../test/e2e/crd.yaml"
 	// setup the migration system
-	crds := []string{"../../manifests.local/storage_migration_crd.yaml", "../../manifests.local/storage_state_crd.yaml"}
+	crds := []string{"../../manifests.local/storage_migration_crd.yaml", "../../manifests.local/storage_state_crd.yaml", "../../manifests.local/migration_policy_crd.yaml", "../../manifests.local/migration_plan_crd.yaml", "../../manifests.local/migration_summary_crd.yaml"}
 	rbacs := "../../manifests.local/namespace-rbac.yaml"
 	trigger := "../../manifests.local/trigger.yaml"
 	migrator := "../../manifests.local/migrator.yaml"
@@ -20,7 +20,7 @@ func setupMigrator() {
 	if err != nil {
 		util.Failf("%s", output)
 	}
-	output, err = exec.Command("kubectl", "apply", "-f", crds[0], "-f", crds[1]).CombinedOutput()
+	output, err = exec.Command("kubectl", "apply", "-f", crds[0], "-f", crds[1], "-f", crds[2], "-f", crds[3], "-f", crds[4]).CombinedOutput()
 	if err != nil {
 		util.Failf("%s", output)
 	}